@@ -0,0 +1,88 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// anonymousModelName is the name given to anonymized models.
+const anonymousModelName = "model"
+
+// Anonymization maps the generic names of an anonymized model back to the
+// original ones.
+type Anonymization struct {
+	Name        string
+	Variables   map[string]string
+	Constraints map[string]string
+}
+
+// Anonymize returns a copy of the model where the model, variables and
+// constraints are named generically ("model", x1, x2, ..., c1, c2, ...),
+// along with the mapping back to the original names. The copy can be
+// exported and shared without revealing the meaning of the model; the
+// original model is left untouched.
+func (model *Model) Anonymize() (*Model, Anonymization) {
+	anon := model.Clone()
+
+	anon.mu.Lock()
+	defer anon.mu.Unlock()
+
+	a := Anonymization{
+		Name:        C.GoString(C.get_lp_name(anon.prob)),
+		Variables:   make(map[string]string, len(anon.vars)),
+		Constraints: make(map[string]string, len(anon.cons)),
+	}
+
+	setName(anonymousModelName, func(name *C.char) { C.set_lp_name(anon.prob, name) })
+
+	if anon.names != nil {
+		anon.names = make(map[string]bool, len(anon.vars))
+	}
+	for i, v := range anon.vars {
+		col := C.int(v.index + 1)
+		name := fmt.Sprintf("x%d", i+1)
+		if anon.names != nil {
+			anon.names[name] = true
+		}
+		a.Variables[name] = anon.colName(int(col))
+		setName(name, func(name *C.char) { C.set_col_name(anon.prob, col, name) })
+	}
+
+	for i, c := range anon.cons {
+		row := C.int(c.index + 1)
+		name := fmt.Sprintf("c%d", i+1)
+		a.Constraints[name] = C.GoString(C.get_row_name(anon.prob, row))
+		setName(name, func(name *C.char) { C.set_row_name(anon.prob, row, name) })
+	}
+
+	return anon, a
+}
+
+// setName calls set with name converted to a C string.
+func setName(name string, set func(*C.char)) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	set(cName)
+}
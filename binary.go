@@ -0,0 +1,358 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// The binary model format consists of a fixed-size header followed by
+// sections of fixed-size little-endian values, each padded to a multiple of
+// 8 bytes, so it can be memory-mapped and read without parsing:
+//
+//	header       binaryMagic, version uint32, flags uint32, then the counts
+//	             vars, cons, nonzeros and the lengths of the model name,
+//	             variable names and constraint names as uint64
+//	model name   bytes
+//	variables    types [vars]uint8, lower, upper and objective
+//	             [vars]float64 each
+//	var names    offsets [vars+1]uint64, bytes
+//	constraints  lower, upper [cons]float64, row starts [cons+1]uint64
+//	con names    offsets [cons+1]uint64, bytes (empty for default names)
+//	nonzeros     columns [nonzeros]uint32, coefficients [nonzeros]float64
+const (
+	binaryMagic      = "GOLPABIN"
+	binaryVersion    = 1
+	binaryHeaderSize = 64
+
+	binaryMaximize = 1 << 0
+)
+
+// errBinaryTruncated is returned for binary models shorter than their
+// header announces.
+var errBinaryTruncated = errors.New("invalid binary model: truncated")
+
+// WriteBinary writes the model in a compact binary format, which can be
+// loaded much faster than text formats with OpenBinary. Named objectives
+// are not included.
+func (model *Model) WriteBinary(w io.Writer) error {
+	data := model.readData()
+
+	var nnz uint64
+	for _, c := range data.cons {
+		nnz += uint64(len(c.cols))
+	}
+	var varNames, conNames uint64
+	for _, v := range data.vars {
+		varNames += uint64(len(v.name))
+	}
+	for _, c := range data.cons {
+		conNames += uint64(len(c.name))
+	}
+
+	bw := &binaryWriter{w: bufio.NewWriter(w)}
+
+	var flags uint32
+	if data.maximize {
+		flags |= binaryMaximize
+	}
+	bw.bytes([]byte(binaryMagic))
+	bw.uint32s(binaryVersion, flags)
+	bw.uint64s(uint64(len(data.vars)), uint64(len(data.cons)), nnz, uint64(len(data.name)), varNames, conNames)
+
+	bw.bytes([]byte(data.name))
+
+	types := make([]byte, len(data.vars))
+	values := make([]float64, len(data.vars))
+	for i, v := range data.vars {
+		types[i] = byte(v.typ)
+	}
+	bw.bytes(types)
+	for _, get := range []func(varData) float64{
+		func(v varData) float64 { return v.lower },
+		func(v varData) float64 { return v.upper },
+		func(v varData) float64 { return v.obj },
+	} {
+		for i, v := range data.vars {
+			values[i] = get(v)
+		}
+		bw.float64s(values...)
+	}
+
+	offsets := make([]uint64, 1, len(data.vars)+1)
+	names := make([]byte, 0, varNames)
+	for _, v := range data.vars {
+		names = append(names, v.name...)
+		offsets = append(offsets, uint64(len(names)))
+	}
+	bw.uint64s(offsets...)
+	bw.bytes(names)
+
+	values = make([]float64, len(data.cons))
+	for _, get := range []func(conData) float64{
+		func(c conData) float64 { return c.lower },
+		func(c conData) float64 { return c.upper },
+	} {
+		for i, c := range data.cons {
+			values[i] = get(c)
+		}
+		bw.float64s(values...)
+	}
+
+	starts := make([]uint64, 1, len(data.cons)+1)
+	for _, c := range data.cons {
+		starts = append(starts, starts[len(starts)-1]+uint64(len(c.cols)))
+	}
+	bw.uint64s(starts...)
+
+	offsets = make([]uint64, 1, len(data.cons)+1)
+	names = make([]byte, 0, conNames)
+	for _, c := range data.cons {
+		names = append(names, c.name...)
+		offsets = append(offsets, uint64(len(names)))
+	}
+	bw.uint64s(offsets...)
+	bw.bytes(names)
+
+	cols := make([]uint32, 0, nnz)
+	coefs := make([]float64, 0, nnz)
+	for _, c := range data.cons {
+		for i, col := range c.cols {
+			cols = append(cols, uint32(col))
+			coefs = append(coefs, c.coefs[i])
+		}
+	}
+	bw.uint32s(cols...)
+	bw.float64s(coefs...)
+
+	return bw.flush()
+}
+
+// OpenBinary loads a model written by WriteBinary from the given file. The
+// file is memory-mapped where supported, so it is never copied into Go
+// memory as a whole, unless it is gzip-compressed.
+func OpenBinary(path string, opts ...Option) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, unmap, err := mapFile(f)
+	if err != nil {
+		return nil, err
+	}
+	defer unmap()
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		r, err := Decompress(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return readBinary(data, opts...)
+}
+
+// readBinary builds a model from the binary format.
+func readBinary(data []byte, opts ...Option) (*Model, error) {
+	if len(data) < binaryHeaderSize || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, fmt.Errorf("invalid binary model: bad header")
+	}
+	le := binary.LittleEndian
+	if version := le.Uint32(data[8:]); version != binaryVersion {
+		return nil, fmt.Errorf("unsupported binary model version %d", version)
+	}
+	flags := le.Uint32(data[12:])
+	counts := make([]uint64, 6)
+	for i := range counts {
+		counts[i] = le.Uint64(data[16+8*i:])
+	}
+	nvars, ncons, nnz := counts[0], counts[1], counts[2]
+
+	br := &binaryReader{data: data, off: binaryHeaderSize}
+	name := br.section(counts[3], 1)
+	types := br.section(nvars, 1)
+	lower, upper, obj := br.section(nvars, 8), br.section(nvars, 8), br.section(nvars, 8)
+	varOffsets, varNames := br.section(nvars+1, 8), br.section(counts[4], 1)
+	conLower, conUpper := br.section(ncons, 8), br.section(ncons, 8)
+	starts := br.section(ncons+1, 8)
+	conOffsets, conNames := br.section(ncons+1, 8), br.section(counts[5], 1)
+	cols, coefs := br.section(nnz, 4), br.section(nnz, 8)
+	if br.err != nil {
+		return nil, br.err
+	}
+
+	f64 := func(b []byte, i uint64) float64 { return math.Float64frombits(le.Uint64(b[8*i:])) }
+	u64 := func(b []byte, i uint64) uint64 { return le.Uint64(b[8*i:]) }
+	str := func(offsets, names []byte, i uint64) (string, error) {
+		from, to := u64(offsets, i), u64(offsets, i+1)
+		if from > to || to > uint64(len(names)) {
+			return "", fmt.Errorf("invalid binary model: bad name offsets")
+		}
+		return string(names[from:to]), nil
+	}
+
+	dir := Minimize
+	if flags&binaryMaximize != 0 {
+		dir = Maximize
+	}
+	model, err := NewModel(string(name), dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]*Variable, nvars)
+	for i := range vars {
+		name, err := str(varOffsets, varNames, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		if VariableType(types[i]) > BinaryVariable {
+			return nil, fmt.Errorf("invalid binary model: bad variable type")
+		}
+		vars[i], err = model.AddDefinedVariable(name, VariableType(types[i]), f64(obj, uint64(i)), f64(lower, uint64(i)), f64(upper, uint64(i)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for k := uint64(0); k < ncons; k++ {
+		if from, to := u64(starts, k), u64(starts, k+1); from > to || to > nnz {
+			return nil, fmt.Errorf("invalid binary model: bad row starts")
+		}
+	}
+	for i := uint64(0); i < nnz; i++ {
+		if uint64(le.Uint32(cols[4*i:])) >= nvars {
+			return nil, fmt.Errorf("invalid binary model: bad column index")
+		}
+	}
+
+	cons, err := model.addConstraintBatch(int(ncons), func(k int) (float64, float64, []*Variable, []float64) {
+		from, to := u64(starts, uint64(k)), u64(starts, uint64(k+1))
+		terms := make([]*Variable, 0, to-from)
+		values := make([]float64, 0, to-from)
+		for i := from; i < to; i++ {
+			terms = append(terms, vars[le.Uint32(cols[4*i:])])
+			values = append(values, f64(coefs, i))
+		}
+		return f64(conLower, uint64(k)), f64(conUpper, uint64(k)), terms, values
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for k, c := range cons {
+		name, err := str(conOffsets, conNames, uint64(k))
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			c.SetName(name)
+		}
+	}
+
+	return model, nil
+}
+
+// binaryWriter writes little-endian values, padding each call's output to
+// a multiple of 8 bytes. Errors are kept until flush.
+type binaryWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (bw *binaryWriter) write(n int, put func([]byte)) {
+	if bw.err != nil {
+		return
+	}
+	buf := make([]byte, (n+7)&^7)
+	put(buf)
+	_, bw.err = bw.w.Write(buf)
+}
+
+func (bw *binaryWriter) bytes(b []byte) {
+	bw.write(len(b), func(buf []byte) { copy(buf, b) })
+}
+
+func (bw *binaryWriter) uint32s(values ...uint32) {
+	bw.write(4*len(values), func(buf []byte) {
+		for i, v := range values {
+			binary.LittleEndian.PutUint32(buf[4*i:], v)
+		}
+	})
+}
+
+func (bw *binaryWriter) uint64s(values ...uint64) {
+	bw.write(8*len(values), func(buf []byte) {
+		for i, v := range values {
+			binary.LittleEndian.PutUint64(buf[8*i:], v)
+		}
+	})
+}
+
+func (bw *binaryWriter) float64s(values ...float64) {
+	bw.write(8*len(values), func(buf []byte) {
+		for i, v := range values {
+			binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+		}
+	})
+}
+
+func (bw *binaryWriter) flush() error {
+	if bw.err != nil {
+		return bw.err
+	}
+	return bw.w.Flush()
+}
+
+// binaryReader splits the binary format into its padded sections.
+type binaryReader struct {
+	data []byte
+	off  uint64
+	err  error
+}
+
+// section returns the next section of n values of the given size.
+func (br *binaryReader) section(n, size uint64) []byte {
+	if br.err != nil {
+		return nil
+	}
+	if n > uint64(len(br.data))/size {
+		br.err = errBinaryTruncated
+		return nil
+	}
+	end := br.off + n*size
+	if end > uint64(len(br.data)) {
+		br.err = errBinaryTruncated
+		return nil
+	}
+	s := br.data[br.off:end]
+	br.off = (end + 7) &^ 7
+	return s
+}
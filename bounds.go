@@ -0,0 +1,196 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"math"
+)
+
+const (
+	// maxPropagationPasses limits the number of passes over all constraints
+	// when propagating bounds, since propagation may converge only slowly.
+	maxPropagationPasses = 20
+
+	// propagationTolerance is the minimum relative improvement for a bound
+	// to be considered tightened.
+	propagationTolerance = 1e-9
+)
+
+// BoundChange describes a change to a variable's bounds, as performed by
+// TightenBounds.
+type BoundChange struct {
+	Variable           *Variable
+	OldLower, OldUpper float64
+	NewLower, NewUpper float64
+}
+
+// TightenBounds uses the model's constraints to derive tighter bounds for its
+// variables and applies them to the model. Integer bounds are rounded
+// accordingly. Tighter bounds do not change the set of feasible solutions,
+// but can make the solver's work easier, particularly for big-M
+// formulations.
+//
+// It returns the changed bounds. If the propagation proves that the model
+// is infeasible, ErrModelInfeasible is returned and the model is not
+// changed.
+func (model *Model) TightenBounds() ([]BoundChange, error) {
+	data := model.readData()
+
+	lower, upper, err := data.propagateBounds()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []BoundChange
+	for i, v := range data.vars {
+		if lower[i] == v.lower && upper[i] == v.upper {
+			continue
+		}
+
+		changes = append(changes, BoundChange{
+			Variable: model.vars[i],
+			OldLower: v.lower,
+			OldUpper: v.upper,
+			NewLower: lower[i],
+			NewUpper: upper[i],
+		})
+	}
+
+	for _, change := range changes {
+		change.Variable.SetBounds(change.NewLower, change.NewUpper)
+	}
+
+	return changes, nil
+}
+
+// propagateBounds returns the tightest variable bounds implied by the
+// constraints, as found by iterated bound propagation.
+func (data *modelData) propagateBounds() (lower, upper []float64, err error) {
+	lower = make([]float64, len(data.vars))
+	upper = make([]float64, len(data.vars))
+	for i, v := range data.vars {
+		lower[i], upper[i] = v.lower, v.upper
+	}
+
+	integral := func(col int) bool {
+		return data.vars[col].typ != ContinuousVariable
+	}
+
+	// tighten updates the bounds of col, if the new values are significantly
+	// tighter
+	tighten := func(col int, newLower, newUpper float64) bool {
+		if integral(col) {
+			newLower = math.Ceil(newLower - propagationTolerance)
+			newUpper = math.Floor(newUpper + propagationTolerance)
+		}
+
+		changed := false
+		if math.IsInf(lower[col], -1) && !math.IsInf(newLower, -1) ||
+			newLower > lower[col]+propagationTolerance*math.Max(1, math.Abs(lower[col])) {
+			lower[col] = newLower
+			changed = true
+		}
+		if math.IsInf(upper[col], 1) && !math.IsInf(newUpper, 1) ||
+			newUpper < upper[col]-propagationTolerance*math.Max(1, math.Abs(upper[col])) {
+			upper[col] = newUpper
+			changed = true
+		}
+		return changed
+	}
+
+	for pass := 0; pass < maxPropagationPasses; pass++ {
+		changed := false
+
+		for _, c := range data.cons {
+			// activity bounds, split into finite parts and the number of
+			// infinite contributions
+			var (
+				minFinite, maxFinite float64
+				minInf, maxInf       int
+			)
+			for i, col := range c.cols {
+				lo, hi := c.coefs[i]*lower[col], c.coefs[i]*upper[col]
+				if c.coefs[i] < 0 {
+					lo, hi = hi, lo
+				}
+
+				if math.IsInf(lo, 0) {
+					minInf++
+				} else {
+					minFinite += lo
+				}
+				if math.IsInf(hi, 0) {
+					maxInf++
+				} else {
+					maxFinite += hi
+				}
+			}
+
+			for i, col := range c.cols {
+				coef := c.coefs[i]
+				lo, hi := coef*lower[col], coef*upper[col]
+				if coef < 0 {
+					lo, hi = hi, lo
+				}
+
+				// minimum and maximum activity of all other terms
+				othersMin, othersMax := math.Inf(-1), math.Inf(1)
+				switch {
+				case minInf == 0:
+					othersMin = minFinite - lo
+				case minInf == 1 && math.IsInf(lo, 0):
+					othersMin = minFinite
+				}
+				switch {
+				case maxInf == 0:
+					othersMax = maxFinite - hi
+				case maxInf == 1 && math.IsInf(hi, 0):
+					othersMax = maxFinite
+				}
+
+				// coef·x ∈ [c.lower - othersMax, c.upper - othersMin]
+				termLower, termUpper := c.lower-othersMax, c.upper-othersMin
+
+				newLower, newUpper := termLower/coef, termUpper/coef
+				if coef < 0 {
+					newLower, newUpper = newUpper, newLower
+				}
+				if math.IsNaN(newLower) {
+					newLower = math.Inf(-1)
+				}
+				if math.IsNaN(newUpper) {
+					newUpper = math.Inf(1)
+				}
+
+				if tighten(col, newLower, newUpper) {
+					changed = true
+				}
+
+				if lower[col]-upper[col] > propagationTolerance*math.Max(1, math.Abs(upper[col])) {
+					return nil, nil, ErrModelInfeasible
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return lower, upper, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// ObjectiveBreakdown returns the contribution of each variable with a
+// non-zero objective coefficient to the objective value, i.e. its
+// coefficient multiplied by its value. The contributions add up to
+// ObjectiveValue, unless the result was obtained with WithObjective, since
+// the model's own objective function is used.
+func (res SolveResult) ObjectiveBreakdown() map[*Variable]float64 {
+	breakdown := make(map[*Variable]float64)
+	for _, v := range res.model.Variables() {
+		if coef := v.Coefficient(); coef != 0 {
+			breakdown[v] = coef * res.scaledValue(v)
+		}
+	}
+
+	return breakdown
+}
+
+// ObjectiveBreakdownBy is like ObjectiveBreakdown, but sums the
+// contributions of variables with the same tag, as returned by the given
+// function. This allows explaining the objective value in terms of e.g.
+// cost categories.
+func (res SolveResult) ObjectiveBreakdownBy(tag func(*Variable) string) map[string]float64 {
+	breakdown := make(map[string]float64)
+	for v, contribution := range res.ObjectiveBreakdown() {
+		breakdown[tag(v)] += contribution
+	}
+
+	return breakdown
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonical returns a textual representation of the model which does not
+// depend on the order in which variables, constraints or terms were added.
+// Two models with the same formulation always result in the same string,
+// making it suitable for golden-file tests.
+//
+// Variables are identified by name, so models with duplicate variable names
+// may not be told apart. Constraint names are only included if they were
+// explicitly set.
+func (model *Model) Canonical() string {
+	data := model.readData()
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "name: %s\n", data.name)
+
+	if data.maximize {
+		b.WriteString("direction: maximize\n")
+	} else {
+		b.WriteString("direction: minimize\n")
+	}
+
+	cols := make([]int, 0, len(data.vars))
+	coefs := make([]float64, 0, len(data.vars))
+	for i, v := range data.vars {
+		if v.obj != 0 {
+			cols = append(cols, i)
+			coefs = append(coefs, v.obj)
+		}
+	}
+
+	b.WriteString("objective:")
+	b.WriteString(data.canonicalTerms(cols, coefs))
+	b.WriteString("\n")
+
+	vars := make([]string, len(data.vars))
+	for i, v := range data.vars {
+		vars[i] = fmt.Sprintf("  %s %s [%s, %s]\n",
+			v.name, variableTypeNames[v.typ], canonicalFloat(v.lower), canonicalFloat(v.upper))
+	}
+	sort.Strings(vars)
+
+	b.WriteString("variables:\n")
+	for _, line := range vars {
+		b.WriteString(line)
+	}
+
+	cons := make([]string, len(data.cons))
+	for i, c := range data.cons {
+		cons[i] = "  " + data.canonicalConstraint(c) + "\n"
+	}
+	sort.Strings(cons)
+
+	b.WriteString("constraints:\n")
+	for _, line := range cons {
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
+
+// canonicalConstraint renders a single constraint, including its name, if
+// explicitly set.
+func (data *modelData) canonicalConstraint(c conData) string {
+	name := ""
+	if c.name != "" {
+		name = c.name + ": "
+	}
+
+	return fmt.Sprintf("%s%s <=%s <= %s",
+		name, canonicalFloat(c.lower), data.canonicalTerms(c.cols, c.coefs), canonicalFloat(c.upper))
+}
+
+// canonicalTerms renders the given terms sorted by variable name.
+func (data *modelData) canonicalTerms(cols []int, coefs []float64) string {
+	type term struct {
+		name string
+		coef float64
+	}
+
+	terms := make([]term, len(cols))
+	for i, col := range cols {
+		terms[i] = term{data.vars[col].name, coefs[i]}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].name != terms[j].name {
+			return terms[i].name < terms[j].name
+		}
+		return terms[i].coef < terms[j].coef
+	})
+
+	var b strings.Builder
+	for _, t := range terms {
+		sign := ""
+		if t.coef >= 0 {
+			sign = "+"
+		}
+		fmt.Fprintf(&b, " %s%s %s", sign, canonicalFloat(t.coef), t.name)
+	}
+
+	return b.String()
+}
+
+var variableTypeNames = map[VariableType]string{
+	ContinuousVariable: "continuous",
+	IntegerVariable:    "integer",
+	BinaryVariable:     "binary",
+}
+
+func canonicalFloat(f float64) string {
+	switch {
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsInf(f, 1):
+		return "+inf"
+	case f == 0:
+		return "0" // avoid "-0"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
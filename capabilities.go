@@ -0,0 +1,57 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// Capabilities describes which solver features are available through this
+// package, so generic code can adapt to them or fail early.
+type Capabilities struct {
+	// SOS reports support for special ordered sets, as used by
+	// AddAtMostOne.
+	SOS bool
+	// SemiContinuous reports support for semi-continuous variables, which
+	// are either 0 or within their bounds.
+	SemiContinuous bool
+	// RangedRows reports support for constraints with both a finite lower
+	// and upper bound in a single row.
+	RangedRows bool
+	// Callbacks reports support for aborting a running solve, e.g. through
+	// SolveWithContext.
+	Callbacks bool
+	// LazyConstraints reports support for adding constraints during
+	// branch-and-bound.
+	LazyConstraints bool
+	// Duals reports support for dual values and shadow prices.
+	Duals bool
+	// MIPStart reports support for warm starting branch-and-bound with a
+	// known solution.
+	MIPStart bool
+	// Quadratic reports support for exact quadratic objectives, as opposed
+	// to approximations like SetQuadraticObjectiveApprox.
+	Quadratic bool
+}
+
+// SolverCapabilities returns the features supported by the lp_solve backend,
+// as exposed by this package.
+func SolverCapabilities() Capabilities {
+	return Capabilities{
+		SOS:        true,
+		RangedRows: true,
+		Callbacks:  true,
+		Duals:      true,
+	}
+}
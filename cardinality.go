@@ -0,0 +1,93 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// sosThreshold is the number of variables from which AddAtMostOne also
+// declares a special ordered set: for short sets, branching on the
+// individual variables is just as effective.
+const sosThreshold = 8
+
+// AddCardinality adds a constraint requiring between min and max of the
+// given binary variables to be 1.
+func (model *Model) AddCardinality(vars []*Variable, min, max int) (*Constraint, error) {
+	if min < 0 || min > max || min > len(vars) {
+		return nil, fmt.Errorf("invalid cardinality range [%d, %d] for %d variables", min, max, len(vars))
+	}
+	for _, v := range vars {
+		if v.model != model {
+			return nil, fmt.Errorf("variable does not belong to model")
+		}
+		if v.Type() != BinaryVariable {
+			return nil, fmt.Errorf("cardinality constraint on non-binary variable %q", v.Name())
+		}
+	}
+
+	coefs := make([]float64, len(vars))
+	for i := range coefs {
+		coefs[i] = 1
+	}
+
+	lower := float64(min)
+	if min == 0 {
+		lower = math.Inf(-1)
+	}
+
+	return model.AddConstraint(lower, float64(max), vars, coefs)
+}
+
+// AddAtMostOne adds a constraint allowing at most one of the given binary
+// variables to be 1. For larger sets of variables, they are additionally
+// declared as a special ordered set of type 1, which lets the solver branch
+// on the whole set at once.
+func (model *Model) AddAtMostOne(vars []*Variable) (*Constraint, error) {
+	c, err := model.AddCardinality(vars, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vars) >= sosThreshold {
+		model.mu.Lock()
+		defer model.mu.Unlock()
+
+		cols := make([]C.int, len(vars))
+		weights := make([]C.REAL, len(vars))
+		for i, v := range vars {
+			cols[i] = C.int(v.index + 1)
+			weights[i] = C.REAL(i + 1)
+		}
+
+		name := C.CString(fmt.Sprintf("SOS%d", c.index+1))
+		defer C.free(unsafe.Pointer(name))
+
+		if C.add_SOS(model.prob, name, 1, 1, C.int(len(vars)), &cols[0], &weights[0]) == 0 {
+			return nil, fmt.Errorf("could not add special ordered set")
+		}
+	}
+
+	return c, nil
+}
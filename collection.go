@@ -0,0 +1,92 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+)
+
+// VariableSlice is an indexed collection of variables.
+type VariableSlice []*Variable
+
+// VariableMatrix is a two-dimensional indexed collection of variables.
+type VariableMatrix []VariableSlice
+
+// AddVariableSlice adds n variables of the given type and bounds to the
+// model, named name[0] to name[n-1]. Their objective coefficients are 0.
+func (model *Model) AddVariableSlice(name string, n int, typ VariableType, low, high float64) (VariableSlice, error) {
+	s := make(VariableSlice, n)
+	for i := range s {
+		v, err := model.AddDefinedVariable(fmt.Sprintf("%s[%d]", name, i), typ, 0, low, high)
+		if err != nil {
+			return nil, err
+		}
+		s[i] = v
+	}
+	return s, nil
+}
+
+// AddVariableMatrix adds rows*cols variables of the given type and bounds to
+// the model, named name[i][j]. Their objective coefficients are 0.
+func (model *Model) AddVariableMatrix(name string, rows, cols int, typ VariableType, low, high float64) (VariableMatrix, error) {
+	m := make(VariableMatrix, rows)
+	for i := range m {
+		row, err := model.AddVariableSlice(fmt.Sprintf("%s[%d]", name, i), cols, typ, low, high)
+		if err != nil {
+			return nil, err
+		}
+		m[i] = row
+	}
+	return m, nil
+}
+
+// Sum returns the sum of all variables in the slice.
+func (s VariableSlice) Sum() Expr {
+	e := Expr{
+		vars:  make([]*Variable, len(s)),
+		coefs: make([]float64, len(s)),
+	}
+	for i, v := range s {
+		e.vars[i] = v
+		e.coefs[i] = 1
+	}
+	return e
+}
+
+// Row returns the i-th row of the matrix.
+func (m VariableMatrix) Row(i int) VariableSlice {
+	return m[i]
+}
+
+// Column returns the j-th column of the matrix.
+func (m VariableMatrix) Column(j int) VariableSlice {
+	col := make(VariableSlice, len(m))
+	for i, row := range m {
+		col[i] = row[j]
+	}
+	return col
+}
+
+// Sum returns the sum of all variables in the matrix.
+func (m VariableMatrix) Sum() Expr {
+	var e Expr
+	for _, row := range m {
+		e = e.Plus(row.Sum())
+	}
+	return e
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddColumn adds a variable to the model along with its coefficients in
+// existing constraints, which is the basic step of column generation
+// algorithms. The variable gets an automatically generated name.
+//
+// The model may already have been solved: solving it again starts from the
+// previous solution's basis, with the new variable being non-basic.
+func (model *Model) AddColumn(coef float64, entries map[*Constraint]float64, low, high float64, typ VariableType) (*Variable, error) {
+	for c, value := range entries {
+		if c.model != model || c.index < 0 {
+			return nil, fmt.Errorf("constraint does not belong to model")
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("coefficient in constraint %d is not finite: %g", c.index+1, value)
+		}
+	}
+
+	model.syncSpool()
+
+	v, err := model.AddDefinedVariable("", typ, coef, low, high)
+	if err != nil {
+		return nil, err
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	for c, value := range entries {
+		C.set_mat(model.prob, C.int(c.index+1), C.int(v.index+1), C.REAL(value))
+	}
+
+	return v, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Constraint is a constraint of a model. Once removed with
+// Model.RemoveConstraint, its methods return zero values and changing it has
+// no effect.
+type Constraint struct {
+	model *Model
+	index int // -1 once removed
+}
+
+/* constraint-related functions */
+
+// Name returns the name of a constraint
+func (c *Constraint) Name() string {
+	c.model.syncSpool()
+
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	if c.index < 0 {
+		return ""
+	}
+	return C.GoString(C.get_row_name(c.model.prob, C.int(c.index+1)))
+}
+
+// SetName sets the name of a constraint
+func (c *Constraint) SetName(name string) {
+	c.model.syncSpool()
+
+	c.model.mu.Lock()
+	defer c.model.mu.Unlock()
+
+	if c.index < 0 {
+		return
+	}
+
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	C.set_row_name(c.model.prob, C.int(c.index+1), c_name)
+}
+
+// Bounds returns the lower and upper bounds of a constraint. Missing bounds
+// are returned as infinities.
+func (c *Constraint) Bounds() (lower, upper float64) {
+	c.model.syncSpool()
+
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	if c.index < 0 {
+		return 0, 0
+	}
+	return c.model.rowBounds(c.index + 1)
+}
+
+// SetBounds changes the lower and upper bounds of a constraint.
+// To remove a bound, pass math.Inf(-1) or math.Inf(1), respectively.
+// Invalid bounds (see ErrInvalidBounds) are logged and otherwise ignored.
+func (c *Constraint) SetBounds(lower, upper float64) {
+	c.model.syncSpool()
+
+	c.model.mu.Lock()
+	defer c.model.mu.Unlock()
+
+	if c.index < 0 {
+		return
+	}
+	if err := validateBounds(lower, upper); err != nil {
+		c.model.logger.Print(fmt.Sprintf("ignoring bounds of %q: %v", C.GoString(C.get_row_name(c.model.prob, C.int(c.index+1))), err))
+		return
+	}
+	c.model.setRowBounds(c.index+1, lower, upper)
+}
+
+// Terms returns the variables used in the constraint with their respective
+// coefficients, in the same form passed to AddConstraint. Variables with a
+// coefficient of zero are omitted.
+func (c *Constraint) Terms() (vars []*Variable, coefs []float64) {
+	c.model.syncSpool()
+
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	if c.index < 0 {
+		return nil, nil
+	}
+	return c.model.rowTerms(c.index + 1)
+}
+
+// rowBounds returns the bounds of the given row. The caller must hold at
+// least the model's read lock.
+func (model *Model) rowBounds(row int) (lower, upper float64) {
+	lower = float64(C.get_rh_lower(model.prob, C.int(row)))
+	upper = float64(C.get_rh_upper(model.prob, C.int(row)))
+
+	inf := float64(C.get_infinite(model.prob))
+
+	if lower <= -inf {
+		lower = math.Inf(-1)
+	}
+	if upper >= inf {
+		upper = math.Inf(1)
+	}
+	return
+}
+
+// setRowBounds sets the bounds of the given row. The caller must hold the
+// model's write lock.
+func (model *Model) setRowBounds(row int, lower, upper float64) {
+	switch {
+	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
+		C.set_constr_type(model.prob, C.int(row), C.LE)
+		C.set_rh(model.prob, C.int(row), C.get_infinite(model.prob))
+	case math.IsInf(lower, 0):
+		C.set_constr_type(model.prob, C.int(row), C.LE)
+		C.set_rh(model.prob, C.int(row), C.REAL(upper))
+	case math.IsInf(upper, 0):
+		C.set_constr_type(model.prob, C.int(row), C.GE)
+		C.set_rh(model.prob, C.int(row), C.REAL(lower))
+	case upper == lower:
+		C.set_constr_type(model.prob, C.int(row), C.EQ)
+		C.set_rh(model.prob, C.int(row), C.REAL(upper))
+	default:
+		C.set_constr_type(model.prob, C.int(row), C.LE)
+		C.set_rh(model.prob, C.int(row), C.REAL(upper))
+		C.set_rh_range(model.prob, C.int(row), C.REAL(upper-lower))
+	}
+}
+
+// rowTerms returns the non-zero entries of the given row (0 being the
+// objective function). The caller must hold at least the model's read lock.
+func (model *Model) rowTerms(row int) (vars []*Variable, coefs []float64) {
+	ncols := len(model.vars)
+	if ncols == 0 {
+		return nil, nil
+	}
+
+	vals := make([]C.REAL, ncols)
+	colno := make([]C.int, ncols)
+
+	n := int(C.get_rowex(model.prob, C.int(row), &vals[0], &colno[0]))
+
+	vars = make([]*Variable, 0, n)
+	coefs = make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		vars = append(vars, model.vars[colno[i]-1])
+		coefs = append(coefs, float64(vals[i]))
+	}
+
+	return vars, coefs
+}
+
+// syncSpool loads all spooled constraints, so that they can be queried. It
+// must be called without holding the model's lock. If loading fails, the
+// error is returned by the next solve.
+func (model *Model) syncSpool() {
+	if model.spool == nil {
+		return
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	_ = model.loadSpooledConstraints()
+}
+
+// removeConstraints deletes the given constraints from the model and
+// renumbers the remaining ones. Removed constraints are invalidated. The
+// caller must hold the model's write lock and spooled constraints must have
+// been loaded.
+func (model *Model) removeConstraints(cons []*Constraint) {
+	removed := make(map[*Constraint]bool, len(cons))
+	for _, c := range cons {
+		removed[c] = true
+	}
+
+	// delete from the end, so row numbers of yet-to-delete rows stay valid
+	for i := len(model.cons) - 1; i >= 0; i-- {
+		if removed[model.cons[i]] {
+			C.del_constraint(model.prob, C.int(i+1))
+		}
+	}
+
+	for key, c := range model.dedup {
+		if removed[c] {
+			delete(model.dedup, key)
+		}
+	}
+
+	// old row indices to new ones, for the scaling
+	renumbered := make([]int, len(model.cons))
+	kept := model.cons[:0]
+	for i, c := range model.cons {
+		if removed[c] {
+			renumbered[i] = -1
+			c.index = -1
+			continue
+		}
+		renumbered[i] = len(kept)
+		c.index = len(kept)
+		kept = append(kept, c)
+	}
+	model.scaling = model.scaling.renumber(nil, func(i int) int { return renumbered[i] })
+	for i := len(kept); i < len(model.cons); i++ {
+		model.cons[i] = nil
+	}
+	model.cons = kept
+}
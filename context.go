@@ -1,7 +1,9 @@
 package golpa
 
+// #include <stdlib.h>
+import "C"
+
 import (
-	"C"
 	"sync"
 	"unsafe"
 )
@@ -36,3 +38,11 @@ func loadRef(ptr unsafe.Pointer) interface{} {
 
 	return refs[ptr]
 }
+
+func releaseRef(ptr unsafe.Pointer) {
+	refsMu.Lock()
+	defer refsMu.Unlock()
+
+	delete(refs, ptr)
+	C.free(ptr)
+}
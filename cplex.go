@@ -0,0 +1,243 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LPDialect selects the syntax used by ExportLP.
+type LPDialect int
+
+const (
+	// DialectLPSolve is lp_solve's own LP format.
+	DialectLPSolve LPDialect = iota
+	// DialectCPLEX is the CPLEX LP format, which is also read by most other
+	// commercial and open source solvers.
+	DialectCPLEX
+)
+
+// ExportOption configures ExportLP.
+type ExportOption func(*exportOptions)
+
+type exportOptions struct {
+	dialect LPDialect
+}
+
+// WithDialect selects the LP dialect written by ExportLP.
+//
+// In the CPLEX dialect, ranged constraints are split into two constraints,
+// with "_lo" and "_hi" name suffixes, and constraints without finite bounds
+// are left out. Characters not allowed in CPLEX names, such as brackets and
+// spaces, are replaced by underscores, names starting with a digit or a
+// period are prefixed with one, and names which then collide get numeric
+// suffixes.
+func WithDialect(dialect LPDialect) ExportOption {
+	return func(o *exportOptions) {
+		o.dialect = dialect
+	}
+}
+
+// cplexMaxLine is the length after which lines are wrapped, safely below
+// the 510 characters accepted by CPLEX.
+const cplexMaxLine = 255
+
+// cplexLP returns the model in CPLEX LP format.
+func (data *modelData) cplexLP() string {
+	var b strings.Builder
+
+	if data.name != "" {
+		fmt.Fprintf(&b, "\\Problem name: %s\n\n", data.name)
+	}
+
+	if data.maximize {
+		b.WriteString("Maximize\n")
+	} else {
+		b.WriteString("Minimize\n")
+	}
+
+	// variables and constraints have separate namespaces
+	usedCols := make(cplexNames)
+	colNames := make([]string, len(data.vars))
+	for i, v := range data.vars {
+		colNames[i] = usedCols.claim(v.name)
+	}
+	usedRows := cplexNames{"obj": true}
+
+	var cols []int
+	var coefs []float64
+	for i, v := range data.vars {
+		if v.obj != 0 {
+			cols = append(cols, i)
+			coefs = append(coefs, v.obj)
+		}
+	}
+	cplexRow(&b, colNames, "obj", cols, coefs, "", 0)
+
+	b.WriteString("Subject To\n")
+	for i, c := range data.cons {
+		name := c.name
+		if name == "" {
+			name = fmt.Sprintf("R%d", i+1)
+		}
+
+		switch {
+		case c.lower == c.upper:
+			cplexRow(&b, colNames, usedRows.claim(name), c.cols, c.coefs, "=", c.lower)
+		case !math.IsInf(c.lower, -1) && !math.IsInf(c.upper, 1):
+			cplexRow(&b, colNames, usedRows.claim(name+"_lo"), c.cols, c.coefs, ">=", c.lower)
+			cplexRow(&b, colNames, usedRows.claim(name+"_hi"), c.cols, c.coefs, "<=", c.upper)
+		case !math.IsInf(c.lower, -1):
+			cplexRow(&b, colNames, usedRows.claim(name), c.cols, c.coefs, ">=", c.lower)
+		case !math.IsInf(c.upper, 1):
+			cplexRow(&b, colNames, usedRows.claim(name), c.cols, c.coefs, "<=", c.upper)
+		}
+	}
+
+	b.WriteString("Bounds\n")
+	var general, binary []string
+	for i, v := range data.vars {
+		name := colNames[i]
+		switch v.typ {
+		case BinaryVariable:
+			binary = append(binary, name)
+			continue
+		case IntegerVariable:
+			general = append(general, name)
+		}
+
+		switch {
+		case math.IsInf(v.lower, -1) && math.IsInf(v.upper, 1):
+			fmt.Fprintf(&b, " %s free\n", name)
+		case v.lower == v.upper:
+			fmt.Fprintf(&b, " %s = %s\n", name, cplexFloat(v.lower))
+		default:
+			fmt.Fprintf(&b, " %s <= %s <= %s\n", cplexFloat(v.lower), name, cplexFloat(v.upper))
+		}
+	}
+
+	for _, section := range []struct {
+		title string
+		names []string
+	}{{"General", general}, {"Binary", binary}} {
+		if len(section.names) == 0 {
+			continue
+		}
+		b.WriteString(section.title + "\n")
+		line := ""
+		for _, name := range section.names {
+			if len(line)+len(name) > cplexMaxLine {
+				b.WriteString(line + "\n")
+				line = ""
+			}
+			line += " " + name
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("End\n")
+
+	return b.String()
+}
+
+// cplexNames records the names used in a CPLEX LP file.
+type cplexNames map[string]bool
+
+// claim returns a valid CPLEX name for the given name, which is not yet
+// used, and records it as used.
+func (used cplexNames) claim(name string) string {
+	valid := []byte(name)
+	for i, c := range valid {
+		if !cplexNameChar(c) {
+			valid[i] = '_'
+		}
+	}
+	name = string(valid)
+	if name == "" || name[0] == '.' || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	base := name
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[name] = true
+
+	return name
+}
+
+// cplexNameChar returns whether the character may appear in a CPLEX name.
+func cplexNameChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!\"#$%&()/,.;?@_`'{}|~", c) >= 0
+}
+
+// cplexRow writes a named linear expression over the variables with the
+// given names, followed by the given operator and right-hand side unless op
+// is empty. Empty expressions are written as a zero multiple of the first
+// variable, since CPLEX requires at least one term.
+func cplexRow(b *strings.Builder, colNames []string, name string, cols []int, coefs []float64, op string, rhs float64) {
+	line := " " + name + ":"
+	write := func(s string) {
+		if len(line)+len(s) > cplexMaxLine {
+			b.WriteString(line + "\n")
+			line = "  "
+		}
+		line += s
+	}
+
+	if len(cols) == 0 && len(colNames) > 0 {
+		write(" 0 " + colNames[0])
+	}
+	for i, col := range cols {
+		sign := "+"
+		coef := coefs[i]
+		if coef < 0 {
+			sign = "-"
+			coef = -coef
+		}
+		if i == 0 && sign == "+" {
+			write(" " + cplexFloat(coef) + " " + colNames[col])
+		} else {
+			write(" " + sign + " " + cplexFloat(coef) + " " + colNames[col])
+		}
+	}
+	if op != "" {
+		write(" " + op + " " + cplexFloat(rhs))
+	}
+
+	b.WriteString(line + "\n")
+}
+
+// cplexFloat formats a number for CPLEX LP files.
+func cplexFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
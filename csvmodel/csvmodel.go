@@ -0,0 +1,271 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package csvmodel builds golpa models from CSV data.
+//
+// A model is described by three CSV files, each starting with a header row
+// naming its columns. Columns may appear in any order; unknown columns are
+// ignored.
+//
+// The variables file has one row per variable:
+//
+//	name       required, unique
+//	type       continuous (default), integer or binary
+//	lower      lower bound, default 0
+//	upper      upper bound, default inf
+//	objective  objective coefficient, default 0
+//
+// The constraints file has one row per constraint:
+//
+//	name       required, unique
+//	lower      lower bound, default -inf
+//	upper      upper bound, default inf
+//
+// The coefficients file has one row per non-zero coefficient:
+//
+//	constraint  name of a constraint, required
+//	variable    name of a variable, required
+//	coefficient value, required
+//
+// Infinite bounds are written as "inf" and "-inf". Files may be
+// gzip-compressed.
+package csvmodel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/costela/golpa"
+)
+
+// Sources holds the readers for the CSV files describing a model.
+type Sources struct {
+	Variables    io.Reader
+	Constraints  io.Reader
+	Coefficients io.Reader
+}
+
+var variableTypes = map[string]golpa.VariableType{
+	"":           golpa.ContinuousVariable,
+	"continuous": golpa.ContinuousVariable,
+	"integer":    golpa.IntegerVariable,
+	"binary":     golpa.BinaryVariable,
+}
+
+// Read adds the variables and constraints described by the given sources to
+// the model.
+func Read(model *golpa.Model, src Sources) error {
+	vars := make(map[string]*golpa.Variable)
+	err := readTable(src.Variables, "variables", []string{"name"}, func(row map[string]string) error {
+		name := row["name"]
+		if vars[name] != nil {
+			return fmt.Errorf("duplicate variable %q", name)
+		}
+
+		typ, ok := variableTypes[strings.ToLower(row["type"])]
+		if !ok {
+			return fmt.Errorf("unknown variable type %q", row["type"])
+		}
+		lower, err := parseFloat(row["lower"], 0)
+		if err != nil {
+			return err
+		}
+		upper, err := parseFloat(row["upper"], math.Inf(1))
+		if err != nil {
+			return err
+		}
+		objective, err := parseFloat(row["objective"], 0)
+		if err != nil {
+			return err
+		}
+
+		v, err := model.AddDefinedVariable(name, typ, objective, lower, upper)
+		if err != nil {
+			return err
+		}
+		vars[name] = v
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	type constraint struct {
+		name         string
+		lower, upper float64
+		vars         []*golpa.Variable
+		coefs        []float64
+	}
+	var cons []*constraint
+	byName := make(map[string]*constraint)
+	err = readTable(src.Constraints, "constraints", []string{"name"}, func(row map[string]string) error {
+		name := row["name"]
+		if byName[name] != nil {
+			return fmt.Errorf("duplicate constraint %q", name)
+		}
+
+		lower, err := parseFloat(row["lower"], math.Inf(-1))
+		if err != nil {
+			return err
+		}
+		upper, err := parseFloat(row["upper"], math.Inf(1))
+		if err != nil {
+			return err
+		}
+
+		c := &constraint{name: name, lower: lower, upper: upper}
+		cons = append(cons, c)
+		byName[name] = c
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readTable(src.Coefficients, "coefficients", []string{"constraint", "variable", "coefficient"}, func(row map[string]string) error {
+		c := byName[row["constraint"]]
+		if c == nil {
+			return fmt.Errorf("unknown constraint %q", row["constraint"])
+		}
+		v := vars[row["variable"]]
+		if v == nil {
+			return fmt.Errorf("unknown variable %q", row["variable"])
+		}
+		coef, err := strconv.ParseFloat(row["coefficient"], 64)
+		if err != nil {
+			return err
+		}
+
+		c.vars = append(c.vars, v)
+		c.coefs = append(c.coefs, coef)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cons {
+		added, err := model.AddConstraint(c.lower, c.upper, c.vars, c.coefs)
+		if err != nil {
+			return fmt.Errorf("constraint %q: %w", c.name, err)
+		}
+		added.SetName(c.name)
+	}
+
+	return nil
+}
+
+// ReadFiles is like Read, but reads the CSV files at the given paths.
+func ReadFiles(model *golpa.Model, variables, constraints, coefficients string) error {
+	var src Sources
+	for _, f := range []struct {
+		path string
+		r    *io.Reader
+	}{
+		{variables, &src.Variables},
+		{constraints, &src.Constraints},
+		{coefficients, &src.Coefficients},
+	} {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		*f.r = file
+	}
+
+	return Read(model, src)
+}
+
+// readTable calls fn for each row of the CSV data in r, as a map from
+// (lowercased) column names to values. The required columns must be present
+// in the header and non-empty in each row.
+func readTable(r io.Reader, table string, required []string, fn func(row map[string]string) error) error {
+	if r == nil {
+		return nil
+	}
+
+	r, err := golpa.Decompress(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", table, err)
+	}
+
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", table, err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, col := range required {
+		if !containsString(header, col) {
+			return fmt.Errorf("%s: missing column %q", table, col)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			row[col] = strings.TrimSpace(record[i])
+		}
+		for _, col := range required {
+			if row[col] == "" {
+				return fmt.Errorf("%s line %d: empty %s", table, line, col)
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return fmt.Errorf("%s line %d: %w", table, line, err)
+		}
+	}
+}
+
+// parseFloat parses s as a float, returning def for empty strings.
+func parseFloat(s string, def float64) (float64, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
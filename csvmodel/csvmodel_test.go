@@ -0,0 +1,107 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package csvmodel
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+const (
+	variablesCSV = `name,type,lower,upper,objective
+x,,0,inf,3
+y,integer,,4,2
+`
+	constraintsCSV = `name,lower,upper
+capacity,,10
+mix,-inf,2
+`
+	coefficientsCSV = `constraint,variable,coefficient
+capacity,x,2
+capacity,y,1
+mix,x,1
+mix,y,-1
+`
+)
+
+func TestRead(t *testing.T) {
+	model, err := golpa.NewModel("csv", golpa.Maximize)
+	require.NoError(t, err)
+
+	err = Read(model, Sources{
+		Variables:    strings.NewReader(variablesCSV),
+		Constraints:  strings.NewReader(constraintsCSV),
+		Coefficients: strings.NewReader(coefficientsCSV),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, model.VariableCount())
+	y := model.Variables()[1]
+	assert.Equal(t, golpa.IntegerVariable, y.Type())
+	l, u := y.Bounds()
+	assert.Equal(t, 0.0, l)
+	assert.Equal(t, 4.0, u)
+
+	cons := model.Constraints()
+	require.Len(t, cons, 2)
+	assert.Equal(t, "capacity", cons[0].Name())
+	l, u = cons[0].Bounds()
+	assert.True(t, math.IsInf(l, -1))
+	assert.Equal(t, 10.0, u)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	// y at its bound of 4, leaving room for x = 3
+	assert.InDelta(t, 17, res.ObjectiveValue(), delta)
+}
+
+func TestReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 3)
+	for i, content := range []string{variablesCSV, constraintsCSV, coefficientsCSV} {
+		paths[i] = filepath.Join(dir, []string{"vars.csv", "cons.csv", "coefs.csv"}[i])
+		require.NoError(t, os.WriteFile(paths[i], []byte(content), 0o600))
+	}
+
+	model, err := golpa.NewModel("csv", golpa.Maximize)
+	require.NoError(t, err)
+	require.NoError(t, ReadFiles(model, paths[0], paths[1], paths[2]))
+	assert.Equal(t, 2, model.ConstraintCount())
+}
+
+func TestReadErrors(t *testing.T) {
+	for name, src := range map[string]Sources{
+		"unknown type":       {Variables: strings.NewReader("name,type\nx,real\n")},
+		"duplicate variable": {Variables: strings.NewReader("name\nx\nx\n")},
+		"missing column":     {Constraints: strings.NewReader("lower,upper\n1,2\n")},
+		"bad bound":          {Constraints: strings.NewReader("name,lower\nc,abc\n")},
+		"unknown constraint": {Coefficients: strings.NewReader("constraint,variable,coefficient\nc,x,1\n")},
+	} {
+		model, err := golpa.NewModel("csv", golpa.Minimize)
+		require.NoError(t, err)
+		assert.Error(t, Read(model, src), name)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+)
+
+// modelData is a plain Go copy of a model's formulation, used by functions
+// that need to inspect the whole model at once.
+type modelData struct {
+	name     string
+	maximize bool
+	vars     []varData
+	cons     []conData
+}
+
+type varData struct {
+	name         string
+	typ          VariableType
+	lower, upper float64
+	obj          float64
+}
+
+type conData struct {
+	name         string // empty unless explicitly set
+	lower, upper float64
+	cols         []int // 0-based variable indices
+	coefs        []float64
+}
+
+// readData copies the model's formulation into Go memory.
+func (model *Model) readData() *modelData {
+	model.syncSpool()
+
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	data := &modelData{
+		name:     C.GoString(C.get_lp_name(model.prob)),
+		maximize: C.is_maxim(model.prob) == C.TRUE,
+		vars:     make([]varData, len(model.vars)),
+		cons:     make([]conData, len(model.cons)),
+	}
+
+	for i, v := range model.vars {
+		col := v.index + 1
+		lower, upper := model.colBounds(col)
+		data.vars[i] = varData{
+			name:  model.colName(col),
+			typ:   model.colType(col),
+			lower: lower,
+			upper: upper,
+			obj:   float64(C.get_mat(model.prob, 0, C.int(col))),
+		}
+	}
+
+	for i, c := range model.cons {
+		row := c.index + 1
+
+		name := C.GoString(C.get_row_name(model.prob, C.int(row)))
+		if name == fmt.Sprintf("R%d", row) {
+			name = ""
+		}
+
+		vars, coefs := model.rowTerms(row)
+		cols := make([]int, len(vars))
+		for j, v := range vars {
+			cols[j] = v.index
+		}
+
+		lower, upper := model.rowBounds(row)
+		data.cons[i] = conData{
+			name:  name,
+			lower: lower,
+			upper: upper,
+			cols:  cols,
+			coefs: coefs,
+		}
+	}
+
+	return data
+}
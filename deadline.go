@@ -0,0 +1,81 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+//
+// extern int abortCallback(lprec *lp, void *userhandle);
+import "C"
+
+import (
+	"context"
+	"math"
+	"time"
+	"unsafe"
+)
+
+// polish fixes the integer variables of the solution just found to their
+// values and solves the LP over the remaining variables, aborting after
+// grace. If the LP cannot be solved in time, res is given a copy of the
+// original solution instead, since the solver's own copy is overwritten.
+// The caller must hold the model's write lock.
+func (model *Model) polish(res *SolveResult, grace time.Duration) {
+	n := len(model.vars)
+	values := make([]float64, n)
+	if n > 0 {
+		C.get_variables(model.prob, (*C.REAL)(unsafe.Pointer(&values[0])))
+	}
+	objective := float64(C.get_objective(model.prob))
+
+	type fixed struct {
+		col          C.int
+		lower, upper C.REAL
+	}
+	var ints []fixed
+	for i := range model.vars {
+		col := C.int(i + 1)
+		if C.is_int(model.prob, col) != C.TRUE {
+			continue
+		}
+		ints = append(ints, fixed{col, C.get_lowbo(model.prob, col), C.get_upbo(model.prob, col)})
+		value := C.REAL(math.Round(values[i]))
+		C.set_int(model.prob, col, C.FALSE)
+		C.set_bounds(model.prob, col, value, value)
+	}
+	defer func() {
+		for _, f := range ints {
+			C.set_bounds(model.prob, f.col, f.lower, f.upper)
+			C.set_int(model.prob, f.col, C.TRUE)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	ref := saveRef(&abortMonitor{ctx: ctx})
+	C.put_abortfunc(model.prob, (*C.lphandle_intfunc)(C.abortCallback), ref)
+	defer func() {
+		C.put_abortfunc(model.prob, nil, nil)
+		releaseRef(ref)
+	}()
+
+	if ret := C.solve(model.prob); ret != C.OPTIMAL {
+		res.values = values
+		res.objective = &objective
+	}
+}
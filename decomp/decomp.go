@@ -0,0 +1,496 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package decomp implements Dantzig-Wolfe decomposition for golpa models with
+// block-angular structure: a set of independent blocks of constraints, tied
+// together by a few linking constraints.
+//
+// The original model is split into a master problem, containing the linking
+// constraints, and one pricing subproblem per block. Solutions of the pricing
+// subproblems are added as columns to the master problem until no column
+// with negative reduced cost remains.
+package decomp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/costela/golpa"
+)
+
+// DefaultMaxIterations is the default limit on the number of column
+// generation rounds performed by Decomposition.Solve.
+const DefaultMaxIterations = 1000
+
+// reducedCostTolerance is the reduced cost below which a pricing solution is
+// considered improving.
+const reducedCostTolerance = 1e-9
+
+// feasibilityTolerance is the value above which an artificial variable in
+// the final master solution marks the model as infeasible.
+const feasibilityTolerance = 1e-6
+
+// Block is a set of variables and the constraints which only involve them.
+type Block struct {
+	Variables   []*golpa.Variable
+	Constraints []*golpa.Constraint
+}
+
+// Decomposition describes the block-angular structure of a model.
+type Decomposition struct {
+	Model *golpa.Model
+
+	// Linking are the constraints spanning more than one block. They are kept
+	// in the master problem.
+	Linking []*golpa.Constraint
+
+	// Blocks are the independent parts of the model, each solved as a
+	// separate pricing subproblem.
+	Blocks []Block
+
+	// Master are the variables which appear in no block constraint. They
+	// are kept as-is in the master problem.
+	Master []*golpa.Variable
+}
+
+// Detect searches the model for block-angular structure. If the constraints
+// already split into independent blocks, no linking constraints are used.
+// Otherwise, the constraints involving the most variables are moved to the
+// linking set, one at a time, until the rest splits into at least two blocks.
+//
+// This is a greedy heuristic; if the structure of the model is known, prefer
+// WithLinking.
+func Detect(model *golpa.Model) (*Decomposition, error) {
+	cons := model.Constraints()
+
+	order := make([]int, len(cons))
+	sizes := make([]int, len(cons))
+	for i, c := range cons {
+		vars, _ := c.Terms()
+		order[i] = i
+		sizes[i] = len(vars)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sizes[order[i]] > sizes[order[j]] })
+
+	for k := 0; k <= len(cons)/2; k++ {
+		linking := make([]*golpa.Constraint, k)
+		for i := range linking {
+			linking[i] = cons[order[i]]
+		}
+
+		d, err := WithLinking(model, linking)
+		if err != nil {
+			return nil, err
+		}
+		if len(d.Blocks) >= 2 {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no block structure found")
+}
+
+// WithLinking decomposes the model using the given linking constraints. The
+// remaining constraints are grouped into blocks by the variables they share.
+func WithLinking(model *golpa.Model, linking []*golpa.Constraint) (*Decomposition, error) {
+	vars := model.Variables()
+	cols := make(map[*golpa.Variable]int, len(vars))
+	for i, v := range vars {
+		cols[v] = i
+	}
+
+	isLinking := make(map[*golpa.Constraint]bool, len(linking))
+	for _, c := range linking {
+		isLinking[c] = true
+	}
+
+	parent := make([]int, len(vars))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	var blockCons []*golpa.Constraint
+	inBlock := make([]bool, len(vars))
+	for _, c := range model.Constraints() {
+		if isLinking[c] {
+			continue
+		}
+		terms, _ := c.Terms()
+		if len(terms) == 0 {
+			continue
+		}
+		blockCons = append(blockCons, c)
+		first, ok := cols[terms[0]]
+		if !ok {
+			return nil, fmt.Errorf("constraint %q uses unknown variable", c.Name())
+		}
+		for _, v := range terms {
+			col := cols[v]
+			inBlock[col] = true
+			parent[find(col)] = find(first)
+		}
+	}
+
+	d := &Decomposition{
+		Model:   model,
+		Linking: linking,
+	}
+
+	blockOf := make(map[int]int)
+	for col, v := range vars {
+		if !inBlock[col] {
+			d.Master = append(d.Master, v)
+			continue
+		}
+		root := find(col)
+		k, ok := blockOf[root]
+		if !ok {
+			k = len(d.Blocks)
+			blockOf[root] = k
+			d.Blocks = append(d.Blocks, Block{})
+		}
+		d.Blocks[k].Variables = append(d.Blocks[k].Variables, v)
+	}
+	for _, c := range blockCons {
+		terms, _ := c.Terms()
+		k := blockOf[find(cols[terms[0]])]
+		d.Blocks[k].Constraints = append(d.Blocks[k].Constraints, c)
+	}
+
+	return d, nil
+}
+
+// Option configures Decomposition.Solve.
+type Option func(*options)
+
+type options struct {
+	maxIterations int
+}
+
+// WithMaxIterations limits the number of column generation rounds. If the
+// limit is reached, the best master solution found so far is returned.
+func WithMaxIterations(n int) Option {
+	return func(o *options) {
+		o.maxIterations = n
+	}
+}
+
+// Result holds the outcome of a Dantzig-Wolfe decomposition solve.
+type Result struct {
+	objective  float64
+	iterations int
+	converged  bool
+	values     map[*golpa.Variable]float64
+}
+
+// ObjectiveValue returns the objective value of the solution, in terms of
+// the original model.
+func (res *Result) ObjectiveValue() float64 {
+	return res.objective
+}
+
+// Value returns the value of a variable of the original model, as a convex
+// combination of the pricing solutions chosen by the master problem.
+func (res *Result) Value(v *golpa.Variable) float64 {
+	return res.values[v]
+}
+
+// Iterations returns the number of column generation rounds performed.
+func (res *Result) Iterations() int {
+	return res.iterations
+}
+
+// Converged reports whether column generation stopped because no improving
+// column was left, as opposed to hitting the iteration limit.
+func (res *Result) Converged() bool {
+	return res.converged
+}
+
+// column is a pricing solution added to the master problem.
+type column struct {
+	block  int
+	lambda *golpa.Variable
+	values []float64
+}
+
+// pricing is the subproblem of a single block.
+type pricing struct {
+	model *golpa.Model
+	vars  []*golpa.Variable
+}
+
+// Solve runs the column generation loop and returns the solution of the
+// master problem, mapped back to the variables of the original model.
+//
+// The master problem is a linear program: integrality is only enforced
+// within the pricing subproblems, so the result is the (often stronger)
+// Dantzig-Wolfe relaxation of the original model, and variable values may be
+// fractional. Each block must be feasible and bounded on its own.
+func (d *Decomposition) Solve(opts ...Option) (*Result, error) {
+	o := options{maxIterations: DefaultMaxIterations}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the master problem is always minimized; maximization objectives are
+	// negated and restored in the result
+	sign := 1.0
+	if d.Model.Direction() == golpa.Maximize {
+		sign = -1
+	}
+
+	cols := make(map[*golpa.Variable]int)
+	for i, v := range d.Model.Variables() {
+		cols[v] = i
+	}
+
+	bigM := 1.0
+	for _, v := range d.Model.Variables() {
+		bigM = math.Max(bigM, math.Abs(v.Coefficient()))
+	}
+	bigM *= 1e6
+
+	master, err := golpa.NewModel(d.Model.Name()+" master", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	masterVars := make(map[*golpa.Variable]*golpa.Variable, len(d.Master))
+	for _, v := range d.Master {
+		low, high := v.Bounds()
+		mv, err := master.AddDefinedVariable(v.Name(), golpa.ContinuousVariable, sign*v.Coefficient(), low, high)
+		if err != nil {
+			return nil, err
+		}
+		masterVars[v] = mv
+	}
+
+	linking := make([]*golpa.Constraint, len(d.Linking))
+	linkingIndex := make(map[*golpa.Variable][]linkTerm)
+	var artificials []*golpa.Variable
+	for i, c := range d.Linking {
+		low, high := c.Bounds()
+		terms, coefs := c.Terms()
+
+		var mvars []*golpa.Variable
+		var mcoefs []float64
+		for j, v := range terms {
+			if mv, ok := masterVars[v]; ok {
+				mvars = append(mvars, mv)
+				mcoefs = append(mcoefs, coefs[j])
+			} else {
+				linkingIndex[v] = append(linkingIndex[v], linkTerm{row: i, coef: coefs[j]})
+			}
+		}
+
+		if linking[i], err = master.AddConstraint(low, high, mvars, mcoefs); err != nil {
+			return nil, err
+		}
+
+		// artificial variables keep the master feasible until enough columns
+		// have been generated
+		for _, dir := range []struct {
+			finite bool
+			coef   float64
+		}{{!math.IsInf(low, -1), 1}, {!math.IsInf(high, 1), -1}} {
+			if !dir.finite {
+				continue
+			}
+			a, err := master.AddColumn(bigM, map[*golpa.Constraint]float64{linking[i]: dir.coef}, 0, math.Inf(1), golpa.ContinuousVariable)
+			if err != nil {
+				return nil, err
+			}
+			artificials = append(artificials, a)
+		}
+	}
+
+	convexity := make([]*golpa.Constraint, len(d.Blocks))
+	subs := make([]pricing, len(d.Blocks))
+	for k, b := range d.Blocks {
+		if convexity[k], err = master.AddConstraint(1, 1, nil, nil); err != nil {
+			return nil, err
+		}
+		if subs[k], err = newPricing(d.Model.Name(), k, b); err != nil {
+			return nil, err
+		}
+	}
+
+	var columns []column
+	addColumn := func(k int, values []float64) error {
+		cost := 0.0
+		entries := map[*golpa.Constraint]float64{convexity[k]: 1}
+		for j, v := range d.Blocks[k].Variables {
+			cost += sign * v.Coefficient() * values[j]
+			for _, t := range linkingIndex[v] {
+				entries[linking[t.row]] += t.coef * values[j]
+			}
+		}
+		lambda, err := master.AddColumn(cost, entries, 0, math.Inf(1), golpa.ContinuousVariable)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, column{block: k, lambda: lambda, values: values})
+		return nil
+	}
+
+	// initial columns: each block's optimum with respect to the original
+	// objective
+	for k, b := range d.Blocks {
+		for j, v := range b.Variables {
+			subs[k].vars[j].SetObjectiveCoefficient(sign * v.Coefficient())
+		}
+		values, _, err := subs[k].solve()
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", k, err)
+		}
+		if err := addColumn(k, values); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{}
+	var masterRes *golpa.SolveResult
+	for {
+		if masterRes, err = master.Solve(); err != nil {
+			return nil, fmt.Errorf("master problem: %w", err)
+		}
+
+		if res.iterations >= o.maxIterations {
+			break
+		}
+		res.iterations++
+
+		duals := make([]float64, len(linking))
+		for i, c := range linking {
+			duals[i] = masterRes.ShadowPrice(c)
+		}
+
+		improved := false
+		for k, b := range d.Blocks {
+			for j, v := range b.Variables {
+				reduced := sign * v.Coefficient()
+				for _, t := range linkingIndex[v] {
+					reduced -= duals[t.row] * t.coef
+				}
+				subs[k].vars[j].SetObjectiveCoefficient(reduced)
+			}
+			values, objective, err := subs[k].solve()
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %w", k, err)
+			}
+			if objective-masterRes.ShadowPrice(convexity[k]) < -reducedCostTolerance {
+				if err := addColumn(k, values); err != nil {
+					return nil, err
+				}
+				improved = true
+			}
+		}
+
+		if !improved {
+			res.converged = true
+			break
+		}
+	}
+
+	for _, a := range artificials {
+		if masterRes.Value(a) > feasibilityTolerance {
+			return nil, golpa.ErrModelInfeasible
+		}
+	}
+
+	res.values = make(map[*golpa.Variable]float64, len(cols))
+	for v, mv := range masterVars {
+		res.values[v] = masterRes.Value(mv)
+	}
+	for _, col := range columns {
+		lambda := masterRes.Value(col.lambda)
+		for j, v := range d.Blocks[col.block].Variables {
+			res.values[v] += lambda * col.values[j]
+		}
+	}
+	for v := range cols {
+		res.objective += v.Coefficient() * res.values[v]
+	}
+
+	return res, nil
+}
+
+// linkTerm is a coefficient of a block variable in a linking constraint.
+type linkTerm struct {
+	row  int
+	coef float64
+}
+
+// newPricing builds the pricing subproblem of a block as a copy of its
+// variables and constraints.
+func newPricing(name string, k int, b Block) (pricing, error) {
+	model, err := golpa.NewModel(fmt.Sprintf("%s block %d", name, k), golpa.Minimize)
+	if err != nil {
+		return pricing{}, err
+	}
+
+	p := pricing{
+		model: model,
+		vars:  make([]*golpa.Variable, len(b.Variables)),
+	}
+	copies := make(map[*golpa.Variable]*golpa.Variable, len(b.Variables))
+	for j, v := range b.Variables {
+		low, high := v.Bounds()
+		if p.vars[j], err = model.AddDefinedVariable(v.Name(), v.Type(), 0, low, high); err != nil {
+			return pricing{}, err
+		}
+		copies[v] = p.vars[j]
+	}
+
+	for _, c := range b.Constraints {
+		low, high := c.Bounds()
+		terms, coefs := c.Terms()
+		vars := make([]*golpa.Variable, len(terms))
+		for i, v := range terms {
+			vars[i] = copies[v]
+		}
+		if _, err := model.AddConstraint(low, high, vars, coefs); err != nil {
+			return pricing{}, err
+		}
+	}
+
+	return p, nil
+}
+
+// solve solves the pricing subproblem with its current objective, returning
+// the values of its variables and the objective value.
+func (p pricing) solve() ([]float64, float64, error) {
+	res, err := p.model.Solve()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := make([]float64, len(p.vars))
+	for j, v := range p.vars {
+		values[j] = res.Value(v)
+	}
+
+	return values, res.ObjectiveValue(), nil
+}
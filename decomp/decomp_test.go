@@ -0,0 +1,109 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package decomp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.000001
+
+// blockModel builds a model with two facilities sharing a linking
+// constraint.
+func blockModel(t *testing.T) (model *golpa.Model, vars []*golpa.Variable, linking *golpa.Constraint) {
+	model, err := golpa.NewModel("facilities", golpa.Maximize)
+	require.NoError(t, err)
+
+	for i, coef := range []float64{3, 2, 4, 1} {
+		v, err := model.AddDefinedVariable(string(rune('a'+i)), golpa.ContinuousVariable, coef, 0, 10)
+		require.NoError(t, err)
+		vars = append(vars, v)
+	}
+
+	_, err = model.AddConstraint(math.Inf(-1), 4, vars[0:2], []float64{1, 1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 6, vars[2:4], []float64{1, 2})
+	require.NoError(t, err)
+	linking, err = model.AddConstraint(math.Inf(-1), 6, []*golpa.Variable{vars[0], vars[2], vars[3]}, []float64{1, 1, 1})
+	require.NoError(t, err)
+
+	return model, vars, linking
+}
+
+func TestDetect(t *testing.T) {
+	model, vars, linking := blockModel(t)
+
+	d, err := Detect(model)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*golpa.Constraint{linking}, d.Linking)
+	require.Len(t, d.Blocks, 2)
+	assert.Equal(t, vars[0:2], d.Blocks[0].Variables)
+	assert.Equal(t, vars[2:4], d.Blocks[1].Variables)
+	assert.Empty(t, d.Master)
+}
+
+func TestDetectNoStructure(t *testing.T) {
+	model, err := golpa.NewModel("dense", golpa.Minimize)
+	require.NoError(t, err)
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+	_, err = model.AddConstraint(1, math.Inf(1), []*golpa.Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	_, err = Detect(model)
+	assert.Error(t, err)
+}
+
+func TestSolve(t *testing.T) {
+	model, vars, _ := blockModel(t)
+
+	d, err := Detect(model)
+	require.NoError(t, err)
+
+	res, err := d.Solve()
+	require.NoError(t, err)
+	assert.True(t, res.Converged())
+
+	direct, err := model.Solve()
+	require.NoError(t, err)
+
+	assert.InDelta(t, direct.ObjectiveValue(), res.ObjectiveValue(), delta)
+
+	obj := 0.0
+	for _, v := range vars {
+		obj += v.Coefficient() * res.Value(v)
+	}
+	assert.InDelta(t, res.ObjectiveValue(), obj, delta)
+	assert.LessOrEqual(t, res.Value(vars[0])+res.Value(vars[2])+res.Value(vars[3]), 6+delta)
+}
+
+func TestSolveInfeasible(t *testing.T) {
+	model, _, linking := blockModel(t)
+	linking.SetBounds(100, math.Inf(1))
+
+	d, err := WithLinking(model, []*golpa.Constraint{linking})
+	require.NoError(t, err)
+
+	_, err = d.Solve()
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// constraintKey returns a string uniquely identifying a constraint's terms
+// and bounds, regardless of the order of its terms. Terms are normalized
+// like the rows stored by lp_solve, so the key of the given terms matches
+// the key of the row they are stored in: terms of the same variable are
+// merged, zero terms are dropped and bounds beyond the model's infinity are
+// infinite. The caller must hold at least the model's read lock.
+func (model *Model) constraintKey(lower, upper float64, vars []*Variable, coefs []float64) string {
+	sums := make(map[int]float64, len(vars))
+	for i, v := range vars {
+		sums[v.index] += coefs[i]
+	}
+	cols := make([]int, 0, len(sums))
+	for col, coef := range sums {
+		if coef != 0 {
+			cols = append(cols, col)
+		}
+	}
+	sort.Ints(cols)
+
+	inf := float64(C.get_infinite(model.prob))
+	bound := func(f float64) string {
+		switch {
+		case f >= inf:
+			f = math.Inf(1)
+		case f <= -inf:
+			f = math.Inf(-1)
+		case f == 0:
+			f = 0 // not -0
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	var b strings.Builder
+	b.WriteString(bound(lower))
+	b.WriteByte(' ')
+	b.WriteString(bound(upper))
+	for _, col := range cols {
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(col))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatFloat(sums[col], 'g', -1, 64))
+	}
+
+	return b.String()
+}
+
+// matchesRow reports whether the given constraint still has the terms and
+// bounds identified by key, i.e. it wasn't modified since it was added.
+// Spooled constraints cannot be modified, so they always match. The caller
+// must hold at least the model's read lock.
+func (model *Model) matchesRow(c *Constraint, key string) bool {
+	if c.index+1 > int(C.get_Nrows(model.prob)) {
+		return true
+	}
+
+	return model.rowKey(c) == key
+}
+
+// rowKey returns the key of the constraint's current terms and bounds. The
+// caller must hold at least the model's read lock.
+func (model *Model) rowKey(c *Constraint) string {
+	row := c.index + 1
+	lower, upper := model.rowBounds(row)
+	vars, coefs := model.rowTerms(row)
+
+	return model.constraintKey(lower, upper, vars, coefs)
+}
@@ -0,0 +1,279 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// maxDiagnosticsBasisSize limits the size of bases analyzed by
+	// Diagnostics, since the analysis uses dense matrices.
+	maxDiagnosticsBasisSize = 500
+
+	diagnosticsResidualWarning   = 1e-6
+	diagnosticsConditionWarning  = 1e12
+	diagnosticsSmallPivotWarning = 1e-11
+)
+
+// Diagnostics is a report on the numerical quality of a solution, as
+// returned by SolveResult.Diagnostics.
+type Diagnostics struct {
+	// Residuals holds the amount by which each violated constraint is
+	// violated by the solution.
+	Residuals map[*Constraint]float64
+	// MaxResidual is the largest value in Residuals.
+	MaxResidual float64
+	// MaxBoundViolation is the largest amount by which a variable violates
+	// its bounds.
+	MaxBoundViolation float64
+
+	// MinCoefficient and MaxCoefficient are the smallest and largest
+	// absolute values of non-zero constraint coefficients.
+	MinCoefficient, MaxCoefficient float64
+
+	// BasisAnalyzed reports whether the following basis-related fields were
+	// computed. This is only done for models with up to 500 constraints.
+	BasisAnalyzed bool
+	// ConditionEstimate is the 1-norm condition number of the final basis.
+	ConditionEstimate float64
+	// LargestPivot and SmallestPivot are the largest and smallest absolute
+	// pivots in an LU factorization of the final basis.
+	LargestPivot, SmallestPivot float64
+
+	// Warnings lists human-readable descriptions of potential problems with
+	// the solution.
+	Warnings []string
+}
+
+// Suspicious reports whether any potential problems were found.
+func (d Diagnostics) Suspicious() bool {
+	return len(d.Warnings) > 0
+}
+
+// Diagnostics analyzes the numerical quality of the result. Like all other
+// result methods, it must be called before the model is modified or solved
+// again.
+func (res SolveResult) Diagnostics() Diagnostics {
+	data := res.model.readData()
+
+	values := make([]float64, len(data.vars))
+	for i, v := range res.model.Variables() {
+		values[i] = res.scaledValue(v)
+	}
+
+	d := Diagnostics{
+		Residuals:      make(map[*Constraint]float64),
+		MinCoefficient: math.Inf(1),
+	}
+
+	for i, v := range data.vars {
+		violation := math.Max(v.lower-values[i], values[i]-v.upper)
+		d.MaxBoundViolation = math.Max(d.MaxBoundViolation, violation)
+	}
+
+	for i, c := range data.cons {
+		var activity float64
+		for j, col := range c.cols {
+			activity += c.coefs[j] * values[col]
+
+			if abs := math.Abs(c.coefs[j]); abs != 0 {
+				d.MinCoefficient = math.Min(d.MinCoefficient, abs)
+				d.MaxCoefficient = math.Max(d.MaxCoefficient, abs)
+			}
+		}
+
+		if residual := math.Max(c.lower-activity, activity-c.upper); residual > 0 {
+			d.Residuals[res.model.cons[i]] = residual
+			d.MaxResidual = math.Max(d.MaxResidual, residual)
+		}
+	}
+
+	if math.IsInf(d.MinCoefficient, 1) {
+		d.MinCoefficient = 0
+	}
+
+	if len(data.cons) > 0 && len(data.cons) <= maxDiagnosticsBasisSize {
+		if basis := res.basisMatrix(data); basis != nil {
+			d.BasisAnalyzed = true
+			d.LargestPivot, d.SmallestPivot, d.ConditionEstimate = analyzeBasis(basis)
+		}
+	}
+
+	if d.MaxResidual > diagnosticsResidualWarning {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("constraints violated by up to %g", d.MaxResidual))
+	}
+	if d.MaxBoundViolation > diagnosticsResidualWarning {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("variable bounds violated by up to %g", d.MaxBoundViolation))
+	}
+	if d.MinCoefficient > 0 && d.MaxCoefficient/d.MinCoefficient > DefaultMaxCoefficientRatio {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("coefficients span %g to %g; consider rescaling", d.MinCoefficient, d.MaxCoefficient))
+	}
+	if d.BasisAnalyzed && d.ConditionEstimate > diagnosticsConditionWarning {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("final basis is ill-conditioned (condition estimate %g)", d.ConditionEstimate))
+	}
+	if d.BasisAnalyzed && d.SmallestPivot < diagnosticsSmallPivotWarning {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("final basis is near-singular (smallest pivot %g)", d.SmallestPivot))
+	}
+
+	return d
+}
+
+// basisMatrix returns the final basis as a dense, column-major matrix, or nil
+// if it cannot be retrieved.
+func (res SolveResult) basisMatrix(data *modelData) [][]float64 {
+	m := len(data.cons)
+
+	bascolumn := make([]C.int, m+1)
+
+	res.model.mu.RLock()
+	ok := C.get_basis(res.model.prob, &bascolumn[0], C.FALSE) == C.TRUE
+	res.model.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	type entry struct {
+		row  int
+		coef float64
+	}
+	columns := make([][]entry, len(data.vars))
+	for i, c := range data.cons {
+		for n, j := range c.cols {
+			columns[j] = append(columns[j], entry{i, c.coefs[n]})
+		}
+	}
+
+	// the row activities are modeled as r = Ax, so the basis consists of
+	// columns of [I A]
+	basis := make([][]float64, m)
+	for k := 1; k <= m; k++ {
+		index := int(bascolumn[k])
+		if index < 0 {
+			index = -index
+		}
+
+		col := make([]float64, m)
+		if index <= m {
+			col[index-1] = 1
+		} else {
+			for _, e := range columns[index-m-1] {
+				col[e.row] += e.coef
+			}
+		}
+		basis[k-1] = col
+	}
+
+	return basis
+}
+
+// analyzeBasis factorizes the given column-major matrix using Gaussian
+// elimination with partial pivoting and returns its largest and smallest
+// absolute pivots and its 1-norm condition number.
+func analyzeBasis(basis [][]float64) (largest, smallest, condition float64) {
+	m := len(basis)
+
+	// row-major copy, which is factorized in-place
+	lu := make([][]float64, m)
+	for i := range lu {
+		lu[i] = make([]float64, m)
+		for j := range lu[i] {
+			lu[i][j] = basis[j][i]
+		}
+	}
+
+	var norm float64
+	for _, col := range basis {
+		var sum float64
+		for _, v := range col {
+			sum += math.Abs(v)
+		}
+		norm = math.Max(norm, sum)
+	}
+
+	perm := make([]int, m)
+	for i := range perm {
+		perm[i] = i
+	}
+
+	smallest = math.Inf(1)
+	for k := 0; k < m; k++ {
+		p := k
+		for i := k + 1; i < m; i++ {
+			if math.Abs(lu[i][k]) > math.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		lu[k], lu[p] = lu[p], lu[k]
+		perm[k], perm[p] = perm[p], perm[k]
+
+		pivot := math.Abs(lu[k][k])
+		largest = math.Max(largest, pivot)
+		smallest = math.Min(smallest, pivot)
+
+		if pivot == 0 {
+			return largest, 0, math.Inf(1)
+		}
+
+		for i := k + 1; i < m; i++ {
+			lu[i][k] /= lu[k][k]
+			for j := k + 1; j < m; j++ {
+				lu[i][j] -= lu[i][k] * lu[k][j]
+			}
+		}
+	}
+
+	// 1-norm of the inverse, column by column
+	var invNorm float64
+	x := make([]float64, m)
+	for col := 0; col < m; col++ {
+		for i := 0; i < m; i++ {
+			x[i] = 0
+			if perm[i] == col {
+				x[i] = 1
+			}
+		}
+		// forward substitution with unit lower triangle
+		for i := 0; i < m; i++ {
+			for j := 0; j < i; j++ {
+				x[i] -= lu[i][j] * x[j]
+			}
+		}
+		// backward substitution
+		for i := m - 1; i >= 0; i-- {
+			for j := i + 1; j < m; j++ {
+				x[i] -= lu[i][j] * x[j]
+			}
+			x[i] /= lu[i][i]
+		}
+
+		var sum float64
+		for _, v := range x {
+			sum += math.Abs(v)
+		}
+		invNorm = math.Max(invNorm, sum)
+	}
+
+	return largest, smallest, norm * invNorm
+}
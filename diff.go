@@ -0,0 +1,252 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ModelDiff describes the structural differences between two models, as
+// returned by Model.Diff. Entries are human-readable descriptions of the
+// variable or constraint in question.
+type ModelDiff struct {
+	DirectionChanged bool
+
+	AddedVariables   []string
+	RemovedVariables []string
+	ChangedVariables []string
+
+	AddedConstraints   []string
+	RemovedConstraints []string
+	ChangedConstraints []string
+}
+
+// Empty reports whether the diff contains no differences at all.
+func (d ModelDiff) Empty() bool {
+	return !d.DirectionChanged &&
+		len(d.AddedVariables) == 0 && len(d.RemovedVariables) == 0 && len(d.ChangedVariables) == 0 &&
+		len(d.AddedConstraints) == 0 && len(d.RemovedConstraints) == 0 && len(d.ChangedConstraints) == 0
+}
+
+// String returns the diff in a format similar to unified diffs: removed
+// entries are prefixed with "-", added entries with "+" and changed entries
+// with "~".
+func (d ModelDiff) String() string {
+	var b strings.Builder
+
+	if d.DirectionChanged {
+		b.WriteString("~ direction\n")
+	}
+
+	for _, section := range []struct {
+		prefix  string
+		entries []string
+	}{
+		{"- variable ", d.RemovedVariables},
+		{"+ variable ", d.AddedVariables},
+		{"~ variable ", d.ChangedVariables},
+		{"- constraint ", d.RemovedConstraints},
+		{"+ constraint ", d.AddedConstraints},
+		{"~ constraint ", d.ChangedConstraints},
+	} {
+		for _, entry := range section.entries {
+			b.WriteString(section.prefix)
+			b.WriteString(entry)
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// Diff compares the formulation of the model with another model and reports
+// the variables and constraints added to, removed from or changed in other.
+//
+// Variables are matched by name. Explicitly named constraints are matched by
+// name; other constraints are matched by the set of variables they use, so a
+// changed coefficient or bound in an unnamed constraint is reported as a
+// change, while a constraint using different variables is reported as
+// removed and added.
+// The model names and the order of variables, constraints and terms are
+// ignored.
+func (model *Model) Diff(other *Model) ModelDiff {
+	return diffData(model.readData(), other.readData(), 0)
+}
+
+// Equal reports whether both models have the same formulation, with all
+// coefficients and bounds within tol of each other. See Diff for how
+// variables and constraints are matched.
+func (model *Model) Equal(other *Model, tol float64) bool {
+	return diffData(model.readData(), other.readData(), tol).Empty()
+}
+
+func diffData(a, b *modelData, tol float64) ModelDiff {
+	var d ModelDiff
+
+	d.DirectionChanged = a.maximize != b.maximize
+
+	aVars, bVars := groupIndices(len(a.vars), func(i int) string {
+		return a.vars[i].name
+	}), groupIndices(len(b.vars), func(i int) string {
+		return b.vars[i].name
+	})
+
+	for _, key := range unionKeys(aVars, bVars) {
+		as, bs := aVars[key], bVars[key]
+		for i := 0; i < len(as) || i < len(bs); i++ {
+			switch {
+			case i >= len(bs):
+				d.RemovedVariables = append(d.RemovedVariables, key)
+			case i >= len(as):
+				d.AddedVariables = append(d.AddedVariables, key)
+			default:
+				if change := diffVar(a.vars[as[i]], b.vars[bs[i]], tol); change != "" {
+					d.ChangedVariables = append(d.ChangedVariables, key+": "+change)
+				}
+			}
+		}
+	}
+
+	constraintKey := func(data *modelData) func(int) string {
+		return func(i int) string {
+			c := data.cons[i]
+			if c.name != "" {
+				return "name:" + c.name
+			}
+
+			names := make([]string, len(c.cols))
+			for j, col := range c.cols {
+				names[j] = data.vars[col].name
+			}
+			sort.Strings(names)
+
+			return "vars:" + strings.Join(names, "\x00")
+		}
+	}
+
+	aCons, bCons := groupIndices(len(a.cons), constraintKey(a)), groupIndices(len(b.cons), constraintKey(b))
+
+	for _, key := range unionKeys(aCons, bCons) {
+		as, bs := aCons[key], bCons[key]
+
+		// pair unnamed constraints using the same variables deterministically
+		sort.Slice(as, func(i, j int) bool {
+			return a.canonicalConstraint(a.cons[as[i]]) < a.canonicalConstraint(a.cons[as[j]])
+		})
+		sort.Slice(bs, func(i, j int) bool {
+			return b.canonicalConstraint(b.cons[bs[i]]) < b.canonicalConstraint(b.cons[bs[j]])
+		})
+
+		for i := 0; i < len(as) || i < len(bs); i++ {
+			switch {
+			case i >= len(bs):
+				d.RemovedConstraints = append(d.RemovedConstraints, a.canonicalConstraint(a.cons[as[i]]))
+			case i >= len(as):
+				d.AddedConstraints = append(d.AddedConstraints, b.canonicalConstraint(b.cons[bs[i]]))
+			default:
+				ca, cb := a.cons[as[i]], b.cons[bs[i]]
+				if !constraintsEqual(a, b, ca, cb, tol) {
+					d.ChangedConstraints = append(d.ChangedConstraints,
+						a.canonicalConstraint(ca)+" -> "+b.canonicalConstraint(cb))
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+func diffVar(a, b varData, tol float64) string {
+	var changes []string
+
+	if a.typ != b.typ {
+		changes = append(changes, fmt.Sprintf("type %s -> %s", variableTypeNames[a.typ], variableTypeNames[b.typ]))
+	}
+	if !floatsEqual(a.lower, b.lower, tol) || !floatsEqual(a.upper, b.upper, tol) {
+		changes = append(changes, fmt.Sprintf("bounds [%s, %s] -> [%s, %s]",
+			canonicalFloat(a.lower), canonicalFloat(a.upper), canonicalFloat(b.lower), canonicalFloat(b.upper)))
+	}
+	if !floatsEqual(a.obj, b.obj, tol) {
+		changes = append(changes, fmt.Sprintf("objective coefficient %s -> %s", canonicalFloat(a.obj), canonicalFloat(b.obj)))
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+func constraintsEqual(a, b *modelData, ca, cb conData, tol float64) bool {
+	if !floatsEqual(ca.lower, cb.lower, tol) || !floatsEqual(ca.upper, cb.upper, tol) {
+		return false
+	}
+
+	termsA := make(map[string]float64, len(ca.cols))
+	for i, col := range ca.cols {
+		termsA[a.vars[col].name] += ca.coefs[i]
+	}
+	termsB := make(map[string]float64, len(cb.cols))
+	for i, col := range cb.cols {
+		termsB[b.vars[col].name] += cb.coefs[i]
+	}
+
+	for name, coef := range termsA {
+		if !floatsEqual(coef, termsB[name], tol) {
+			return false
+		}
+	}
+	for name, coef := range termsB {
+		if !floatsEqual(coef, termsA[name], tol) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func floatsEqual(a, b, tol float64) bool {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return math.Abs(a-b) <= tol
+}
+
+// groupIndices groups the indices 0..n-1 by the given key function.
+func groupIndices(n int, key func(int) string) map[string][]int {
+	groups := make(map[string][]int)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		groups[k] = append(groups[k], i)
+	}
+	return groups
+}
+
+// unionKeys returns the sorted union of both maps' keys.
+func unionKeys(a, b map[string][]int) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dual returns a new model with the LP dual of the model. Integer and
+// binary variables are treated as continuous, i.e. the dual of the model's
+// LP relaxation is returned.
+//
+// The dual has one constraint per variable of the model, named after the
+// respective variable, and one variable per finite bound of each of the
+// model's constraints and variables. These are named after the originating
+// constraint or variable, with the suffixes "_lo" and "_up" for lower and
+// upper bounds, or no suffix for equality constraints and fixed variables.
+// Unnamed constraints are referred to by their default name (R1, R2, …).
+//
+// Minimization problems result in maximization duals and vice-versa. The
+// optimal objective values of a model and its dual are equal. An error is
+// returned if the underlying library fails to build the dual.
+func (model *Model) Dual() (*Model, error) {
+	data := model.readData()
+
+	dir := Maximize
+	if data.maximize {
+		dir = Minimize
+	}
+
+	dual, err := NewModel(data.name+"_dual", dir, WithLogger(model.logger))
+	if err != nil {
+		return nil, err
+	}
+
+	// sign for the terms related to lower bounds; terms related to upper
+	// bounds get the opposite sign.
+	lowerSign := 1.0
+	if data.maximize {
+		lowerSign = -1.0
+	}
+
+	colVars := make([][]*Variable, len(data.vars))
+	colCoefs := make([][]float64, len(data.vars))
+
+	// addBoundVars adds the dual variables for a primal constraint or bound
+	// with the given coefficients for each primal variable
+	addBoundVars := func(name string, lower, upper float64, cols []int, coefs []float64) error {
+		var duals []*Variable
+		var signs []float64
+
+		add := func(name string, obj, lower, sign float64) error {
+			v, err := dual.AddDefinedVariable(name, ContinuousVariable, obj, lower, math.Inf(1))
+			if err != nil {
+				return err
+			}
+			duals, signs = append(duals, v), append(signs, sign)
+			return nil
+		}
+
+		switch {
+		case lower == upper:
+			if err := add(name, lower, math.Inf(-1), 1); err != nil {
+				return err
+			}
+		default:
+			if !math.IsInf(lower, 0) {
+				if err := add(name+"_lo", lowerSign*lower, 0, lowerSign); err != nil {
+					return err
+				}
+			}
+			if !math.IsInf(upper, 0) {
+				if err := add(name+"_up", -lowerSign*upper, 0, -lowerSign); err != nil {
+					return err
+				}
+			}
+		}
+
+		for k, v := range duals {
+			for j, col := range cols {
+				colVars[col] = append(colVars[col], v)
+				colCoefs[col] = append(colCoefs[col], signs[k]*coefs[j])
+			}
+		}
+		return nil
+	}
+
+	for i, c := range data.cons {
+		name := c.name
+		if name == "" {
+			name = fmt.Sprintf("R%d", i+1)
+		}
+
+		if err := addBoundVars(name, c.lower, c.upper, c.cols, c.coefs); err != nil {
+			return nil, err
+		}
+	}
+
+	for j, v := range data.vars {
+		if err := addBoundVars(v.name, v.lower, v.upper, []int{j}, []float64{1}); err != nil {
+			return nil, err
+		}
+	}
+
+	for j, v := range data.vars {
+		c, err := dual.AddConstraint(v.obj, v.obj, colVars[j], colCoefs[j])
+		if err != nil {
+			return nil, err
+		}
+		c.SetName(v.name)
+	}
+
+	return dual, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import "fmt"
+
+// ErrDimensionMismatch is returned when the number of variables and
+// coefficients given for a linear expression differ.
+type ErrDimensionMismatch struct {
+	Got, Want int // number of coefficients and variables, respectively
+}
+
+func (e ErrDimensionMismatch) Error() string {
+	return fmt.Sprintf("inconsistent number of variables and coefficients: %d != %d", e.Want, e.Got)
+}
+
+// ErrInvalidVariable is returned when a variable given for a linear
+// expression is nil or does not belong to the model.
+type ErrInvalidVariable struct {
+	Position int    // position of the variable in the given slice
+	Model    string // name of the model
+}
+
+func (e ErrInvalidVariable) Error() string {
+	return fmt.Sprintf("variable %d is nil or does not belong to model %q", e.Position, e.Model)
+}
+
+// ErrInvalidCoefficient is returned when a coefficient is NaN or infinite.
+type ErrInvalidCoefficient struct {
+	Variable string // name of the variable
+	Index    int    // 0-based index of the variable in the model
+	Value    float64
+}
+
+func (e ErrInvalidCoefficient) Error() string {
+	return fmt.Sprintf("coefficient of %q (variable %d) is not finite: %g", e.Variable, e.Index, e.Value)
+}
+
+// ErrInvalidBounds is returned when bounds are NaN, the lower bound
+// exceeds the upper bound or both bounds are the same infinity.
+type ErrInvalidBounds struct {
+	Lower, Upper float64
+}
+
+func (e ErrInvalidBounds) Error() string {
+	return fmt.Sprintf("invalid bounds [%g, %g]", e.Lower, e.Upper)
+}
+
+// ErrConstraint adds the context of the constraint being added to an error.
+type ErrConstraint struct {
+	Index int    // 0-based index the constraint would have had
+	Name  string // name of the constraint, if known
+	Err   error
+}
+
+func (e ErrConstraint) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("constraint %q (%d): %v", e.Name, e.Index, e.Err)
+	}
+	return fmt.Sprintf("constraint %d: %v", e.Index, e.Err)
+}
+
+func (e ErrConstraint) Unwrap() error {
+	return e.Err
+}
+
+// ErrVariable adds the context of the variable being added to an error.
+type ErrVariable struct {
+	Name string
+	Err  error
+}
+
+func (e ErrVariable) Error() string {
+	return fmt.Sprintf("variable %q: %v", e.Name, e.Err)
+}
+
+func (e ErrVariable) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownObjective is returned when solving for an objective which was
+// not added with AddObjective.
+type ErrUnknownObjective struct {
+	Name string
+}
+
+func (e ErrUnknownObjective) Error() string {
+	return fmt.Sprintf("unknown objective %q", e.Name)
+}
+
+// ErrDuplicateName is returned when adding a variable with the name of an
+// existing one to a model rejecting duplicate names.
+type ErrDuplicateName struct {
+	Name string
+}
+
+func (e ErrDuplicateName) Error() string {
+	return fmt.Sprintf("duplicate variable name %q", e.Name)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package exact solves linear programs in exact rational arithmetic.
+//
+// Models are read through golpa's public API and solved by a pure Go
+// simplex implementation on math/big.Rat, independently of lp_solve. Every
+// float64 in the model is converted to the rational number it exactly
+// represents, so the results are the exact optima of the model as stored,
+// free of rounding errors. This is orders of magnitude slower than lp_solve
+// and only meant for small models, e.g. to verify floating point results.
+package exact
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/costela/golpa"
+)
+
+// Result holds the exact optimal solution of a model.
+type Result struct {
+	objective *big.Rat
+	values    map[*golpa.Variable]*big.Rat
+}
+
+// ObjectiveValue returns the optimal value of the objective function.
+func (res *Result) ObjectiveValue() *big.Rat {
+	return new(big.Rat).Set(res.objective)
+}
+
+// Value returns the value of the given variable in the optimal solution.
+func (res *Result) Value(v *golpa.Variable) *big.Rat {
+	value, ok := res.values[v]
+	if !ok {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(value)
+}
+
+// Solve finds an exact optimal solution of the given model, which must only
+// have continuous variables.
+//
+// If the model is infeasible or unbounded, golpa.ErrModelInfeasible or
+// golpa.ErrModelUnbounded are returned, respectively.
+func Solve(model *golpa.Model) (*Result, error) {
+	vars := model.Variables()
+
+	// each variable x is substituted by non-negative columns: x = l + y,
+	// x = u - y or, if free, x = y1 - y2
+	type substitution struct {
+		offset *big.Rat
+		cols   []int
+		signs  []int
+	}
+	subs := make([]substitution, len(vars))
+	index := make(map[*golpa.Variable]int, len(vars))
+	var p problem
+	for i, v := range vars {
+		index[v] = i
+		if v.Type() != golpa.ContinuousVariable {
+			return nil, fmt.Errorf("variable %q is not continuous", v.Name())
+		}
+
+		lower, upper := v.Bounds()
+		switch {
+		case !math.IsInf(lower, -1):
+			col := p.addColumn()
+			subs[i] = substitution{rat(lower), []int{col}, []int{1}}
+			if !math.IsInf(upper, 1) {
+				p.addRow(map[int]*big.Rat{col: big.NewRat(1, 1)}, lessEqual, new(big.Rat).Sub(rat(upper), rat(lower)))
+			}
+		case !math.IsInf(upper, 1):
+			subs[i] = substitution{rat(upper), []int{p.addColumn()}, []int{-1}}
+		default:
+			subs[i] = substitution{new(big.Rat), []int{p.addColumn(), p.addColumn()}, []int{1, -1}}
+		}
+	}
+
+	// substitute expr = sum coefs[k]*vars[k] into the columns, returning the
+	// resulting coefficients and constant
+	substitute := func(vs []*golpa.Variable, coefs []float64) (map[int]*big.Rat, *big.Rat) {
+		row := make(map[int]*big.Rat)
+		constant := new(big.Rat)
+		for k, v := range vs {
+			coef := rat(coefs[k])
+			sub := subs[index[v]]
+			constant.Add(constant, new(big.Rat).Mul(coef, sub.offset))
+			for j, col := range sub.cols {
+				if row[col] == nil {
+					row[col] = new(big.Rat)
+				}
+				term := new(big.Rat).Mul(coef, big.NewRat(int64(sub.signs[j]), 1))
+				row[col].Add(row[col], term)
+			}
+		}
+		return row, constant
+	}
+
+	for _, c := range model.Constraints() {
+		cvars, coefs := c.Terms()
+		row, constant := substitute(cvars, coefs)
+		lower, upper := c.Bounds()
+		switch {
+		case lower == upper:
+			p.addRow(row, equal, new(big.Rat).Sub(rat(lower), constant))
+		default:
+			if !math.IsInf(lower, -1) {
+				p.addRow(row, greaterEqual, new(big.Rat).Sub(rat(lower), constant))
+			}
+			if !math.IsInf(upper, 1) {
+				p.addRow(row, lessEqual, new(big.Rat).Sub(rat(upper), constant))
+			}
+		}
+	}
+
+	objCoefs := make([]float64, len(vars))
+	for i, v := range vars {
+		objCoefs[i] = v.Coefficient()
+	}
+	cost, _ := substitute(vars, objCoefs)
+	if model.Direction() == golpa.Maximize {
+		for _, c := range cost {
+			c.Neg(c)
+		}
+	}
+	p.cost = cost
+
+	y, err := p.solve()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		objective: new(big.Rat),
+		values:    make(map[*golpa.Variable]*big.Rat, len(vars)),
+	}
+	for i, v := range vars {
+		value := new(big.Rat).Set(subs[i].offset)
+		for j, col := range subs[i].cols {
+			term := new(big.Rat).Mul(y[col], big.NewRat(int64(subs[i].signs[j]), 1))
+			value.Add(value, term)
+		}
+		res.values[v] = value
+		res.objective.Add(res.objective, new(big.Rat).Mul(rat(objCoefs[i]), value))
+	}
+
+	return res, nil
+}
+
+// rat returns the rational number exactly represented by f.
+func rat(f float64) *big.Rat {
+	return new(big.Rat).SetFloat64(f)
+}
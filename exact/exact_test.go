@@ -0,0 +1,88 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package exact
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/costela/golpa/golpatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.000001
+
+func TestSolve(t *testing.T) {
+	model, err := golpa.NewModel("exact", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", golpa.ContinuousVariable, 1, 0, math.Inf(1))
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", golpa.ContinuousVariable, 1, math.Inf(-1), 2)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 1, []*golpa.Variable{x, y}, []float64{3, 3})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(1, 1, []*golpa.Variable{x, y}, []float64{1, -2})
+	require.NoError(t, err)
+
+	res, err := Solve(model)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 3), res.ObjectiveValue())
+	assert.Equal(t, big.NewRat(5, 9), res.Value(x))
+	assert.Equal(t, big.NewRat(-2, 9), res.Value(y))
+}
+
+func TestSolveMatchesLPSolve(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		model, err := golpatest.RandomLP(rng, 5, 4, 0.6)
+		require.NoError(t, err)
+
+		res, err := Solve(model)
+		require.NoError(t, err)
+		lp, err := model.Solve()
+		require.NoError(t, err)
+
+		objective, _ := res.ObjectiveValue().Float64()
+		assert.InDelta(t, lp.ObjectiveValue(), objective, delta)
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	model, err := golpa.NewModel("errors", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", golpa.ContinuousVariable, 1, 0, math.Inf(1))
+	require.NoError(t, err)
+
+	_, err = Solve(model)
+	assert.ErrorIs(t, err, golpa.ErrModelUnbounded)
+
+	c, err := model.AddConstraint(math.Inf(-1), -1, []*golpa.Variable{x}, []float64{1})
+	require.NoError(t, err)
+	_, err = Solve(model)
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+
+	c.SetBounds(math.Inf(-1), 1)
+	x.SetType(golpa.IntegerVariable)
+	_, err = Solve(model)
+	assert.Error(t, err)
+}
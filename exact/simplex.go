@@ -0,0 +1,254 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package exact
+
+import (
+	"math/big"
+
+	"github.com/costela/golpa"
+)
+
+// sense is the relation between a row's left and right-hand sides.
+type sense int
+
+const (
+	lessEqual sense = iota
+	greaterEqual
+	equal
+)
+
+type row struct {
+	coefs map[int]*big.Rat
+	sense sense
+	rhs   *big.Rat
+}
+
+// problem is a linear program in standard form: minimize cost·y subject to
+// the rows and y >= 0.
+type problem struct {
+	cols int
+	rows []row
+	cost map[int]*big.Rat
+}
+
+// addColumn adds a non-negative column and returns its index.
+func (p *problem) addColumn() int {
+	p.cols++
+	return p.cols - 1
+}
+
+func (p *problem) addRow(coefs map[int]*big.Rat, s sense, rhs *big.Rat) {
+	p.rows = append(p.rows, row{coefs, s, rhs})
+}
+
+// tableau is a dense simplex tableau. The last entry of each row holds its
+// right-hand side.
+type tableau struct {
+	rows  [][]*big.Rat
+	basis []int
+}
+
+// solve returns an optimal solution of the problem, using the two-phase
+// simplex method with Bland's rule, which cannot cycle.
+func (p *problem) solve() ([]*big.Rat, error) {
+	// columns: structural, then one slack per inequality, then one
+	// artificial per row (unless its slack can start in the basis)
+	slacks, artificials := 0, 0
+	for _, r := range p.rows {
+		if r.sense != equal {
+			slacks++
+		}
+	}
+	n := p.cols + slacks + len(p.rows) // unneeded artificial columns stay empty
+	t := &tableau{basis: make([]int, len(p.rows))}
+	isArtificial := make(map[int]bool)
+
+	slack := p.cols
+	artificial := p.cols + slacks
+	for i, r := range p.rows {
+		tr := make([]*big.Rat, n+1)
+		for j := range tr {
+			tr[j] = new(big.Rat)
+		}
+		for col, coef := range r.coefs {
+			tr[col].Set(coef)
+		}
+		tr[n].Set(r.rhs)
+
+		switch r.sense {
+		case lessEqual:
+			tr[slack].SetInt64(1)
+		case greaterEqual:
+			tr[slack].SetInt64(-1)
+		}
+
+		// make the right-hand side non-negative
+		if tr[n].Sign() < 0 {
+			for _, x := range tr {
+				x.Neg(x)
+			}
+		}
+
+		if r.sense != equal && tr[slack].Sign() > 0 {
+			t.basis[i] = slack
+		} else {
+			tr[artificial].SetInt64(1)
+			t.basis[i] = artificial
+			isArtificial[artificial] = true
+			artificial++
+			artificials++
+		}
+		if r.sense != equal {
+			slack++
+		}
+		t.rows = append(t.rows, tr)
+	}
+
+	// phase 1: minimize the sum of artificials
+	if artificials > 0 {
+		phase1 := make(map[int]*big.Rat, artificials)
+		for col := range isArtificial {
+			phase1[col] = big.NewRat(1, 1)
+		}
+		if !t.optimize(phase1, n, nil) {
+			panic("phase 1 cannot be unbounded")
+		}
+		if t.value(phase1, n).Sign() > 0 {
+			return nil, golpa.ErrModelInfeasible
+		}
+
+		// drive remaining (zero) artificials out of the basis; rows where
+		// this is impossible are redundant and keep their artificial
+		for i, b := range t.basis {
+			if !isArtificial[b] {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if !isArtificial[j] && t.rows[i][j].Sign() != 0 {
+					t.pivot(i, j)
+					break
+				}
+			}
+		}
+	}
+
+	// phase 2: minimize the actual cost, never letting artificials re-enter
+	if !t.optimize(p.cost, n, isArtificial) {
+		return nil, golpa.ErrModelUnbounded
+	}
+
+	y := make([]*big.Rat, p.cols)
+	for j := range y {
+		y[j] = new(big.Rat)
+	}
+	for i, b := range t.basis {
+		if b < p.cols {
+			y[b].Set(t.rows[i][n])
+		}
+	}
+	return y, nil
+}
+
+// optimize pivots until the given cost is minimal over the first n
+// columns, except the excluded ones. It returns false if the cost is
+// unbounded.
+func (t *tableau) optimize(cost map[int]*big.Rat, n int, excluded map[int]bool) bool {
+	costOf := func(j int) *big.Rat {
+		if c, ok := cost[j]; ok {
+			return c
+		}
+		return new(big.Rat)
+	}
+
+	for {
+		// Bland's rule: the first column with negative reduced cost enters
+		enter := -1
+		for j := 0; j < n && enter < 0; j++ {
+			if excluded[j] || t.isBasic(j) {
+				continue
+			}
+			reduced := new(big.Rat).Set(costOf(j))
+			for i, b := range t.basis {
+				reduced.Sub(reduced, new(big.Rat).Mul(costOf(b), t.rows[i][j]))
+			}
+			if reduced.Sign() < 0 {
+				enter = j
+			}
+		}
+		if enter < 0 {
+			return true
+		}
+
+		// ratio test, breaking ties by the lowest basic column
+		leave := -1
+		var best *big.Rat
+		for i, tr := range t.rows {
+			if tr[enter].Sign() <= 0 {
+				continue
+			}
+			ratio := new(big.Rat).Quo(tr[n], tr[enter])
+			if leave < 0 || ratio.Cmp(best) < 0 || ratio.Cmp(best) == 0 && t.basis[i] < t.basis[leave] {
+				leave, best = i, ratio
+			}
+		}
+		if leave < 0 {
+			return false
+		}
+
+		t.pivot(leave, enter)
+	}
+}
+
+// value returns the cost of the current basic solution.
+func (t *tableau) value(cost map[int]*big.Rat, n int) *big.Rat {
+	total := new(big.Rat)
+	for i, b := range t.basis {
+		if c, ok := cost[b]; ok {
+			total.Add(total, new(big.Rat).Mul(c, t.rows[i][n]))
+		}
+	}
+	return total
+}
+
+func (t *tableau) isBasic(j int) bool {
+	for _, b := range t.basis {
+		if b == j {
+			return true
+		}
+	}
+	return false
+}
+
+// pivot makes column j basic in row r.
+func (t *tableau) pivot(r, j int) {
+	pr := t.rows[r]
+	inv := new(big.Rat).Inv(pr[j])
+	for _, x := range pr {
+		x.Mul(x, inv)
+	}
+	for i, tr := range t.rows {
+		if i == r || tr[j].Sign() == 0 {
+			continue
+		}
+		factor := new(big.Rat).Set(tr[j])
+		for k, x := range tr {
+			x.Sub(x, new(big.Rat).Mul(factor, pr[k]))
+		}
+	}
+	t.basis[r] = j
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+)
+
+// Expr is a linear expression: a sum of variables multiplied by
+// coefficients, plus a constant. The zero value is the empty expression.
+//
+// Expressions are immutable: all methods return new expressions.
+type Expr struct {
+	vars     []*Variable
+	coefs    []float64
+	constant float64
+}
+
+// Term returns the expression coef * v.
+func Term(coef float64, v *Variable) Expr {
+	return Expr{
+		vars:  []*Variable{v},
+		coefs: []float64{coef},
+	}
+}
+
+// Plus returns the sum of both expressions.
+func (e Expr) Plus(other Expr) Expr {
+	vars := make([]*Variable, 0, len(e.vars)+len(other.vars))
+	coefs := make([]float64, 0, len(e.coefs)+len(other.coefs))
+	return Expr{
+		vars:     append(append(vars, e.vars...), other.vars...),
+		coefs:    append(append(coefs, e.coefs...), other.coefs...),
+		constant: e.constant + other.constant,
+	}
+}
+
+// PlusConstant returns the expression with the given constant added.
+func (e Expr) PlusConstant(constant float64) Expr {
+	e.constant += constant
+	return e
+}
+
+// Scale returns the expression multiplied by factor.
+func (e Expr) Scale(factor float64) Expr {
+	coefs := make([]float64, len(e.coefs))
+	for i, coef := range e.coefs {
+		coefs[i] = coef * factor
+	}
+	return Expr{
+		vars:     e.vars,
+		coefs:    coefs,
+		constant: e.constant * factor,
+	}
+}
+
+// Terms returns the variables in the expression and their coefficients.
+// Repeated variables are merged into a single term, in order of first
+// appearance.
+func (e Expr) Terms() (vars []*Variable, coefs []float64) {
+	seen := make(map[*Variable]int, len(e.vars))
+	for i, v := range e.vars {
+		if j, ok := seen[v]; ok {
+			coefs[j] += e.coefs[i]
+			continue
+		}
+		seen[v] = len(vars)
+		vars = append(vars, v)
+		coefs = append(coefs, e.coefs[i])
+	}
+	return vars, coefs
+}
+
+// Constant returns the constant part of the expression.
+func (e Expr) Constant() float64 {
+	return e.constant
+}
+
+// AddExprConstraint adds a constraint requiring the expression to lie
+// between lower and upper. The expression's constant is moved to the bounds.
+func (model *Model) AddExprConstraint(lower, upper float64, e Expr) (*Constraint, error) {
+	vars, coefs := e.Terms()
+	for _, v := range vars {
+		if v.model != model {
+			return nil, fmt.Errorf("variable does not belong to model")
+		}
+	}
+
+	return model.AddConstraint(lower-e.constant, upper-e.constant, vars, coefs)
+}
+
+// Sum returns the sum of the given variables.
+func Sum(vars ...*Variable) Expr {
+	return VariableSlice(vars).Sum()
+}
+
+// Dot returns the expression coefs[0]*vars[0] + coefs[1]*vars[1] + ....
+// It panics if both slices don't have the same length.
+func Dot(coefs []float64, vars []*Variable) Expr {
+	if len(coefs) != len(vars) {
+		panic(fmt.Sprintf("inconsistent number of coefficients and variables: %d != %d", len(coefs), len(vars)))
+	}
+	return Expr{
+		vars:  append([]*Variable(nil), vars...),
+		coefs: append([]float64(nil), coefs...),
+	}
+}
+
+// SetObjectiveExpr defines the objective function of the model as the
+// given expression. Variables not in the expression get a coefficient of 0.
+// The models don't support objective constants, so the expression's
+// constant must be 0.
+func (model *Model) SetObjectiveExpr(e Expr) error {
+	if e.constant != 0 {
+		return fmt.Errorf("objective constants are not supported")
+	}
+
+	vars, coefs := e.Terms()
+	for _, v := range vars {
+		if v.model != model {
+			return fmt.Errorf("variable does not belong to model")
+		}
+	}
+
+	for _, v := range model.Variables() {
+		v.SetObjectiveCoefficient(0)
+	}
+	return model.SetObjectiveFunction(coefs, vars)
+}
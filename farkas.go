@@ -0,0 +1,177 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+const certificateTolerance = 1e-9
+
+// InfeasibilityCertificate returns a Farkas certificate proving that the LP
+// relaxation of the model is infeasible. The certificate assigns a
+// multiplier to each constraint involved in the infeasibility (constraints
+// not in the returned map have a multiplier of zero).
+//
+// Adding up all constraints weighted by their multipliers results in a
+// single inequality which cannot be satisfied by any value within the
+// variables' bounds: with y being the multipliers and A the constraint
+// matrix, the lowest possible value of y·Ax implied by the constraints'
+// bounds is larger than the highest value of (yA)·x implied by the variable
+// bounds.
+//
+// It is only available for results returned along with ErrModelInfeasible.
+// If only the integer model is infeasible, but its LP relaxation is not, an
+// error is returned.
+func (res SolveResult) InfeasibilityCertificate() (map[*Constraint]float64, error) {
+	if res.status != SolutionInfeasible {
+		return nil, fmt.Errorf("infeasibility certificate is only available for infeasible results")
+	}
+
+	data := res.model.readData()
+
+	// Solve an "elastic" version of the model, where each constraint may be
+	// violated at a cost. The dual values of the constraints in this model's
+	// optimal solution form the certificate.
+	elastic, err := NewModel(data.name, Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]*Variable, len(data.vars))
+	for i, v := range data.vars {
+		vars[i], err = elastic.AddDefinedVariable(v.name, ContinuousVariable, 0, v.lower, v.upper)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cons := make([]*Constraint, len(data.cons))
+	for i, c := range data.cons {
+		over, err := elastic.AddDefinedVariable("", ContinuousVariable, 1, 0, math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		under, err := elastic.AddDefinedVariable("", ContinuousVariable, 1, 0, math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+
+		rowVars := make([]*Variable, 0, len(c.cols)+2)
+		for _, col := range c.cols {
+			rowVars = append(rowVars, vars[col])
+		}
+		rowVars = append(rowVars, over, under)
+		rowCoefs := append(append(make([]float64, 0, len(c.coefs)+2), c.coefs...), -1, 1)
+
+		cons[i], err = elastic.AddConstraint(c.lower, c.upper, rowVars, rowCoefs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	elasticRes, err := elastic.Solve()
+	if err != nil {
+		return nil, fmt.Errorf("solving elastic model: %w", err)
+	}
+
+	if elasticRes.ObjectiveValue() <= certificateTolerance {
+		return nil, fmt.Errorf("LP relaxation is feasible; infeasibility is caused by integrality")
+	}
+
+	multipliers := make([]float64, len(cons))
+	for i, c := range cons {
+		if y := elasticRes.ShadowPrice(c); math.Abs(y) > certificateTolerance {
+			multipliers[i] = y
+		}
+	}
+
+	// the sign convention for dual values depends on the solver's internals,
+	// so make sure we return a certificate proving infeasibility
+	switch {
+	case data.farkasGap(multipliers) > certificateTolerance:
+	case data.farkasGap(negated(multipliers)) > certificateTolerance:
+		multipliers = negated(multipliers)
+	default:
+		return nil, fmt.Errorf("could not derive a valid certificate from dual values")
+	}
+
+	certificate := make(map[*Constraint]float64)
+	for i, y := range multipliers {
+		if y != 0 {
+			certificate[res.model.cons[i]] = y
+		}
+	}
+
+	return certificate, nil
+}
+
+// farkasGap returns how far the given constraint multipliers are from
+// proving the infeasibility of the model's LP relaxation. A positive value
+// means the multipliers form a valid certificate.
+func (data *modelData) farkasGap(multipliers []float64) float64 {
+	// lowest value of y·Ax allowed by the constraints' bounds
+	var rowMin float64
+	combined := make([]float64, len(data.vars))
+	for i, c := range data.cons {
+		y := multipliers[i]
+		switch {
+		case y > 0:
+			rowMin += y * c.lower
+		case y < 0:
+			rowMin += y * c.upper
+		default:
+			continue
+		}
+		for j, col := range c.cols {
+			combined[col] += y * c.coefs[j]
+		}
+	}
+
+	// highest value of (yA)·x allowed by the variables' bounds
+	var colMax float64
+	for j, r := range combined {
+		if math.Abs(r) <= certificateTolerance {
+			// numerical noise; would otherwise meet an infinite bound
+			continue
+		}
+
+		switch {
+		case r > 0:
+			colMax += r * data.vars[j].upper
+		case r < 0:
+			colMax += r * data.vars[j].lower
+		}
+	}
+
+	gap := rowMin - colMax
+	if math.IsNaN(gap) {
+		return math.Inf(-1)
+	}
+
+	return gap
+}
+
+func negated(values []float64) []float64 {
+	neg := make([]float64, len(values))
+	for i, v := range values {
+		neg[i] = -v
+	}
+	return neg
+}
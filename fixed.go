@@ -0,0 +1,130 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxExactAmount is the largest magnitude of integers exactly representable
+// as float64, and thus of amounts used in models.
+const maxExactAmount = 1 << 53
+
+// FixedPoint describes decimal amounts with a fixed number of decimals,
+// such as currency amounts in cents. Models use amounts as integers in
+// minor units (e.g. 1999 for 19.99), which float64 coefficients and values
+// represent exactly, avoiding the rounding errors of decimal fractions like
+// 0.1 at the modeling boundary.
+type FixedPoint struct {
+	Decimals int
+}
+
+// Cents is the fixed point format of most currencies.
+var Cents = FixedPoint{Decimals: 2}
+
+// Parse converts a decimal number like "-19.99" to an amount in minor
+// units. Numbers with more decimals than the format are rejected, instead
+// of being rounded.
+func (f FixedPoint) Parse(s string) (int64, error) {
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	if !negative {
+		digits = strings.TrimPrefix(s, "+")
+	}
+
+	whole, frac := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, frac = digits[:i], digits[i+1:]
+	}
+	if whole == "" && frac == "" || strings.ContainsAny(whole+frac, "+-") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > f.Decimals {
+		return 0, fmt.Errorf("%q has more than %d decimals", s, f.Decimals)
+	}
+	frac += strings.Repeat("0", f.Decimals-len(frac))
+
+	amount, err := strconv.ParseInt("0"+whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if amount > maxExactAmount {
+		return 0, fmt.Errorf("amount %q is too large", s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// Format returns the amount given in minor units as a decimal number.
+func (f FixedPoint) Format(amount int64) string {
+	sign := ""
+	abs := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if f.Decimals <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= f.Decimals {
+		digits = strings.Repeat("0", f.Decimals-len(digits)+1) + digits
+	}
+	point := len(digits) - f.Decimals
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// DotAmounts returns the expression amounts[0]*vars[0] +
+// amounts[1]*vars[1] + ..., with amounts in minor units. An error is
+// returned if an amount is too large to be represented exactly.
+func DotAmounts(amounts []int64, vars []*Variable) (Expr, error) {
+	if len(amounts) != len(vars) {
+		return Expr{}, fmt.Errorf("inconsistent number of amounts and variables: %d != %d", len(amounts), len(vars))
+	}
+	coefs := make([]float64, len(amounts))
+	for i, amount := range amounts {
+		if amount > maxExactAmount || amount < -maxExactAmount {
+			return Expr{}, fmt.Errorf("amount %d is too large", amount)
+		}
+		coefs[i] = float64(amount)
+	}
+	return Dot(coefs, vars), nil
+}
+
+// Amount converts a value computed from amounts in minor units, e.g. an
+// objective value, back to an integer amount, rounding it to the nearest
+// minor unit. An error is returned if the value is not finite or too large
+// to be exact.
+func Amount(value float64) (int64, error) {
+	if math.IsNaN(value) || math.Abs(value) > maxExactAmount {
+		return 0, fmt.Errorf("value %g is not an exact amount", value)
+	}
+	return int64(math.Round(value)), nil
+}
+
+// ObjectiveAmount returns the objective value as an amount in minor units,
+// for objective functions built from amounts, e.g. with DotAmounts.
+func (res SolveResult) ObjectiveAmount() (int64, error) {
+	return Amount(res.ObjectiveValue())
+}
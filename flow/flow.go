@@ -0,0 +1,219 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package flow provides helpers for modeling network flow problems with
+// golpa.
+//
+// A Network is declared as a set of nodes with supplies and a set of arcs
+// with capacities and costs. Building it on a golpa.Model adds one variable
+// per arc and one flow conservation constraint per node. MaxFlow and
+// NewPath formulate maximum flows and cheapest paths on their own models.
+package flow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// balanceTolerance is the largest total supply imbalance accepted by Build.
+const balanceTolerance = 1e-9
+
+// Node is a node of a network.
+type Node struct {
+	name   string
+	supply float64
+	index  int
+}
+
+// Name returns the name of the node.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Supply returns the supply of the node. Negative values are demands.
+func (n *Node) Supply() float64 {
+	return n.supply
+}
+
+// Arc is a directed arc of a network.
+type Arc struct {
+	From, To *Node
+	capacity float64
+	cost     float64
+	index    int
+}
+
+// Capacity returns the maximum flow through the arc.
+func (a *Arc) Capacity() float64 {
+	return a.capacity
+}
+
+// Cost returns the cost per unit of flow through the arc.
+func (a *Arc) Cost() float64 {
+	return a.cost
+}
+
+// Network is a directed graph with node supplies and arc capacities and
+// costs.
+type Network struct {
+	nodes []*Node
+	arcs  []*Arc
+}
+
+// NewNetwork returns an empty network.
+func NewNetwork() *Network {
+	return &Network{}
+}
+
+// AddNode adds a node to the network. Positive supplies are sources of flow,
+// negative supplies are demands and nodes with zero supply are transshipment
+// nodes.
+func (n *Network) AddNode(name string, supply float64) *Node {
+	node := &Node{
+		name:   name,
+		supply: supply,
+		index:  len(n.nodes),
+	}
+	n.nodes = append(n.nodes, node)
+	return node
+}
+
+// AddArc adds a directed arc to the network. Use math.Inf(1) as capacity for
+// uncapacitated arcs.
+func (n *Network) AddArc(from, to *Node, capacity, cost float64) *Arc {
+	arc := &Arc{
+		From:     from,
+		To:       to,
+		capacity: capacity,
+		cost:     cost,
+		index:    len(n.arcs),
+	}
+	n.arcs = append(n.arcs, arc)
+	return arc
+}
+
+// Nodes returns the nodes of the network, in the order they were added.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Arcs returns the arcs of the network, in the order they were added.
+func (n *Network) Arcs() []*Arc {
+	return n.arcs
+}
+
+// Flow holds the variables and constraints added to a model by
+// Network.Build.
+type Flow struct {
+	vars         []*golpa.Variable
+	conservation []*golpa.Constraint
+}
+
+// Build adds the minimum cost flow formulation of the network to the given
+// model: one continuous variable per arc, bounded by the arc's capacity and
+// with the arc's cost as objective coefficient, and one constraint per node
+// requiring outgoing minus incoming flow to equal the node's supply.
+//
+// The model must be a minimization model and the supplies must add up to
+// zero. Other variables and constraints may be added to the model before or
+// after building the network.
+func (n *Network) Build(model *golpa.Model) (*Flow, error) {
+	if model.Direction() != golpa.Minimize {
+		return nil, fmt.Errorf("min-cost flow requires a minimization model")
+	}
+
+	total := 0.0
+	for _, node := range n.nodes {
+		total += node.supply
+	}
+	if math.Abs(total) > balanceTolerance {
+		return nil, fmt.Errorf("unbalanced network: total supply is %g", total)
+	}
+
+	f := &Flow{
+		vars:         make([]*golpa.Variable, len(n.arcs)),
+		conservation: make([]*golpa.Constraint, len(n.nodes)),
+	}
+
+	vars := make([][]*golpa.Variable, len(n.nodes))
+	coefs := make([][]float64, len(n.nodes))
+	used := make(map[string]bool, len(n.arcs))
+	for i, arc := range n.arcs {
+		// parallel arcs, or node names containing underscores, would
+		// otherwise give several arcs the same name
+		name := fmt.Sprintf("flow_%s_%s", arc.From.name, arc.To.name)
+		for used[name] {
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		used[name] = true
+		v, err := model.AddDefinedVariable(name, golpa.ContinuousVariable, arc.cost, 0, arc.capacity)
+		if err != nil {
+			return nil, err
+		}
+		f.vars[i] = v
+
+		if arc.From == arc.To {
+			// self-loops don't affect the balance of their node
+			continue
+		}
+
+		vars[arc.From.index] = append(vars[arc.From.index], v)
+		coefs[arc.From.index] = append(coefs[arc.From.index], 1)
+		vars[arc.To.index] = append(vars[arc.To.index], v)
+		coefs[arc.To.index] = append(coefs[arc.To.index], -1)
+	}
+
+	for i, node := range n.nodes {
+		c, err := model.AddConstraint(node.supply, node.supply, vars[i], coefs[i])
+		if err != nil {
+			return nil, err
+		}
+		c.SetName("balance_" + node.name)
+		f.conservation[i] = c
+	}
+
+	return f, nil
+}
+
+// Variable returns the model variable holding the flow through the given
+// arc.
+func (f *Flow) Variable(arc *Arc) *golpa.Variable {
+	return f.vars[arc.index]
+}
+
+// Conservation returns the flow conservation constraint of the given node.
+// Its shadow price is the node's potential.
+func (f *Flow) Conservation(node *Node) *golpa.Constraint {
+	return f.conservation[node.index]
+}
+
+// Value returns the flow through the given arc in a solution of the model.
+func (f *Flow) Value(res *golpa.SolveResult, arc *Arc) float64 {
+	return res.Value(f.vars[arc.index])
+}
+
+// Values returns the flow through every arc of the network in a solution of
+// the model, indexed like Network.Arcs.
+func (f *Flow) Values(res *golpa.SolveResult) []float64 {
+	values := make([]float64, len(f.vars))
+	for i, v := range f.vars {
+		values[i] = res.Value(v)
+	}
+	return values
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package flow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestMinCostFlow(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 4)
+	a := net.AddNode("a", 0)
+	b := net.AddNode("b", 0)
+	d := net.AddNode("t", -4)
+
+	sa := net.AddArc(s, a, 3, 1)
+	sb := net.AddArc(s, b, math.Inf(1), 4)
+	ab := net.AddArc(a, b, 2, 1)
+	at := net.AddArc(a, d, 2, 3)
+	bt := net.AddArc(b, d, 5, 1)
+
+	model, err := golpa.NewModel("flow", golpa.Minimize)
+	require.NoError(t, err)
+
+	f, err := net.Build(model)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, model.VariableCount())
+	assert.Equal(t, 4, model.ConstraintCount())
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	// s->a->b->t carries 2 at cost 3, s->a->t 1 at cost 4, s->b->t 1 at cost 5
+	assert.InDelta(t, 15, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 3, f.Value(res, sa), delta)
+	assert.InDelta(t, 1, f.Value(res, sb), delta)
+	assert.InDelta(t, 2, f.Value(res, ab), delta)
+	assert.InDelta(t, 1, f.Value(res, at), delta)
+	assert.InDelta(t, 3, f.Value(res, bt), delta)
+	assert.Len(t, f.Values(res), 5)
+}
+
+func TestBuildErrors(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 2)
+	d := net.AddNode("t", -1)
+	net.AddArc(s, d, 10, 1)
+
+	model, err := golpa.NewModel("flow", golpa.Minimize)
+	require.NoError(t, err)
+	_, err = net.Build(model)
+	assert.Error(t, err, "unbalanced")
+
+	model, err = golpa.NewModel("flow", golpa.Maximize)
+	require.NoError(t, err)
+	_, err = NewNetwork().Build(model)
+	assert.Error(t, err, "maximization")
+}
+
+func TestBuildParallelArcs(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 2)
+	d := net.AddNode("t", -2)
+	net.AddArc(s, d, 1, 1)
+	net.AddArc(s, d, 1, 2)
+
+	model, err := golpa.NewModel("flow", golpa.Minimize, golpa.WithDuplicateNames(golpa.RejectDuplicateNames))
+	require.NoError(t, err)
+	_, err = net.Build(model)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+}
+
+func TestMaxFlow(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 0)
+	a := net.AddNode("a", 0)
+	b := net.AddNode("b", 0)
+	d := net.AddNode("t", 0)
+
+	net.AddArc(s, a, 3, 0)
+	net.AddArc(s, b, 4, 0)
+	net.AddArc(a, b, 1, 0)
+	at := net.AddArc(a, d, 2, 0)
+	bt := net.AddArc(b, d, 3, 0)
+
+	res, err := MaxFlow(net, s, d)
+	require.NoError(t, err)
+
+	// the only minimum cut separates t from all other nodes
+	assert.InDelta(t, 5, res.Value, delta)
+	assert.InDelta(t, 2, res.Flows[at.index], delta)
+	assert.InDelta(t, 3, res.Flows[bt.index], delta)
+	assert.ElementsMatch(t, []*Node{s, a, b}, res.SourceSide)
+	assert.ElementsMatch(t, []*Arc{at, bt}, res.Cut)
+
+	// without capacity towards t, the cut is empty
+	at.capacity, bt.capacity = 0, 0
+	res, err = MaxFlow(net, s, d)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, res.Value, delta)
+	assert.ElementsMatch(t, []*Node{s, a, b}, res.SourceSide)
+	assert.ElementsMatch(t, []*Arc{at, bt}, res.Cut)
+
+	_, err = MaxFlow(net, s, s)
+	assert.Error(t, err)
+}
+
+func TestMaxFlowParallelArcs(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 0)
+	d := net.AddNode("t", 0)
+	net.AddArc(s, d, 2, 0)
+	net.AddArc(s, d, 3, 0)
+
+	res, err := MaxFlow(net, s, d)
+	require.NoError(t, err)
+	assert.InDelta(t, 5, res.Value, delta)
+	assert.InDelta(t, 2, res.Flows[0], delta)
+	assert.InDelta(t, 3, res.Flows[1], delta)
+}
+
+func TestPath(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 0)
+	a := net.AddNode("a", 0)
+	b := net.AddNode("b", 0)
+	d := net.AddNode("t", 0)
+
+	sa := net.AddArc(s, a, 1, 1)
+	ad := net.AddArc(a, d, 1, 1)
+	sb := net.AddArc(s, b, 1, 3)
+	bd := net.AddArc(b, d, 1, 3)
+	sd := net.AddArc(s, d, 1, 10)
+	net.AddArc(a, b, 0, 0)
+	time := []float64{5, 5, 1, 1, 1, 0}
+
+	for _, tc := range []struct {
+		limit float64
+		path  []*Arc
+		cost  float64
+	}{
+		{math.Inf(1), []*Arc{sa, ad}, 2},
+		{4, []*Arc{sb, bd}, 6},
+		{1, []*Arc{sd}, 10},
+	} {
+		p, err := NewPath(net, s, d)
+		require.NoError(t, err)
+		_, err = p.AddResourceLimit(time, tc.limit)
+		require.NoError(t, err)
+
+		path, cost, err := p.Solve()
+		require.NoError(t, err)
+		assert.Equal(t, tc.path, path, "limit %g", tc.limit)
+		assert.InDelta(t, tc.cost, cost, delta)
+	}
+
+	p, err := NewPath(net, s, d)
+	require.NoError(t, err)
+	_, err = p.AddResourceLimit(time, 0)
+	require.NoError(t, err)
+	_, _, err = p.Solve()
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+
+	_, err = p.AddResourceLimit(time[:2], 0)
+	assert.Error(t, err)
+
+	// a zero cost cycle through a node of the path
+	net = NewNetwork()
+	s = net.AddNode("s", 0)
+	a = net.AddNode("a", 0)
+	c := net.AddNode("c", 0)
+	d = net.AddNode("t", 0)
+	sa = net.AddArc(s, a, 1, 1)
+	ac := net.AddArc(a, c, 1, 0)
+	net.AddArc(c, a, 1, 0)
+	ad = net.AddArc(a, d, 1, 1)
+
+	p, err = NewPath(net, s, d)
+	require.NoError(t, err)
+	p.Variable(ac).SetBounds(1, 1)
+	path, cost, err := p.Solve()
+	require.NoError(t, err)
+	assert.Equal(t, []*Arc{sa, ad}, path)
+	assert.InDelta(t, 2, cost, delta)
+}
+
+func TestPathParallelArcs(t *testing.T) {
+	net := NewNetwork()
+	s := net.AddNode("s", 0)
+	d := net.AddNode("t", 0)
+	expensive := net.AddArc(s, d, 1, 2)
+	cheap := net.AddArc(s, d, 1, 1)
+
+	p, err := NewPath(net, s, d)
+	require.NoError(t, err)
+	assert.NotEqual(t, p.Variable(expensive).Name(), p.Variable(cheap).Name())
+
+	path, cost, err := p.Solve()
+	require.NoError(t, err)
+	assert.Equal(t, []*Arc{cheap}, path)
+	assert.InDelta(t, 1, cost, delta)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package flow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// MaxFlowResult is a maximum flow of a network along with a minimum cut.
+type MaxFlowResult struct {
+	// Value is the amount of flow from the source to the sink, which is
+	// also the capacity of the cut.
+	Value float64
+	// Flows is the flow through every arc, indexed like Network.Arcs.
+	Flows []float64
+
+	// SourceSide is the set of nodes on the source side of the cut.
+	SourceSide []*Node
+	// Cut is the set of arcs from the source side to the sink side, whose
+	// capacities add up to Value.
+	Cut []*Arc
+}
+
+// MaxFlow returns the maximum flow from source to sink in the network,
+// ignoring node supplies and arc costs, along with a minimum cut separating
+// them. If a path with unlimited capacity connects them, ErrModelUnbounded
+// is returned.
+//
+// The cut is read from the node potentials, i.e. the shadow prices of the
+// flow conservation constraints: scaled to be 1 at the source and 0 at the
+// sink, nodes with a potential above 1/2 are on the source side.
+func MaxFlow(net *Network, source, sink *Node) (*MaxFlowResult, error) {
+	if source == sink {
+		return nil, fmt.Errorf("source and sink are the same node %q", source.name)
+	}
+
+	model, err := golpa.NewModel("maxflow", golpa.Maximize, golpa.WithDuplicateNames(golpa.RejectDuplicateNames))
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([][]*golpa.Variable, len(net.nodes))
+	coefs := make([][]float64, len(net.nodes))
+	addArc := func(v *golpa.Variable, from, to *Node) {
+		vars[from.index] = append(vars[from.index], v)
+		coefs[from.index] = append(coefs[from.index], 1)
+		vars[to.index] = append(vars[to.index], v)
+		coefs[to.index] = append(coefs[to.index], -1)
+	}
+
+	arcs := make([]*golpa.Variable, len(net.arcs))
+	// as in Build, parallel arcs would otherwise share a name, and none may
+	// take the name of the return arc added below
+	used := map[string]bool{"return": true}
+	for i, arc := range net.arcs {
+		name := fmt.Sprintf("flow_%s_%s", arc.From.name, arc.To.name)
+		for used[name] {
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		used[name] = true
+		if arcs[i], err = model.AddDefinedVariable(name, golpa.ContinuousVariable, 0, 0, arc.capacity); err != nil {
+			return nil, err
+		}
+		if arc.From != arc.To {
+			addArc(arcs[i], arc.From, arc.To)
+		}
+	}
+
+	// the flow returns from the sink to the source, so all nodes are
+	// balanced
+	ret, err := model.AddDefinedVariable("return", golpa.ContinuousVariable, 1, 0, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+	addArc(ret, sink, source)
+
+	conservation := make([]*golpa.Constraint, len(net.nodes))
+	for i := range net.nodes {
+		if conservation[i], err = model.AddConstraint(0, 0, vars[i], coefs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MaxFlowResult{
+		Value: res.ObjectiveValue(),
+		Flows: make([]float64, len(arcs)),
+	}
+	for i, v := range arcs {
+		result.Flows[i] = res.Value(v)
+	}
+
+	onSourceSide := make([]bool, len(net.nodes))
+	top := res.ShadowPrice(conservation[source.index])
+	bottom := res.ShadowPrice(conservation[sink.index])
+	if top != bottom {
+		for i, c := range conservation {
+			onSourceSide[i] = (res.ShadowPrice(c)-bottom)/(top-bottom) > 0.5
+		}
+	} else {
+		// without flow, all potentials may be equal; the nodes reachable
+		// through arcs with capacity then form a cut of capacity zero
+		net.reachable(source, onSourceSide)
+	}
+
+	for i, node := range net.nodes {
+		if onSourceSide[i] {
+			result.SourceSide = append(result.SourceSide, node)
+		}
+	}
+	for _, arc := range net.arcs {
+		if onSourceSide[arc.From.index] && !onSourceSide[arc.To.index] {
+			result.Cut = append(result.Cut, arc)
+		}
+	}
+
+	return result, nil
+}
+
+// reachable marks the nodes reachable from the given node through arcs with
+// positive capacity.
+func (n *Network) reachable(from *Node, marked []bool) {
+	marked[from.index] = true
+	for _, arc := range n.arcs {
+		if arc.From == from && arc.capacity > 0 && !marked[arc.To.index] {
+			n.reachable(arc.To, marked)
+		}
+	}
+}
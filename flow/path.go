@@ -0,0 +1,171 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package flow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Path is the formulation of a cheapest path between two nodes of a
+// network, as a model with one binary variable per arc telling whether the
+// path uses it. Unlike graph algorithms, it can be extended with side
+// constraints before solving, e.g. to find the cheapest path within a time
+// budget with AddResourceLimit.
+//
+// Arc costs must not form cycles of negative cost, and arcs without
+// capacity are not used. Node supplies are ignored.
+type Path struct {
+	model          *golpa.Model
+	net            *Network
+	source, target *Node
+	vars           []*golpa.Variable
+}
+
+// NewPath returns the formulation of a cheapest path from source to target.
+func NewPath(net *Network, source, target *Node) (*Path, error) {
+	if source == target {
+		return nil, fmt.Errorf("source and target are the same node %q", source.name)
+	}
+
+	model, err := golpa.NewModel("path", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Path{
+		model:  model,
+		net:    net,
+		source: source,
+		target: target,
+		vars:   make([]*golpa.Variable, len(net.arcs)),
+	}
+
+	vars := make([][]*golpa.Variable, len(net.nodes))
+	coefs := make([][]float64, len(net.nodes))
+	used := make(map[string]bool, len(net.arcs))
+	for i, arc := range net.arcs {
+		// as in Build, parallel arcs would otherwise share a name
+		name := fmt.Sprintf("use_%s_%s", arc.From.name, arc.To.name)
+		for used[name] {
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		used[name] = true
+		if p.vars[i], err = model.AddDefinedVariable(name, golpa.BinaryVariable, arc.cost, 0, 1); err != nil {
+			return nil, err
+		}
+		if arc.capacity <= 0 || arc.From == arc.To {
+			p.vars[i].SetBounds(0, 0)
+			continue
+		}
+
+		vars[arc.From.index] = append(vars[arc.From.index], p.vars[i])
+		coefs[arc.From.index] = append(coefs[arc.From.index], 1)
+		vars[arc.To.index] = append(vars[arc.To.index], p.vars[i])
+		coefs[arc.To.index] = append(coefs[arc.To.index], -1)
+	}
+
+	for i, node := range net.nodes {
+		supply := 0.0
+		switch node {
+		case source:
+			supply = 1
+		case target:
+			supply = -1
+		}
+		if _, err := model.AddConstraint(supply, supply, vars[i], coefs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Model returns the model of the path, for adding side constraints.
+func (p *Path) Model() *golpa.Model {
+	return p.model
+}
+
+// Variable returns the binary variable telling whether the path uses the
+// given arc.
+func (p *Path) Variable(arc *Arc) *golpa.Variable {
+	return p.vars[arc.index]
+}
+
+// AddResourceLimit restricts the path to use at most limit of some resource,
+// such as time or fuel, of which each arc uses the amount given in usage,
+// indexed like Network.Arcs.
+func (p *Path) AddResourceLimit(usage []float64, limit float64) (*golpa.Constraint, error) {
+	if len(usage) != len(p.vars) {
+		return nil, fmt.Errorf("inconsistent number of usages and arcs: %d != %d", len(usage), len(p.vars))
+	}
+	return p.model.AddConstraint(math.Inf(-1), limit, p.vars, usage)
+}
+
+// Solve returns the arcs of the cheapest path satisfying all side
+// constraints, in order from the source to the target, along with its cost.
+// If there is no such path, ErrModelInfeasible is returned.
+func (p *Path) Solve(opts ...golpa.SolveOption) ([]*Arc, float64, error) {
+	res, err := p.model.Solve(opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	next := make(map[*Node][]*Arc)
+	for i, arc := range p.net.arcs {
+		if res.Value(p.vars[i]) > 0.5 {
+			next[arc.From] = append(next[arc.From], arc)
+		}
+	}
+
+	// cycles of zero cost may be part of the solution too, even through
+	// nodes of the path, so search the used arcs for a path which visits
+	// each node at most once
+	visited := make(map[*Node]bool)
+	var path []*Arc
+	var search func(node *Node) bool
+	search = func(node *Node) bool {
+		if node == p.target {
+			return true
+		}
+		visited[node] = true
+		for _, arc := range next[node] {
+			if visited[arc.To] {
+				continue
+			}
+			path = append(path, arc)
+			if search(arc.To) {
+				return true
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	if !search(p.source) {
+		return nil, 0, fmt.Errorf("solution does not contain a path from %q to %q", p.source.name, p.target.name)
+	}
+
+	cost := 0.0
+	for _, arc := range path {
+		cost += arc.cost
+	}
+
+	return path, cost, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ctxCheckInterval is the number of constraints added between checks for
+// cancellation in context-aware bulk operations.
+const ctxCheckInterval = 1000
+
+// consumeBatchSize is the maximum number of constraints Consume adds at once.
+const consumeBatchSize = 1000
+
+// ConstraintSpec describes a constraint to be added by ForEach,
+// AddConstraintsCtx or Consume: the expression must lie between Lower and Upper. An
+// empty Name keeps the default name.
+type ConstraintSpec struct {
+	Name         string
+	Lower, Upper float64
+	Expr         Expr
+}
+
+// ForEach adds one constraint per index, as described by gen. The specs are
+// generated concurrently, so gen must be safe to call from multiple
+// goroutines; the constraints are then added in one batch, in the order of
+// the indices.
+//
+// If any spec is invalid, no constraint is added. If adding the constraints
+// fails otherwise, those added so far are returned along with the error.
+func (model *Model) ForEach(indices []int, gen func(i int) ConstraintSpec) ([]*Constraint, error) {
+	type built struct {
+		spec  ConstraintSpec
+		vars  []*Variable
+		coefs []float64
+	}
+	specs := make([]built, len(indices))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(indices) {
+		workers = len(indices)
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				spec := gen(indices[k])
+				vars, coefs := spec.Expr.Terms()
+				specs[k] = built{spec, vars, coefs}
+			}
+		}()
+	}
+	for k := range indices {
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+
+	for k, b := range specs {
+		for _, v := range b.vars {
+			if v.model != model {
+				return nil, fmt.Errorf("constraint for index %d: variable does not belong to model", indices[k])
+			}
+		}
+	}
+
+	cons, err := model.addConstraintBatch(len(specs), func(k int) (lower, upper float64, vars []*Variable, coefs []float64) {
+		b := specs[k]
+		return b.spec.Lower - b.spec.Expr.constant, b.spec.Upper - b.spec.Expr.constant, b.vars, b.coefs
+	})
+
+	for k, c := range cons {
+		if specs[k].spec.Name != "" {
+			c.SetName(specs[k].spec.Name)
+		}
+	}
+
+	return cons, err
+}
+
+// AddConstraintsCtx adds the described constraints in batches, checking
+// between batches whether ctx is done. If it is, the constraints added so
+// far are returned along with the context's error, so building a model
+// which is no longer needed can be given up early. The same holds if adding
+// a batch fails.
+func (model *Model) AddConstraintsCtx(ctx context.Context, specs []ConstraintSpec) ([]*Constraint, error) {
+	cons := make([]*Constraint, 0, len(specs))
+	for start := 0; start < len(specs); start += ctxCheckInterval {
+		if err := ctx.Err(); err != nil {
+			return cons, err
+		}
+
+		batch := specs[start:]
+		if len(batch) > ctxCheckInterval {
+			batch = batch[:ctxCheckInterval]
+		}
+		added, err := model.addSpecs(batch)
+		cons = append(cons, added...)
+		if err != nil {
+			return cons, err
+		}
+	}
+
+	return cons, nil
+}
+
+// Consume adds the constraints received from specs until the channel is
+// closed or ctx is done, and returns them. Constraints are added in batches
+// of up to 1000, or fewer whenever no further spec is ready, so slow
+// producers do not delay insertion. Since specs are only received while
+// the previous batch is not being added, producers are held back when they
+// outpace the model.
+//
+// If ctx is done or a spec is invalid, the constraints added so far are
+// returned along with the error.
+func (model *Model) Consume(ctx context.Context, specs <-chan ConstraintSpec) ([]*Constraint, error) {
+	var cons []*Constraint
+	batch := make([]ConstraintSpec, 0, consumeBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		added, err := model.addSpecs(batch)
+		cons = append(cons, added...)
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		var (
+			spec ConstraintSpec
+			ok   bool
+		)
+		select {
+		case spec, ok = <-specs:
+		case <-ctx.Done():
+			return cons, ctx.Err()
+		default:
+			// no spec ready: add the pending ones before waiting
+			if err := flush(); err != nil {
+				return cons, err
+			}
+			select {
+			case spec, ok = <-specs:
+			case <-ctx.Done():
+				return cons, ctx.Err()
+			}
+		}
+		if !ok {
+			return cons, flush()
+		}
+
+		batch = append(batch, spec)
+		if len(batch) == consumeBatchSize {
+			if err := flush(); err != nil {
+				return cons, err
+			}
+		}
+	}
+}
+
+// addSpecs adds the described constraints in one batch, returning those
+// added so far if it fails.
+func (model *Model) addSpecs(specs []ConstraintSpec) ([]*Constraint, error) {
+	cons, err := model.addConstraintBatch(len(specs), func(k int) (lower, upper float64, vars []*Variable, coefs []float64) {
+		spec := specs[k]
+		vars, coefs = spec.Expr.Terms()
+		return spec.Lower - spec.Expr.constant, spec.Upper - spec.Expr.constant, vars, coefs
+	})
+
+	for k, c := range cons {
+		if specs[k].Name != "" {
+			c.SetName(specs[k].Name)
+		}
+	}
+
+	return cons, err
+}
+
+// addConstraintBatch adds n constraints, as returned by row, while holding
+// the model's write lock only once and growing the underlying model only
+// once. All rows are validated first, so none is added if any is invalid;
+// if adding fails nonetheless, the constraints added so far are returned
+// along with the error.
+func (model *Model) addConstraintBatch(n int, row func(k int) (lower, upper float64, vars []*Variable, coefs []float64)) ([]*Constraint, error) {
+	type batchRow struct {
+		lower, upper float64
+		vars         []*Variable
+		coefs        []float64
+	}
+	rows := make([]batchRow, n)
+	for k := range rows {
+		r := &rows[k]
+		r.lower, r.upper, r.vars, r.coefs = row(k)
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	for k, r := range rows {
+		if err := model.validateTerms(r.vars, r.coefs); err != nil {
+			return nil, ErrConstraint{Index: len(model.cons) + k, Err: err}
+		}
+		if err := validateBounds(r.lower, r.upper); err != nil {
+			return nil, ErrConstraint{Index: len(model.cons) + k, Err: err}
+		}
+	}
+
+	// spooled rows are only loaded into the underlying model later, so
+	// there is nothing to grow yet
+	if model.spool == nil {
+		C.resize_lp(model.prob, C.get_Nrows(model.prob)+C.int(n), C.get_Ncolumns(model.prob))
+	}
+
+	cons := make([]*Constraint, 0, n)
+	for _, r := range rows {
+		c, err := model.addConstraint(r.lower, r.upper, r.vars, r.coefs)
+		if err != nil {
+			return cons, err
+		}
+		cons = append(cons, c)
+	}
+
+	return cons, nil
+}
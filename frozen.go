@@ -0,0 +1,65 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import "context"
+
+// FrozenModel is an immutable snapshot of a model, which can be solved from
+// many goroutines at the same time. Each solve works on its own copy of the
+// model, so concurrent solves do not wait for each other.
+type FrozenModel struct {
+	model *Model
+}
+
+// Freeze returns an immutable snapshot of the model. Later changes to the
+// model do not affect the snapshot.
+func (model *Model) Freeze() *FrozenModel {
+	return &FrozenModel{model: model.Clone()}
+}
+
+// Name returns the name of the frozen model.
+func (f *FrozenModel) Name() string {
+	return f.model.Name()
+}
+
+// Variables returns the frozen model's variables, which can be used to read
+// the results of its solves. Changes to the slice will not be reflected in
+// the model.
+func (f *FrozenModel) Variables() []*Variable {
+	return append([]*Variable(nil), f.model.Variables()...)
+}
+
+// Constraints returns the frozen model's constraints, which can be used to
+// read the results of its solves. Changes to the slice will not be
+// reflected in the model.
+func (f *FrozenModel) Constraints() []*Constraint {
+	return append([]*Constraint(nil), f.model.Constraints()...)
+}
+
+// Solve solves a copy of the frozen model, like Model.Solve. The result can
+// be read with the frozen model's variables and constraints.
+func (f *FrozenModel) Solve(opts ...SolveOption) (*SolveResult, error) {
+	return f.model.Clone().Solve(opts...)
+}
+
+// SolveWithContext solves a copy of the frozen model, like
+// Model.SolveWithContext. The result can be read with the frozen model's
+// variables and constraints.
+func (f *FrozenModel) SolveWithContext(ctx context.Context, opts ...SolveOption) (*SolveResult, error) {
+	return f.model.Clone().SolveWithContext(ctx, opts...)
+}
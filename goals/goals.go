@@ -0,0 +1,218 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package goals implements goal programming on top of golpa models.
+//
+// Each goal asks a linear expression to reach a target. Instead of being a
+// hard constraint, the goal gets deviation variables measuring how far the
+// expression falls short of or exceeds the target, and the undesired
+// deviations are minimized, either as a weighted sum or preemptively by
+// priority.
+package goals
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/costela/golpa"
+)
+
+// preemptiveTolerance is the relative slack allowed when fixing the
+// achievement of a priority level before optimizing the next one.
+const preemptiveTolerance = 1e-9
+
+// Sense determines which deviations from a goal's target are undesired.
+type Sense int
+
+const (
+	// AtLeast goals penalize falling short of the target.
+	AtLeast Sense = iota
+	// AtMost goals penalize exceeding the target.
+	AtMost
+	// Exactly goals penalize deviations in both directions.
+	Exactly
+)
+
+// Goal describes a target for a linear expression.
+type Goal struct {
+	Name   string
+	Expr   golpa.Expr
+	Target float64
+	Sense  Sense
+
+	// Priority orders goals in preemptive solves: all goals with a lower
+	// priority value are fully optimized first.
+	Priority int
+
+	// Weight scales the goal's undesired deviations. Zero is treated as 1.
+	Weight float64
+}
+
+// Deviation holds the deviation variables of a goal.
+type Deviation struct {
+	// Under is how much the expression falls short of the target.
+	Under *golpa.Variable
+	// Over is how much the expression exceeds the target.
+	Over *golpa.Variable
+
+	goal Goal
+}
+
+// Goal returns the goal the deviations belong to.
+func (d *Deviation) Goal() Goal {
+	return d.goal
+}
+
+// Program is a set of goals on a model.
+type Program struct {
+	model      *golpa.Model
+	deviations []*Deviation
+}
+
+// New returns an empty goal program for the given model.
+func New(model *golpa.Model) *Program {
+	return &Program{model: model}
+}
+
+// Add adds a goal to the program, along with its deviation variables and
+// the constraint Expr + Under - Over = Target.
+func (p *Program) Add(g Goal) (*Deviation, error) {
+	if g.Weight < 0 {
+		return nil, fmt.Errorf("goal %q has negative weight %g", g.Name, g.Weight)
+	}
+	if g.Weight == 0 {
+		g.Weight = 1
+	}
+
+	under, err := p.model.AddDefinedVariable(g.Name+"_under", golpa.ContinuousVariable, 0, 0, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+	over, err := p.model.AddDefinedVariable(g.Name+"_over", golpa.ContinuousVariable, 0, 0, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+
+	e := g.Expr.Plus(golpa.Term(1, under)).Plus(golpa.Term(-1, over))
+	c, err := p.model.AddExprConstraint(g.Target, g.Target, e)
+	if err != nil {
+		return nil, err
+	}
+	if g.Name != "" {
+		c.SetName(g.Name)
+	}
+
+	d := &Deviation{
+		Under: under,
+		Over:  over,
+		goal:  g,
+	}
+	p.deviations = append(p.deviations, d)
+
+	return d, nil
+}
+
+// penalty returns the weighted undesired deviations of the given goals.
+func penalty(deviations []*Deviation) (vars []*golpa.Variable, coefs []float64) {
+	for _, d := range deviations {
+		if d.goal.Sense != AtMost {
+			vars = append(vars, d.Under)
+			coefs = append(coefs, d.goal.Weight)
+		}
+		if d.goal.Sense != AtLeast {
+			vars = append(vars, d.Over)
+			coefs = append(coefs, d.goal.Weight)
+		}
+	}
+	return vars, coefs
+}
+
+// objectiveName is the name under which goal objectives are stored on the
+// model.
+func objectiveName(level int) string {
+	return fmt.Sprintf("goals priority %d", level)
+}
+
+// SolveWeighted minimizes the weighted sum of all undesired deviations,
+// regardless of priorities. The model's own objective function is ignored
+// but left unchanged.
+func (p *Program) SolveWeighted() (*golpa.SolveResult, error) {
+	vars, coefs := penalty(p.deviations)
+	if err := p.model.AddObjective("goals", coefs, vars); err != nil {
+		return nil, err
+	}
+
+	return p.model.SolveAs(golpa.Minimize, golpa.WithObjective("goals"))
+}
+
+// SolvePreemptive minimizes the weighted undesired deviations of each
+// priority level in turn, from the lowest priority value up, never allowing
+// a level to get worse once it was optimized. The model's own objective
+// function is ignored but left unchanged.
+//
+// The constraints holding the achievement of each level are relaxed again
+// once the solve is finished, but remain in the model as free rows.
+func (p *Program) SolvePreemptive() (*golpa.SolveResult, error) {
+	levels := make(map[int][]*Deviation)
+	for _, d := range p.deviations {
+		levels[d.goal.Priority] = append(levels[d.goal.Priority], d)
+	}
+	priorities := make([]int, 0, len(levels))
+	for priority := range levels {
+		priorities = append(priorities, priority)
+	}
+	sort.Ints(priorities)
+	if len(priorities) == 0 {
+		return nil, fmt.Errorf("no goals to optimize")
+	}
+
+	var fixed []*golpa.Constraint
+	defer func() {
+		for _, c := range fixed {
+			c.SetBounds(math.Inf(-1), math.Inf(1))
+		}
+	}()
+
+	var res *golpa.SolveResult
+	for i, priority := range priorities {
+		vars, coefs := penalty(levels[priority])
+		name := objectiveName(priority)
+		if err := p.model.AddObjective(name, coefs, vars); err != nil {
+			return nil, err
+		}
+
+		var err error
+		res, err = p.model.SolveAs(golpa.Minimize, golpa.WithObjective(name))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == len(priorities)-1 {
+			break
+		}
+
+		achieved := res.ObjectiveValue()
+		c, err := p.model.AddConstraint(math.Inf(-1), achieved+preemptiveTolerance*math.Max(1, math.Abs(achieved)), vars, coefs)
+		if err != nil {
+			return nil, err
+		}
+		fixed = append(fixed, c)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package goals
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.000001
+
+// production builds a model with two products sharing 10 hours of work and
+// goals on profit and production volumes.
+func production(t *testing.T) (*golpa.Model, *Program, []*Deviation) {
+	model, err := golpa.NewModel("production", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", golpa.ContinuousVariable, 1, 0, math.Inf(1))
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", golpa.ContinuousVariable, 1, 0, math.Inf(1))
+	require.NoError(t, err)
+	_, err = model.AddExprConstraint(math.Inf(-1), 10, golpa.Sum(x, y))
+	require.NoError(t, err)
+
+	p := New(model)
+	var devs []*Deviation
+	for _, g := range []Goal{
+		{Name: "profit", Expr: golpa.Dot([]float64{5, 3}, []*golpa.Variable{x, y}), Target: 45, Sense: AtLeast, Priority: 1},
+		{Name: "x", Expr: golpa.Sum(x), Target: 8, Sense: AtLeast, Priority: 2},
+		{Name: "y", Expr: golpa.Sum(y), Target: 5, Sense: AtLeast, Priority: 2},
+	} {
+		d, err := p.Add(g)
+		require.NoError(t, err)
+		devs = append(devs, d)
+	}
+
+	return model, p, devs
+}
+
+func TestSolveWeighted(t *testing.T) {
+	model, p, devs := production(t)
+
+	res, err := p.SolveWeighted()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+	assert.Equal(t, golpa.Maximize, model.Direction())
+	assert.Equal(t, AtLeast, devs[0].Goal().Sense)
+}
+
+func TestSolvePreemptive(t *testing.T) {
+	model, p, devs := production(t)
+	constraints := model.ConstraintCount()
+
+	res, err := p.SolvePreemptive()
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0, res.Value(devs[0].Under), delta, "priority 1 must be met")
+	assert.InDelta(t, 3, res.Value(devs[1].Under)+res.Value(devs[2].Under), delta)
+
+	// the priority 1 achievement is no longer enforced
+	assert.Equal(t, constraints+1, model.ConstraintCount())
+	l, u := model.Constraints()[constraints].Bounds()
+	assert.True(t, math.IsInf(l, -1) && math.IsInf(u, 1))
+}
+
+func TestSolvePreemptiveEmpty(t *testing.T) {
+	model, err := golpa.NewModel("empty", golpa.Minimize)
+	require.NoError(t, err)
+
+	_, err = New(model).SolvePreemptive()
+	assert.Error(t, err)
+}
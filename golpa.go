@@ -70,9 +70,6 @@ can be expressed with GoLPA like this:
 */
 package golpa
 
-// #cgo linux LDFLAGS: -llpsolve55
-// #cgo darwin LDFLAGS: -L/usr/local/lib -llpsolve55
-// #cgo darwin CFLAGS: -I/usr/local/include
 // #include <lp_lib.h>
 // #include <stdlib.h>
 /*
@@ -91,16 +88,30 @@ import (
 	"math"
 	"runtime"
 	"sync"
+	"time"
 	"unsafe"
 )
 
 /* Types */
 
 type Model struct {
-	mu     sync.RWMutex
-	prob   *C.lprec
-	vars   []*Variable
-	logger Logger
+	mu         sync.RWMutex
+	prob       *C.lprec
+	vars       []*Variable
+	cons       []*Constraint
+	logger     Logger
+	spool      *constraintSpool
+	spoolErr   error // failure loading spooled constraints
+	dedup      map[string]*Constraint
+	params     map[string]*Param
+	objectives map[string]*namedObjective
+	namePolicy DuplicateNamePolicy
+	names      map[string]bool // used variable names, unless duplicates are allowed
+	anonymous  int             // number of names generated by AddAnonymousVariable
+	scaling    *scaling
+
+	historyLimit int
+	history      []SolveRecord
 }
 
 type direction C.uchar
@@ -164,19 +175,28 @@ func logCallback(prob *C.lprec, modelPtr unsafe.Pointer, msg *C.char) {
 // finalizeModel is the function registered to be called upon garbage-
 // collection of the model value
 func finalizeModel(model *Model) {
+	if model.spool != nil {
+		model.spool.close()
+	}
 	C.delete_lp(model.prob)
 }
 
 // Clone returns a copy of the model.
+// Spooled constraints (see WithConstraintSpool) are loaded before cloning and
+// the clone itself does not spool its constraints.
 func (model *Model) Clone() *Model {
-	model.mu.RLock()
-	defer model.mu.RUnlock()
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	// a failure is returned by the next solve of either model
+	spoolErr := model.loadSpooledConstraints()
 
 	newProb := C.copy_lp(model.prob)
 	newVars := make([]*Variable, len(model.vars))
 	newModel := &Model{
-		prob:   newProb,
-		logger: model.logger,
+		prob:     newProb,
+		logger:   model.logger,
+		spoolErr: spoolErr,
 	}
 
 	for i, v := range model.vars {
@@ -188,6 +208,41 @@ func (model *Model) Clone() *Model {
 
 	newModel.vars = newVars
 
+	newCons := make([]*Constraint, len(model.cons))
+	for i, c := range model.cons {
+		newCons[i] = &Constraint{
+			model: newModel,
+			index: c.index,
+		}
+	}
+
+	newModel.cons = newCons
+
+	if model.dedup != nil {
+		newModel.dedup = make(map[string]*Constraint, len(model.dedup))
+		for key, c := range model.dedup {
+			newModel.dedup[key] = newCons[c.index]
+		}
+	}
+
+	newModel.anonymous = model.anonymous
+	if model.names != nil {
+		newModel.namePolicy = model.namePolicy
+		newModel.names = make(map[string]bool, len(model.names))
+		for name := range model.names {
+			newModel.names[name] = true
+		}
+	}
+
+	if model.objectives != nil {
+		newModel.objectives = make(map[string]*namedObjective, len(model.objectives))
+		for name, obj := range model.objectives {
+			newModel.objectives[name] = obj.clone(newVars)
+		}
+	}
+
+	newModel.scaling = model.scaling
+
 	newModel.finishInitialization()
 
 	return newModel
@@ -198,6 +253,12 @@ func (model *Model) Name() string {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	return model.name()
+}
+
+// name returns the model's name. The caller must hold at least the model's
+// read lock.
+func (model *Model) name() string {
 	return C.GoString(C.get_lp_name(model.prob))
 }
 
@@ -231,12 +292,11 @@ func (model *Model) VariableCount() int {
 }
 
 // Variables returns a new slice with the model's variables. Changes to the slice will not be reflected in the model.
-// Changes to the variables
 func (model *Model) Variables() []*Variable {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
-	return model.vars
+	return append([]*Variable(nil), model.vars...)
 }
 
 // AddVariable adds a variable to the linear programming model and
@@ -268,38 +328,85 @@ func (model *Model) AddIntegerVariable(name string) (v *Variable, err error) {
 	return model.AddDefinedVariable(name, IntegerVariable, 1, math.Inf(-1), math.Inf(1))
 }
 
+// AddFreeVariable is a convenience function for adding a single named
+// continuous variable without bounds (from -∞ to +∞) to the model, with a
+// default objective coefficient of 1. It is equivalent to AddVariable, but
+// states the intent explicitly.
+// Empty names will automatically replaced by a unique name.
+func (model *Model) AddFreeVariable(name string) (v *Variable, err error) {
+	return model.AddDefinedVariable(name, ContinuousVariable, 1, math.Inf(-1), math.Inf(1))
+}
+
+// AddNonNegativeVariable is a convenience function for adding a single
+// named continuous variable with bounds from 0 to +∞ to the model, with a
+// default objective coefficient of 1.
+// Empty names will automatically replaced by a unique name.
+func (model *Model) AddNonNegativeVariable(name string) (v *Variable, err error) {
+	return model.AddDefinedVariable(name, ContinuousVariable, 1, 0, math.Inf(1))
+}
+
 // AddDefinedVariable add a variable to the linear programming model
 // with its attributes passed as arguments.
 // If varType is BinaryVariable, the bounds are ignored.
 // Empty names will automatically replaced by a unique name.
 func (model *Model) AddDefinedVariable(name string, varType VariableType, coefficient, lowerBound, upperBound float64) (v *Variable, err error) {
+	return model.addVariable(name, false, varType, coefficient, lowerBound, upperBound)
+}
+
+// addVariable implements AddDefinedVariable. If anonymous is set, the name
+// is generated by anonymousName instead.
+func (model *Model) addVariable(name string, anonymous bool, varType VariableType, coefficient, lowerBound, upperBound float64) (v *Variable, err error) {
+	if math.IsNaN(coefficient) || math.IsInf(coefficient, 0) {
+		return nil, ErrVariable{Name: name, Err: ErrInvalidCoefficient{Variable: name, Index: model.VariableCount(), Value: coefficient}}
+	}
+	if varType != BinaryVariable {
+		if err := validateBounds(lowerBound, upperBound); err != nil {
+			return nil, ErrVariable{Name: name, Err: err}
+		}
+	}
+
 	size := model.VariableCount()
 
-	func() {
+	err = func() error {
 		model.mu.Lock()
 		defer model.mu.Unlock()
 
-		v = new(Variable)
-		v.index = size
-		v.model = model
-		model.vars = append(model.vars, v)
+		switch {
+		case anonymous:
+			name = model.anonymousName()
+		case name == "":
+			name = fmt.Sprintf("V%d", size)
+		}
+		unique, err := model.claimName(name)
+		if err != nil {
+			return ErrVariable{Name: name, Err: err}
+		}
+		name = unique
 
 		// when adding a variable after some constraints have been defined,
 		// we pass an array filled with zeroes to add_column, so the new
 		// variable is assumed to not be used in the existing constraints
-		C.add_columnex(model.prob, 0, nil, nil)
+		if C.add_columnex(model.prob, 0, nil, nil) != C.TRUE {
+			return fmt.Errorf("model %q: lp_solve failed to add variable %q", model.name(), name)
+		}
+
+		v = new(Variable)
+		v.index = size
+		v.model = model
+		model.vars = append(model.vars, v)
 		// coef_array := make([]C.REAL, model.ConstraintCount()+1)
 		// C.add_column(model.prob, &coef_array[0])
 
-		if name == "" {
-			name = fmt.Sprintf("V%d", size)
-		}
-
 		c_name := C.CString(name)
 		defer C.free(unsafe.Pointer(c_name))
 
 		C.set_col_name(model.prob, C.int(v.index+1), c_name)
+
+		return nil
 	}()
+	if err != nil {
+		return nil, err
+	}
 
 	v.SetType(varType)
 	v.SetObjectiveCoefficient(coefficient)
@@ -317,6 +424,13 @@ func (model *Model) AddDefinedVariable(name string, varType VariableType, coeffi
 // Where x and y are the return values of one of the Add*Variable
 // functions.
 func (model *Model) SetObjectiveFunction(coefs []float64, vars []*Variable) error {
+	model.mu.RLock()
+	err := model.validateTerms(vars, coefs)
+	model.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
 	for i, v := range vars {
 		v.SetObjectiveCoefficient(coefs[i])
 	}
@@ -326,42 +440,106 @@ func (model *Model) SetObjectiveFunction(coefs []float64, vars []*Variable) erro
 /* Constraint-related functions */
 
 // ConstraintCount returns the number of individual constraints in
-// the model
+// the model, including spooled ones.
 func (model *Model) ConstraintCount() int {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
-	return int(C.get_Nrows(model.prob))
+	count := int(C.get_Nrows(model.prob))
+	if model.spool != nil {
+		count += model.spool.pending
+	}
+
+	return count
+}
+
+// Constraints returns the model's constraints. Changes to the slice will not be reflected in the model.
+func (model *Model) Constraints() []*Constraint {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	return append([]*Constraint(nil), model.cons...)
 }
 
 // AddConstraint adds a constraint to the model as a lower and an upper
 // bounds, a slice of variables and a slice of their respective
 // coefficients.
-func (model *Model) AddConstraint(lower, upper float64, vars []*Variable, coefs []float64) error {
-	if len(vars) != len(coefs) {
-		return fmt.Errorf("inconsistent number of variables and coefficients: %d != %d", len(vars), len(coefs))
+// To leave one side of the constraint unbounded, pass math.Inf(-1) or
+// math.Inf(1) respectively.
+func (model *Model) AddConstraint(lower, upper float64, vars []*Variable, coefs []float64) (*Constraint, error) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	return model.addConstraint(lower, upper, vars, coefs)
+}
+
+// addConstraint adds a constraint to the model, or returns an identical
+// existing one if deduplication is enabled. The caller must hold the model's
+// write lock.
+func (model *Model) addConstraint(lower, upper float64, vars []*Variable, coefs []float64) (*Constraint, error) {
+	if err := model.validateTerms(vars, coefs); err != nil {
+		return nil, ErrConstraint{Index: len(model.cons), Err: err}
+	}
+	if err := validateBounds(lower, upper); err != nil {
+		return nil, ErrConstraint{Index: len(model.cons), Err: err}
 	}
 
-	row := make([]C.REAL, len(vars))
-	colno := make([]C.int, len(vars))
-	for i, v := range vars {
-		colno[i] = C.int(v.index + 1)
-		row[i] = C.REAL(coefs[i])
-	}
-
-	switch {
-	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
-		// no constraints
-	case math.IsInf(lower, 0):
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.LE, C.double(upper))
-	case math.IsInf(upper, 0):
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.GE, C.double(lower))
-	case upper == lower:
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.EQ, C.double(upper))
-	default:
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.LE, C.double(upper))
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.GE, C.double(lower))
+	var key string
+	if model.dedup != nil {
+		key = model.constraintKey(lower, upper, vars, coefs)
+		if c := model.dedup[key]; c != nil && model.matchesRow(c, key) {
+			return c, nil
+		}
+	}
+
+	c := &Constraint{
+		model: model,
+		index: len(model.cons),
+	}
+
+	if model.spool != nil {
+		if err := model.spool.write(lower, upper, vars, coefs); err != nil {
+			// the spool may now hold a partial row, so it cannot be loaded
+			model.spoolErr = err
+			return nil, err
+		}
+	} else {
+		row := make([]C.REAL, len(vars))
+		colno := make([]C.int, len(vars))
+		for i, v := range vars {
+			colno[i] = C.int(v.index + 1)
+			row[i] = C.REAL(coefs[i])
+		}
+
+		if err := model.addRow(lower, upper, row, colno); err != nil {
+			return nil, err
+		}
+	}
+
+	model.cons = append(model.cons, c)
+
+	if model.dedup != nil {
+		model.dedup[key] = c
+	}
+
+	return c, nil
+}
+
+// addRow adds the given row to the underlying library. The caller must hold
+// the model's write lock.
+func (model *Model) addRow(lower, upper float64, row []C.REAL, colno []C.int) error {
+	var (
+		rowPtr   *C.REAL
+		colnoPtr *C.int
+	)
+	if len(row) > 0 {
+		rowPtr, colnoPtr = &row[0], &colno[0]
+	}
+
+	if C.add_constraintex(model.prob, C.int(len(row)), rowPtr, colnoPtr, C.LE, 0) != C.TRUE {
+		return fmt.Errorf("model %q: lp_solve failed to add constraint %d", model.name(), C.get_Nrows(model.prob)+1)
 	}
+	model.setRowBounds(int(C.get_Nrows(model.prob)), lower, upper)
 
 	return nil
 }
@@ -369,20 +547,118 @@ func (model *Model) AddConstraint(lower, upper float64, vars []*Variable, coefs
 // Solve attempts to find an optimal solution to the model.
 // Information about the solution can be queried from the returned
 // SolveResult value.
-func (model *Model) Solve() (res *SolveResult, err error) {
+// If the model is infeasible or unbounded, ErrModelInfeasible or
+// ErrModelUnbounded are returned along with a result which can be used to
+// inspect the cause (see SolveResult.InfeasibilityCertificate and
+// SolveResult.UnboundedRay).
+func (model *Model) Solve(opts ...SolveOption) (res *SolveResult, err error) {
+	start := time.Now()
+
+	o := solveOptions{intTolerance: DefaultIntegralityTolerance}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	model.mu.Lock()
 	defer model.mu.Unlock()
+	defer func() { model.recordSolve(start, &o, res, err) }()
 
-	res = new(SolveResult)
-	res.model = model
+	if err := model.loadSpooledConstraints(); err != nil {
+		return nil, err
+	}
 
-	ret := C.solve(model.prob)
+	if o.objective != "" {
+		obj, ok := model.objectives[o.objective]
+		if !ok {
+			return nil, ErrUnknownObjective{Name: o.objective}
+		}
+		prev := model.objectiveRow()
+		model.setObjectiveRow(obj.row(len(model.vars)))
+		defer model.setObjectiveRow(prev)
+	}
+
+	if o.breakAtFirst {
+		prev := C.is_break_at_first(model.prob)
+		C.set_break_at_first(model.prob, C.TRUE)
+		defer C.set_break_at_first(model.prob, prev)
+	}
+
+	parent := o.ctx
+	if o.softDeadline > 0 {
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, o.softDeadline)
+		defer cancel()
+		o.ctx = ctx
+	}
+
+	var monitor *abortMonitor
+	if o.ctx != nil || o.stallWindow > 0 {
+		monitor = &abortMonitor{ctx: o.ctx, stallWindow: o.stallWindow}
+		ref := saveRef(monitor)
+		C.put_abortfunc(model.prob, (*C.lphandle_intfunc)(C.abortCallback), ref)
+		defer func() {
+			C.put_abortfunc(model.prob, nil, nil)
+			releaseRef(ref)
+		}()
+	}
+
+	if o.direction != nil {
+		prev := C.is_maxim(model.prob)
+		C.set_sense(model.prob, C.uchar(*o.direction))
+		defer C.set_sense(model.prob, prev)
+	}
+
+	if o.workspace != nil {
+		res = o.workspace.result()
+	} else {
+		res = new(SolveResult)
+	}
+	res.model = model
+	res.rounding = o.rounding
+	res.intTolerance = o.intTolerance
+	res.scaling = model.scaling
+	res.objectiveFn = o.objective
+
+	solve := func() C.int { return model.solvePhases(start, &res.timings) }
+	ret := measure(&res.stats, func() C.int {
+		ret := solve()
+		if ret == C.USERABORT && monitor != nil && monitor.stalled {
+			ret = model.retryStalled(monitor, solve)
+		}
+		return ret
+	})
+	res.stats.WallTime = time.Since(start)
+	res.iterations = model.iterationStats()
+	res.iterations.Stalled = monitor != nil && monitor.stalled
+
+	if o.softDeadline > 0 && o.ctx.Err() != nil && parent.Err() == nil {
+		switch ret {
+		case C.USERABORT:
+			ret = C.TIMEOUT
+		case C.SUBOPTIMAL:
+			model.polish(res, o.grace)
+		}
+	}
 
 	switch ret {
 	case C.OPTIMAL, C.SUBOPTIMAL:
 		res.status = SolveStatus(ret)
+		// unless polish already had to copy the solution
+		if o.workspace != nil && res.objective == nil {
+			extract := time.Now()
+			o.workspace.readValues(model)
+			res.timings.Extract = time.Since(extract)
+		}
 		return res, nil
-	case C.INFEASIBLE, C.UNBOUNDED, C.DEGENERATE, C.NUMFAILURE,
+	case C.INFEASIBLE:
+		res.status = SolutionInfeasible
+		return res, SolveError(ret)
+	case C.UNBOUNDED:
+		res.status = SolutionUnbounded
+		return res, SolveError(ret)
+	case C.DEGENERATE, C.NUMFAILURE,
 		C.USERABORT, C.TIMEOUT, C.PROCFAIL, C.PROCBREAK, C.FEASFOUND,
 		C.NOFEASFOUND, C.NOMEMORY:
 		return nil, SolveError(ret)
@@ -391,10 +667,26 @@ func (model *Model) Solve() (res *SolveResult, err error) {
 	}
 }
 
+// SolveAs is like Solve, but optimizes in the given direction instead of the
+// model's own. The model's direction is left unchanged.
+func (model *Model) SolveAs(dir direction, opts ...SolveOption) (*SolveResult, error) {
+	return model.Solve(append(opts, WithDirection(dir))...)
+}
+
+// SolveFeasible is like Solve, but stops at the first solution satisfying
+// all constraints and integrality requirements, without trying to improve
+// it. Unless the model is a pure linear program, the result's status is then
+// SolutionSuboptimal.
+//
+// This is useful when any feasible solution will do and latency matters.
+func (model *Model) SolveFeasible(opts ...SolveOption) (*SolveResult, error) {
+	return model.Solve(append(opts, func(o *solveOptions) { o.breakAtFirst = true })...)
+}
+
 //export abortCallback
-func abortCallback(prob *C.lprec, ctxPtr unsafe.Pointer) C.int {
-	ctx, ok := loadRef(ctxPtr).(context.Context)
-	if ok && ctx.Err() != nil {
+func abortCallback(prob *C.lprec, monitorPtr unsafe.Pointer) C.int {
+	monitor, ok := loadRef(monitorPtr).(*abortMonitor)
+	if ok && monitor.abort(prob) {
 		return C.TRUE
 	}
 
@@ -404,11 +696,8 @@ func abortCallback(prob *C.lprec, ctxPtr unsafe.Pointer) C.int {
 // SolveWithContext wraps Solve() with a context. If the context is cancelled or times out, the solution search will be
 // aborted and the context error will be returned.
 // Note that if some solution has already been found, res.Status() will be SolutionSuboptimal.
-func (model *Model) SolveWithContext(ctx context.Context) (res *SolveResult, err error) {
-	C.put_abortfunc(model.prob, (*C.lphandle_intfunc)(C.abortCallback), saveRef(ctx))
-	defer C.put_abortfunc(model.prob, nil, nil)
-
-	ret, err := model.Solve()
+func (model *Model) SolveWithContext(ctx context.Context, opts ...SolveOption) (res *SolveResult, err error) {
+	ret, err := model.Solve(append(opts, func(o *solveOptions) { o.ctx = ctx })...)
 
 	if errors.Is(err, ErrUserAbort) {
 		return ret, ctx.Err()
@@ -429,10 +718,24 @@ func lpexCallback(bufPtr unsafe.Pointer, buf *C.char) C.int {
 	return 0
 }
 
-// ExportLP returns the model in lp format.
-func (model *Model) ExportLP() (string, error) {
-	model.mu.RLock()
-	defer model.mu.RUnlock()
+// ExportLP returns the model in lp format. By default, lp_solve's own
+// dialect is used; see WithDialect for alternatives.
+func (model *Model) ExportLP(opts ...ExportOption) (string, error) {
+	var o exportOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.dialect == DialectCPLEX {
+		return model.readData().cplexLP(), nil
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.loadSpooledConstraints(); err != nil {
+		return "", err
+	}
 
 	buf := bytes.Buffer{}
 
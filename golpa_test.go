@@ -17,11 +17,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package golpa
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -53,7 +60,7 @@ func getBigModelCopy(t *testing.T) *Model {
 			v, _ := model.AddIntegerVariable(fmt.Sprintf("x%d", i))
 			vars[i] = v
 			coefs[i] = 1
-			err := model.AddConstraint(-float64(i), float64(i), []*Variable{v}, []float64{1})
+			_, err := model.AddConstraint(-float64(i), float64(i), []*Variable{v}, []float64{1})
 			require.NoError(t, err)
 		}
 
@@ -80,7 +87,7 @@ func TestClone(t *testing.T) {
 	v, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 2, 3)
 	require.NoError(t, err)
 
-	err = model.AddConstraint(0, 1, []*Variable{v}, []float64{1})
+	_, err = model.AddConstraint(0, 1, []*Variable{v}, []float64{1})
 	require.NoError(t, err)
 
 	modelClone := model.Clone()
@@ -134,6 +141,259 @@ func TestSetObjectiveFunction(t *testing.T) {
 	}
 }
 
+func TestConstraints(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+
+	c1, err := model.AddConstraint(1, 2, []*Variable{x, y}, []float64{3, 4})
+	require.NoError(t, err)
+	c2, err := model.AddConstraint(math.Inf(-1), 5, []*Variable{y}, []float64{6})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Constraint{c1, c2}, model.Constraints())
+	assert.Equal(t, 2, model.ConstraintCount())
+
+	l, h := c1.Bounds()
+	assert.Equal(t, 1.0, l)
+	assert.Equal(t, 2.0, h)
+
+	l, h = c2.Bounds()
+	assert.Equal(t, math.Inf(-1), l)
+	assert.Equal(t, 5.0, h)
+
+	vars, coefs := c1.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{3, 4}, coefs)
+
+	c2.SetName("c2")
+	c2.SetBounds(5, 5)
+	assert.Equal(t, "c2", c2.Name())
+	l, h = c2.Bounds()
+	assert.Equal(t, 5.0, l)
+	assert.Equal(t, 5.0, h)
+
+	cons := model.Constraints()
+	cons[0] = nil
+	assert.Equal(t, []*Constraint{c1, c2}, model.Constraints())
+}
+
+func TestConstraintDeduplication(t *testing.T) {
+	model, err := NewModel("test", Maximize, WithConstraintDeduplication())
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+
+	c1, err := model.AddConstraint(1, 2, []*Variable{x, y}, []float64{3, 4})
+	require.NoError(t, err)
+	c2, err := model.AddConstraint(1, 2, []*Variable{y, x}, []float64{4, 3})
+	require.NoError(t, err)
+	c3, err := model.AddConstraint(1, 3, []*Variable{x, y}, []float64{3, 4})
+	require.NoError(t, err)
+
+	assert.Same(t, c1, c2)
+	assert.NotSame(t, c1, c3)
+	assert.Equal(t, 2, model.ConstraintCount())
+
+	// modified constraints are not considered duplicates anymore
+	c1.SetBounds(0, 2)
+	c4, err := model.AddConstraint(1, 2, []*Variable{x, y}, []float64{3, 4})
+	require.NoError(t, err)
+	assert.NotSame(t, c1, c4)
+	assert.Equal(t, 3, model.ConstraintCount())
+
+	// constraints changed by SetCoefficient are found by their new terms
+	require.NoError(t, model.SetCoefficient(c3, y, 5))
+	c5, err := model.AddConstraint(1, 3, []*Variable{x, y}, []float64{3, 5})
+	require.NoError(t, err)
+	assert.Same(t, c3, c5)
+
+	// terms and bounds are compared in normalized form
+	c6, err := model.AddConstraint(math.Copysign(0, -1), 1, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	c7, err := model.AddConstraint(0, 1, []*Variable{y, x, x, y}, []float64{1, 0.5, 0.5, 0})
+	require.NoError(t, err)
+	assert.Same(t, c6, c7)
+
+	// keys stay consistent after renumbering variables
+	z, _ := model.AddVariable("z")
+	require.NoError(t, model.RemoveVariable(x))
+	c8, err := model.AddConstraint(0, 5, []*Variable{z, y}, []float64{1, 1})
+	require.NoError(t, err)
+	c9, err := model.AddConstraint(0, 5, []*Variable{y, z, z}, []float64{1, 0.5, 0.5})
+	require.NoError(t, err)
+	assert.Same(t, c8, c9)
+}
+
+func TestCanonical(t *testing.T) {
+	build := func(reverse bool) *Model {
+		model, err := NewModel("test", Minimize)
+		require.NoError(t, err)
+
+		names := []string{"x", "y"}
+		if reverse {
+			names = []string{"y", "x"}
+		}
+		vars := map[string]*Variable{}
+		for _, name := range names {
+			vars[name], err = model.AddDefinedVariable(name, ContinuousVariable, 1, 0, math.Inf(1))
+			require.NoError(t, err)
+		}
+
+		rows := []func() error{
+			func() error {
+				_, err := model.AddConstraint(1, 2, []*Variable{vars["x"], vars["y"]}, []float64{3, 4})
+				return err
+			},
+			func() error {
+				_, err := model.AddConstraint(math.Inf(-1), 5, []*Variable{vars["y"]}, []float64{-6})
+				return err
+			},
+		}
+		if reverse {
+			rows[0], rows[1] = rows[1], rows[0]
+		}
+		for _, add := range rows {
+			require.NoError(t, add())
+		}
+
+		return model
+	}
+
+	expected := `name: test
+direction: minimize
+objective: +1 x +1 y
+variables:
+  x continuous [0, +inf]
+  y continuous [0, +inf]
+constraints:
+  -inf <= -6 y <= 5
+  1 <= +3 x +4 y <= 2
+`
+
+	assert.Equal(t, expected, build(false).Canonical())
+	assert.Equal(t, expected, build(true).Canonical())
+}
+
+func TestDiff(t *testing.T) {
+	build := func(xUpper, coef float64, extra bool) *Model {
+		model, err := NewModel("test", Minimize)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, xUpper)
+		y, _ := model.AddDefinedVariable("y", IntegerVariable, 2, 0, 10)
+		_, err = model.AddConstraint(1, 2, []*Variable{x, y}, []float64{coef, 4})
+		require.NoError(t, err)
+
+		if extra {
+			z, _ := model.AddVariable("z")
+			c, err := model.AddConstraint(math.Inf(-1), 3, []*Variable{z}, []float64{1})
+			require.NoError(t, err)
+			c.SetName("limit")
+		}
+
+		return model
+	}
+
+	base := build(10, 3, false)
+
+	assert.True(t, base.Diff(build(10, 3, false)).Empty())
+	assert.True(t, base.Equal(build(10, 3.0000001, false), 1e-6))
+	assert.False(t, base.Equal(build(10, 3.0000001, false), 0))
+
+	d := base.Diff(build(20, 5, true))
+	assert.False(t, d.DirectionChanged)
+	assert.Equal(t, []string{"z"}, d.AddedVariables)
+	assert.Empty(t, d.RemovedVariables)
+	assert.Equal(t, []string{"x: bounds [0, 10] -> [0, 20]"}, d.ChangedVariables)
+	assert.Equal(t, []string{"limit: -inf <= +1 z <= 3"}, d.AddedConstraints)
+	assert.Empty(t, d.RemovedConstraints)
+	assert.Equal(t, []string{"1 <= +3 x +4 y <= 2 -> 1 <= +5 x +4 y <= 2"}, d.ChangedConstraints)
+}
+
+func TestRedundantConstraints(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 10)
+
+	c1, _ := model.AddConstraint(math.Inf(-1), 100, []*Variable{x, y}, []float64{1, 1})
+	c2, _ := model.AddConstraint(math.Inf(-1), 5, []*Variable{x, y}, []float64{1, 1})
+	c3, _ := model.AddConstraint(math.Inf(-1), 20, []*Variable{y, x}, []float64{2, 2})
+	c4, _ := model.AddConstraint(math.Inf(-1), 5, []*Variable{x, y}, []float64{1, 1})
+	c5, _ := model.AddConstraint(1, math.Inf(1), []*Variable{x, y}, []float64{1, -1})
+	c6, _ := model.AddConstraint(math.Inf(-1), -2, []*Variable{x, y}, []float64{-2, 2})
+
+	assert.Equal(t, []*Constraint{c1, c3, c4, c6}, model.FindRedundantConstraints())
+
+	assert.Equal(t, []*Constraint{c1, c3, c4, c6}, model.RemoveRedundantConstraints())
+	assert.Equal(t, []*Constraint{c2, c5}, model.Constraints())
+	assert.Equal(t, 2, model.ConstraintCount())
+
+	l, h := c5.Bounds()
+	assert.Equal(t, 1.0, l)
+	assert.Equal(t, math.Inf(1), h)
+}
+
+func TestTightenBounds(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, math.Inf(1))
+	z, _ := model.AddIntegerVariable("z")
+	w, _ := model.AddDefinedVariable("w", ContinuousVariable, 1, 0, 1)
+
+	model.AddConstraint(math.Inf(-1), 10, []*Variable{x, y}, []float64{1, 1})
+	model.AddConstraint(4, math.Inf(1), []*Variable{x, y}, []float64{2, -1})
+	model.AddConstraint(0.5, 7.5, []*Variable{z}, []float64{2})
+
+	changes, err := model.TightenBounds()
+	require.NoError(t, err)
+
+	assert.Equal(t, []BoundChange{
+		{Variable: x, OldLower: 0, OldUpper: math.Inf(1), NewLower: 2, NewUpper: 10},
+		{Variable: y, OldLower: 0, OldUpper: math.Inf(1), NewLower: 0, NewUpper: 8},
+		{Variable: z, OldLower: math.Inf(-1), OldUpper: math.Inf(1), NewLower: 1, NewUpper: 3},
+	}, changes)
+
+	l, h := z.Bounds()
+	assert.Equal(t, 1.0, l)
+	assert.Equal(t, 3.0, h)
+	l, h = w.Bounds()
+	assert.Equal(t, 0.0, l)
+	assert.Equal(t, 1.0, h)
+
+	model.AddConstraint(11, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	_, err = model.TightenBounds()
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+}
+
+func TestCheckCoefficientRanges(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+
+	model.AddConstraint(0, 1, []*Variable{x, y}, []float64{1, 1000})
+	c, _ := model.AddConstraint(0, 1, []*Variable{x, y}, []float64{-1e-5, 1e6})
+
+	warnings := model.CheckCoefficientRanges(DefaultMaxCoefficientRatio)
+	require.Len(t, warnings, 1)
+	assert.Same(t, c, warnings[0].Constraint)
+	assert.Equal(t, 1e-5, warnings[0].Min)
+	assert.Equal(t, 1e6, warnings[0].Max)
+	assert.InDelta(t, 1e11, warnings[0].Ratio(), 1)
+	assert.InDelta(t, 1/math.Sqrt(10), warnings[0].SuggestedScale, delta)
+
+	assert.Len(t, model.CheckCoefficientRanges(100), 2)
+}
+
 func TestSolveMIP(t *testing.T) {
 	model, err := NewModel("test", Maximize)
 	require.NoError(t, err)
@@ -191,6 +451,368 @@ func TestSolveLP(t *testing.T) {
 	}
 }
 
+func TestConstraintSpool(t *testing.T) {
+	model, err := NewModel("test", Maximize, WithConstraintSpool(t.TempDir()))
+	require.NoError(t, err)
+
+	x1, _ := model.AddDefinedVariable("x1", ContinuousVariable, 1, 0, math.Inf(1))
+	x2, _ := model.AddDefinedVariable("x2", ContinuousVariable, 2, 0, math.Inf(1))
+	x3, _ := model.AddDefinedVariable("x3", ContinuousVariable, -1, 0, math.Inf(1))
+
+	_, err = model.AddConstraint(math.Inf(-1), 14, []*Variable{x1, x2, x3}, []float64{2, 1, 1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 28, []*Variable{x1, x2, x3}, []float64{4, 2, 3})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 30, []*Variable{x1, x2, x3}, []float64{2, 5, 5})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, model.ConstraintCount())
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, model.ConstraintCount())
+	assert.InDelta(t, 13.0, res.ObjectiveValue(), delta)
+
+	for i, x := range []*Variable{x1, x2, x3} {
+		assert.InDelta(t, []float64{5, 4, 0}[i], res.Value(x), delta)
+	}
+}
+
+func TestConstraintRows(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithConstraintSpool(t.TempDir())}} {
+		model, err := NewModel("test", Maximize, opts...)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+
+		// ranged, free and empty constraints take one row each
+		_, err = model.AddConstraint(2, 4, []*Variable{x}, []float64{1})
+		require.NoError(t, err)
+		_, err = model.AddConstraint(math.Inf(-1), math.Inf(1), []*Variable{x}, []float64{1})
+		require.NoError(t, err)
+		_, err = model.AddConstraint(0, 0, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, model.ConstraintCount())
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.Equal(t, 3, model.ConstraintCount())
+		assert.InDelta(t, 4, res.Value(x), delta)
+	}
+}
+
+func TestInfeasibilityCertificate(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, math.Inf(1))
+	z, _ := model.AddDefinedVariable("z", ContinuousVariable, 1, 0, math.Inf(1))
+
+	sum, _ := model.AddConstraint(10, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	xMax, _ := model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{1})
+	yMax, _ := model.AddConstraint(math.Inf(-1), 3, []*Variable{y}, []float64{1})
+	model.AddConstraint(math.Inf(-1), 3, []*Variable{z}, []float64{1}) // unrelated
+
+	res, err := model.Solve()
+	require.ErrorIs(t, err, ErrModelInfeasible)
+	require.NotNil(t, res)
+	assert.Equal(t, SolutionInfeasible, res.Status())
+
+	cert, err := res.InfeasibilityCertificate()
+	require.NoError(t, err)
+
+	require.Len(t, cert, 3)
+	assert.Greater(t, cert[sum], 0.0)
+	assert.InDelta(t, -cert[sum], cert[xMax], delta)
+	assert.InDelta(t, -cert[sum], cert[yMax], delta)
+}
+
+func TestUnboundedRay(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, math.Inf(1))
+	z, _ := model.AddDefinedVariable("z", ContinuousVariable, 1, 0, 5)
+
+	model.AddConstraint(math.Inf(-1), 10, []*Variable{x, z}, []float64{1, 1})
+	model.AddConstraint(math.Inf(-1), 0, []*Variable{x, y}, []float64{1, -1})
+
+	res, err := model.Solve()
+	require.ErrorIs(t, err, ErrModelUnbounded)
+	require.NotNil(t, res)
+	assert.Equal(t, SolutionUnbounded, res.Status())
+
+	ray, err := res.UnboundedRay()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[*Variable]float64{y: 1}, ray)
+}
+
+func TestDual(t *testing.T) {
+	for _, dir := range []direction{Minimize, Maximize} {
+		model, err := NewModel("test", dir)
+		require.NoError(t, err)
+
+		sign := 1.0
+		if dir == Minimize {
+			sign = -1.0
+		}
+
+		x1, _ := model.AddDefinedVariable("x1", ContinuousVariable, sign*1, 0, math.Inf(1))
+		x2, _ := model.AddDefinedVariable("x2", ContinuousVariable, sign*2, 0, 3)
+		x3, _ := model.AddDefinedVariable("x3", ContinuousVariable, sign*-1, math.Inf(-1), math.Inf(1))
+
+		model.AddConstraint(0, 14, []*Variable{x1, x2, x3}, []float64{2, 1, 1})
+		model.AddConstraint(math.Inf(-1), 28, []*Variable{x1, x2, x3}, []float64{4, 2, 3})
+		model.AddConstraint(2, 2, []*Variable{x1, x3}, []float64{1, -1})
+
+		dual, err := model.Dual()
+		require.NoError(t, err)
+		assert.Equal(t, 3, dual.ConstraintCount())
+		assert.NotEqual(t, model.Direction(), dual.Direction())
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		dualRes, err := dual.Solve()
+		require.NoError(t, err)
+
+		assert.InDelta(t, res.ObjectiveValue(), dualRes.ObjectiveValue(), delta)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x1, _ := model.AddDefinedVariable("x1", ContinuousVariable, 1, 0, math.Inf(1))
+	x2, _ := model.AddDefinedVariable("x2", ContinuousVariable, 2, 0, math.Inf(1))
+	x3, _ := model.AddDefinedVariable("x3", ContinuousVariable, -1, 0, math.Inf(1))
+
+	model.AddConstraint(0, 14, []*Variable{x1, x2, x3}, []float64{2, 1, 1})
+	model.AddConstraint(0, 28, []*Variable{x1, x2, x3}, []float64{4, 2, 3})
+	model.AddConstraint(0, 30, []*Variable{x1, x2, x3}, []float64{2, 5, 5})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	d := res.Diagnostics()
+	assert.False(t, d.Suspicious(), d.Warnings)
+	assert.Empty(t, d.Residuals)
+	assert.Equal(t, 1.0, d.MinCoefficient)
+	assert.Equal(t, 5.0, d.MaxCoefficient)
+	assert.True(t, d.BasisAnalyzed)
+	assert.GreaterOrEqual(t, d.ConditionEstimate, 1.0)
+	assert.Greater(t, d.SmallestPivot, 0.0)
+}
+
+func TestSolveParametricRHS(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+
+	capacity, _ := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	model.AddConstraint(0, 0, []*Variable{x, y}, []float64{1, -1})
+
+	points, err := model.SolveParametricRHS(capacity, []float64{2, 6, -1})
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+
+	assert.NoError(t, points[0].Err)
+	assert.Equal(t, 2.0, points[0].Parameter)
+	assert.InDelta(t, 3.0, points[0].ObjectiveValue, delta)
+	assert.InDeltaSlice(t, []float64{1, 1}, points[0].Values, delta)
+
+	assert.NoError(t, points[1].Err)
+	assert.InDelta(t, 9.0, points[1].ObjectiveValue, delta)
+
+	assert.ErrorIs(t, points[2].Err, ErrModelInfeasible)
+
+	l, h := capacity.Bounds()
+	assert.Equal(t, math.Inf(-1), l)
+	assert.Equal(t, 4.0, h)
+}
+
+func TestSolveParametricObjective(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+
+	model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+
+	points, err := model.SolveParametricObjective(x, []float64{1, 3})
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+
+	assert.InDelta(t, 8.0, points[0].ObjectiveValue, delta)
+	assert.InDeltaSlice(t, []float64{0, 4}, points[0].Values, delta)
+	assert.InDelta(t, 12.0, points[1].ObjectiveValue, delta)
+	assert.InDeltaSlice(t, []float64{4, 0}, points[1].Values, delta)
+
+	assert.Equal(t, 1.0, x.Coefficient())
+}
+
+func TestParams(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 10)
+	demand, _ := model.AddConstraint(math.Inf(-1), 0, []*Variable{x, y}, []float64{1, 1})
+
+	p, err := model.NewParam("demand", 5)
+	require.NoError(t, err)
+	p.BindConstraintUpper(demand)
+	p.BindUpperBound(y)
+
+	price, err := model.NewParam("price", 3)
+	require.NoError(t, err)
+	price.BindObjectiveCoefficient(x)
+	price.BindCoefficient(demand, x)
+
+	_, err = model.NewParam("demand", 1)
+	assert.Error(t, err)
+
+	_, h := demand.Bounds()
+	assert.Equal(t, 5.0, h)
+	_, h = y.Bounds()
+	assert.Equal(t, 5.0, h)
+	assert.Equal(t, 3.0, x.Coefficient())
+
+	require.NoError(t, model.SetParam("demand", 12))
+	assert.Equal(t, 12.0, model.Param("demand").Value())
+	_, h = demand.Bounds()
+	assert.Equal(t, 12.0, h)
+	_, h = y.Bounds()
+	assert.Equal(t, 12.0, h)
+
+	require.NoError(t, model.SetParam("price", 1))
+	vars, coefs := demand.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{1, 1}, coefs)
+
+	// like bounds, coefficients are bound in scaled form
+	require.NoError(t, model.ScaleConstraint(demand, 2))
+	weight, err := model.NewParam("weight", 4)
+	require.NoError(t, err)
+	weight.BindCoefficient(demand, y)
+	_, coefs = demand.Terms()
+	assert.Equal(t, []float64{2, 4}, coefs)
+
+	// bindings to removed constraints leave the objective alone
+	require.NoError(t, model.RemoveConstraint(demand))
+	require.NoError(t, model.SetParam("weight", 7))
+	assert.Equal(t, 1.0, y.Coefficient())
+
+	assert.Error(t, model.SetParam("unknown", 1))
+}
+
+func TestStochastic(t *testing.T) {
+	// newsvendor: order at cost 1, sell at price 3 for an uncertain demand
+	first, err := NewModel("newsvendor", Maximize)
+	require.NoError(t, err)
+
+	order, _ := first.AddDefinedVariable("order", ContinuousVariable, -1, 0, 100)
+
+	st := NewStochastic(first)
+	for _, scenario := range []struct {
+		name   string
+		p      float64
+		demand float64
+	}{
+		{"low", 0.25, 10},
+		{"mid", 0.5, 20},
+		{"high", 0.25, 30},
+	} {
+		demand := scenario.demand
+		st.AddScenario(scenario.name, scenario.p, func(model *Model, firstStage []*Variable) error {
+			sold, err := model.AddDefinedVariable("sold", ContinuousVariable, 3, 0, demand)
+			if err != nil {
+				return err
+			}
+			_, err = model.AddConstraint(math.Inf(-1), 0, []*Variable{sold, firstStage[0]}, []float64{1, -1})
+			return err
+		})
+	}
+
+	res, err := st.Solve()
+	require.NoError(t, err)
+
+	// selling probability at 20 is 0.75 > 1/3, at 30 is 0.25 < 1/3
+	assert.InDelta(t, 20, res.FirstStageValue(order), delta)
+	assert.InDelta(t, -20+3*(0.25*10+0.5*20+0.25*20), res.ObjectiveValue(), delta)
+
+	st.AddScenario("extra", 0.1, func(*Model, []*Variable) error { return nil })
+	_, err = st.Solve()
+	assert.Error(t, err)
+}
+
+func TestAddRobustConstraint(t *testing.T) {
+	for _, tt := range []struct {
+		budget   float64
+		expected float64
+	}{
+		{0, 10},                 // nominal: x + y <= 10
+		{1, 8},                  // one coefficient may become 1.5
+		{math.Inf(1), 20.0 / 3}, // both coefficients may become 1.5
+	} {
+		model, err := NewModel("test", Maximize)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, math.Inf(1))
+		y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, math.Inf(1))
+		model.AddConstraint(0, 0, []*Variable{x, y}, []float64{1, -1})
+
+		_, upper, err := model.AddRobustConstraint(math.Inf(-1), 10, []*Variable{x, y}, []float64{1, 1}, []float64{0.5, 0.5}, tt.budget)
+		require.NoError(t, err)
+		require.NotNil(t, upper)
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, tt.expected, res.ObjectiveValue(), delta, "budget %g", tt.budget)
+	}
+
+	// auxiliary variables do not collide with each other
+	model, err := NewModel("test", Maximize, WithDuplicateNames(RejectDuplicateNames))
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, -5, 5)
+	for _, budget := range []float64{1, 1} {
+		_, _, err = model.AddRobustConstraint(math.Inf(-1), 10, []*Variable{x}, []float64{1}, []float64{0.5}, budget)
+		require.NoError(t, err)
+	}
+}
+
+func TestAddColumn(t *testing.T) {
+	// cover a demand of 10 using patterns of different cost
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	demand, _ := model.AddConstraint(10, math.Inf(1), []*Variable{x}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 30, res.ObjectiveValue(), delta)
+
+	y, err := model.AddColumn(5, map[*Constraint]float64{demand: 2}, 0, math.Inf(1), ContinuousVariable)
+	require.NoError(t, err)
+
+	vars, coefs := demand.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{1, 2}, coefs)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 25, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 5, res.Value(y), delta)
+}
+
 func TestBig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -254,3 +876,2057 @@ func BenchmarkMemoryLeaks(b *testing.B) {
 	runtime.GC()
 	time.Sleep(10 * time.Second)
 }
+
+func TestAddCardinality(t *testing.T) {
+	model, err := NewModel("cardinality", Maximize)
+	require.NoError(t, err)
+
+	vars := make([]*Variable, 5)
+	for i := range vars {
+		vars[i], err = model.AddBinaryVariable(fmt.Sprintf("b%d", i))
+		require.NoError(t, err)
+	}
+
+	c, err := model.AddCardinality(vars, 2, 3)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+
+	model.SetDirection(Minimize)
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 2, res.ObjectiveValue(), delta)
+
+	l, u := c.Bounds()
+	assert.Equal(t, 2.0, l)
+	assert.Equal(t, 3.0, u)
+
+	_, err = model.AddCardinality(vars, 3, 2)
+	assert.Error(t, err)
+
+	x, err := model.AddVariable("x")
+	require.NoError(t, err)
+	_, err = model.AddCardinality([]*Variable{x}, 0, 1)
+	assert.Error(t, err)
+}
+
+func TestAddAtMostOne(t *testing.T) {
+	for _, n := range []int{3, 10} {
+		model, err := NewModel("at most one", Maximize)
+		require.NoError(t, err)
+
+		vars := make([]*Variable, n)
+		for i := range vars {
+			vars[i], err = model.AddDefinedVariable(fmt.Sprintf("b%d", i), BinaryVariable, float64(i), 0, 1)
+			require.NoError(t, err)
+		}
+
+		_, err = model.AddAtMostOne(vars)
+		require.NoError(t, err)
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, float64(n-1), res.ObjectiveValue(), delta, "%d variables", n)
+	}
+}
+
+func TestAddFixedCharge(t *testing.T) {
+	model, err := NewModel("fixed charge", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 2, 0, 20)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", BinaryVariable, 10, 0, 1)
+	require.NoError(t, err)
+
+	demand, err := model.AddConstraint(3, math.Inf(1), []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	c, err := model.AddFixedCharge(x, y, 0)
+	require.NoError(t, err)
+	vars, coefs := c.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{1, -20}, coefs)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 16, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 1, res.Value(y), delta)
+
+	demand.SetBounds(0, math.Inf(1))
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 0, res.ObjectiveValue(), delta)
+
+	free, err := model.AddVariable("free")
+	require.NoError(t, err)
+	_, err = model.AddFixedCharge(free, y, 10)
+	assert.Error(t, err)
+	_, err = model.AddFixedCharge(x, free, 10)
+	assert.Error(t, err)
+}
+
+func TestAddProduct(t *testing.T) {
+	model, err := NewModel("product", Minimize)
+	require.NoError(t, err)
+
+	a, err := model.AddDefinedVariable("a", BinaryVariable, 0, 0, 1)
+	require.NoError(t, err)
+	b, err := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+	require.NoError(t, err)
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 0, -2, 5)
+	require.NoError(t, err)
+
+	ab, err := model.AddBinaryProduct(a, b)
+	require.NoError(t, err)
+	bx, err := model.AddProduct(b, x)
+	require.NoError(t, err)
+
+	for _, va := range []float64{0, 1} {
+		for _, vb := range []float64{0, 1} {
+			for _, vx := range []float64{-2, 3} {
+				a.SetBounds(va, va)
+				b.SetBounds(vb, vb)
+				x.SetBounds(vx, vx)
+
+				// the products are fully determined, whatever the direction
+				for _, dir := range []direction{Minimize, Maximize} {
+					model.SetDirection(dir)
+					ab.SetObjectiveCoefficient(1)
+					bx.SetObjectiveCoefficient(1)
+
+					res, err := model.Solve()
+					require.NoError(t, err)
+					assert.InDelta(t, va*vb, res.Value(ab), delta, "a=%g b=%g", va, vb)
+					assert.InDelta(t, vb*vx, res.Value(bx), delta, "b=%g x=%g", vb, vx)
+				}
+			}
+		}
+	}
+
+	_, err = model.AddBinaryProduct(a, x)
+	assert.Error(t, err)
+
+	free, err := model.AddVariable("free")
+	require.NoError(t, err)
+	_, err = model.AddProduct(b, free)
+	assert.Error(t, err)
+}
+
+func TestVariableCollections(t *testing.T) {
+	model, err := NewModel("collections", Maximize)
+	require.NoError(t, err)
+
+	s, err := model.AddVariableSlice("x", 3, ContinuousVariable, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, s, 3)
+	assert.Equal(t, "x[2]", s[2].Name())
+
+	m, err := model.AddVariableMatrix("y", 2, 3, IntegerVariable, 0, 5)
+	require.NoError(t, err)
+	require.Len(t, m, 2)
+	assert.Equal(t, "y[1][2]", m[1][2].Name())
+	assert.Equal(t, IntegerVariable, m[0][0].Type())
+	assert.Equal(t, VariableSlice{m[0][1], m[1][1]}, m.Column(1))
+
+	vars, coefs := m.Sum().Terms()
+	assert.Len(t, vars, 6)
+	assert.Equal(t, []float64{1, 1, 1, 1, 1, 1}, coefs)
+
+	_, err = model.AddExprConstraint(math.Inf(-1), 7, m.Sum().Plus(s.Sum()).PlusConstant(1))
+	require.NoError(t, err)
+
+	for _, v := range append(s, m.Row(0)...) {
+		v.SetObjectiveCoefficient(1)
+	}
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 6, res.ObjectiveValue(), delta)
+}
+
+func TestExpr(t *testing.T) {
+	model, err := NewModel("expr", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariable("x")
+	require.NoError(t, err)
+	y, err := model.AddVariable("y")
+	require.NoError(t, err)
+
+	e := Term(2, x).Plus(Term(1, y)).Plus(Term(-1, x)).PlusConstant(3).Scale(2)
+	vars, coefs := e.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{2, 2}, coefs)
+	assert.Equal(t, 6.0, e.Constant())
+
+	c, err := model.AddExprConstraint(10, 10, e)
+	require.NoError(t, err)
+	l, u := c.Bounds()
+	assert.Equal(t, 4.0, l)
+	assert.Equal(t, 4.0, u)
+}
+
+func TestSumDot(t *testing.T) {
+	model, err := NewModel("sum", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariableSlice("x", 3, ContinuousVariable, 0, 10)
+	require.NoError(t, err)
+
+	_, err = model.AddExprConstraint(math.Inf(-1), 12, Sum(x...))
+	require.NoError(t, err)
+	_, err = model.AddExprConstraint(math.Inf(-1), 4, Sum(x[0], x[1]).Scale(-1).Plus(Term(1, x[2])))
+	require.NoError(t, err)
+
+	require.NoError(t, model.SetObjectiveExpr(Dot([]float64{1, 2, 3}, x)))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	// x2 = x0 + x1 + 4 and x0 + x1 + x2 = 12, with all of x0 + x1 on x1
+	assert.InDelta(t, 4, res.Value(x[1]), delta)
+	assert.InDelta(t, 8, res.Value(x[2]), delta)
+	assert.InDelta(t, 32, res.ObjectiveValue(), delta)
+
+	assert.Error(t, model.SetObjectiveExpr(Sum(x...).PlusConstant(1)))
+	assert.Panics(t, func() { Dot([]float64{1}, x) })
+}
+
+func TestForEach(t *testing.T) {
+	model, err := NewModel("foreach", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariableMatrix("x", 4, 3, ContinuousVariable, 0, 10)
+	require.NoError(t, err)
+
+	rows := []int{0, 1, 2, 3}
+	cons, err := model.ForEach(rows, func(i int) ConstraintSpec {
+		return ConstraintSpec{
+			Name:  fmt.Sprintf("row%d", i),
+			Lower: math.Inf(-1),
+			Upper: float64(i + 1),
+			Expr:  x.Row(i).Sum(),
+		}
+	})
+	require.NoError(t, err)
+	require.Len(t, cons, 4)
+	assert.Equal(t, 4, model.ConstraintCount())
+	assert.Equal(t, "row2", cons[2].Name())
+	_, u := cons[3].Bounds()
+	assert.Equal(t, 4.0, u)
+
+	require.NoError(t, model.SetObjectiveExpr(x.Sum()))
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 10, res.ObjectiveValue(), delta)
+
+	other, err := NewModel("other", Maximize)
+	require.NoError(t, err)
+	_, err = other.ForEach(rows, func(i int) ConstraintSpec {
+		return ConstraintSpec{Upper: 1, Expr: x.Row(i).Sum()}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, other.ConstraintCount())
+
+	// an invalid spec after valid ones adds none of them
+	count := model.ConstraintCount()
+	cons, err = model.ForEach([]int{0, 1}, func(i int) ConstraintSpec {
+		return ConstraintSpec{Lower: float64(i), Upper: 0, Expr: x.Row(i).Sum()}
+	})
+	assert.Error(t, err)
+	assert.Empty(t, cons)
+	assert.Equal(t, count, model.ConstraintCount())
+}
+
+func TestExportLPCPLEX(t *testing.T) {
+	model, err := NewModel("cplex", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", IntegerVariable, -2, math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", BinaryVariable, 0, 0, 1)
+	require.NoError(t, err)
+
+	c, err := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	c.SetName("cap")
+	_, err = model.AddConstraint(-1, 2, []*Variable{x, y, z}, []float64{-1, 2, 1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(1, 1, []*Variable{z}, []float64{1})
+	require.NoError(t, err)
+
+	lp, err := model.ExportLP(WithDialect(DialectCPLEX))
+	require.NoError(t, err)
+
+	assert.Equal(t, `\Problem name: cplex
+
+Maximize
+ obj: 3 x - 2 y
+Subject To
+ cap: 1 x + 1 y <= 4
+ R2_lo: - 1 x + 2 y + 1 z >= -1
+ R2_hi: - 1 x + 2 y + 1 z <= 2
+ R3: 1 z = 1
+Bounds
+ 0 <= x <= 10
+ y free
+General
+ y
+Binary
+ z
+End
+`, lp)
+}
+
+func TestExportLPCPLEXNames(t *testing.T) {
+	model, err := NewModel("names", Minimize)
+	require.NoError(t, err)
+
+	var vars []*Variable
+	for _, name := range []string{"x[3][7]", "x_3__7_", "v[scenario]", "unit cost", "2nd"} {
+		v, err := model.AddDefinedVariable(name, ContinuousVariable, 1, 0, 1)
+		require.NoError(t, err)
+		vars = append(vars, v)
+	}
+	c, err := model.AddConstraint(1, math.Inf(1), vars, []float64{1, 1, 1, 1, 1})
+	require.NoError(t, err)
+	c.SetName("demand[north]")
+
+	lp, err := model.ExportLP(WithDialect(DialectCPLEX))
+	require.NoError(t, err)
+
+	assert.Equal(t, `\Problem name: names
+
+Minimize
+ obj: 1 x_3__7_ + 1 x_3__7__2 + 1 v_scenario_ + 1 unit_cost + 1 _2nd
+Subject To
+ demand_north_: 1 x_3__7_ + 1 x_3__7__2 + 1 v_scenario_ + 1 unit_cost + 1 _2nd >= 1
+Bounds
+ 0 <= x_3__7_ <= 1
+ 0 <= x_3__7__2 <= 1
+ 0 <= v_scenario_ <= 1
+ 0 <= unit_cost <= 1
+ 0 <= _2nd <= 1
+End
+`, lp)
+}
+
+func TestAnonymize(t *testing.T) {
+	model, err := NewModel("secret", Minimize)
+	require.NoError(t, err)
+
+	price, err := model.AddDefinedVariable("price", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	margin, err := model.AddDefinedVariable("margin", ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+	c, err := model.AddConstraint(3, math.Inf(1), []*Variable{price, margin}, []float64{1, 1})
+	require.NoError(t, err)
+	c.SetName("floor")
+
+	anon, names := model.Anonymize()
+
+	assert.Equal(t, "model", anon.Name())
+	assert.Equal(t, "x2", anon.Variables()[1].Name())
+	assert.Equal(t, "c1", anon.Constraints()[0].Name())
+	assert.Equal(t, Anonymization{
+		Name:        "secret",
+		Variables:   map[string]string{"x1": "price", "x2": "margin"},
+		Constraints: map[string]string{"c1": "floor"},
+	}, names)
+
+	assert.Equal(t, "secret", model.Name())
+	assert.Equal(t, "price", price.Name())
+
+	lp, err := anon.ExportLP()
+	require.NoError(t, err)
+	assert.NotContains(t, lp, "price")
+
+	res, err := anon.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+}
+
+func TestSolveAs(t *testing.T) {
+	model, err := NewModel("range", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 2, 7)
+	require.NoError(t, err)
+
+	res, err := model.SolveAs(Maximize)
+	require.NoError(t, err)
+	assert.InDelta(t, 7, res.Value(x), delta)
+	assert.InDelta(t, 7, res.ObjectiveValue(), delta)
+	assert.Equal(t, Minimize, model.Direction())
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 2, res.ObjectiveValue(), delta)
+
+	res, err = model.Solve(WithDirection(Maximize))
+	require.NoError(t, err)
+	assert.InDelta(t, 7, res.ObjectiveValue(), delta)
+	assert.Equal(t, Minimize, model.Direction())
+}
+
+func TestAddObjective(t *testing.T) {
+	model, err := NewModel("objectives", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(4, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	require.NoError(t, model.AddObjective("cost", []float64{3, 1}, []*Variable{x, y}))
+	require.NoError(t, model.AddObjective("service", []float64{-1}, []*Variable{y}))
+	assert.Equal(t, []string{"cost", "service"}, model.Objectives())
+
+	res, err := model.Solve(WithObjective("cost"))
+	require.NoError(t, err)
+	assert.InDelta(t, 4, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 4, res.Value(y), delta)
+
+	res, err = model.Solve(WithObjective("service"))
+	require.NoError(t, err)
+	assert.InDelta(t, -10, res.ObjectiveValue(), delta)
+
+	assert.Equal(t, 1.0, x.Coefficient(), "model objective must be restored")
+
+	clone := model.Clone()
+	res, err = clone.Solve(WithObjective("cost"), WithDirection(Maximize))
+	require.NoError(t, err)
+	assert.InDelta(t, 40, res.ObjectiveValue(), delta)
+
+	_, err = model.Solve(WithObjective("unknown"))
+	assert.Error(t, err)
+}
+
+func TestSetQuadraticObjectiveApprox(t *testing.T) {
+	model, err := NewModel("quadratic", Minimize)
+	require.NoError(t, err)
+
+	// (x-3)^2 + (y-1.5)^2 without the constant terms
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, -6, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, -3, 0, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, model.SetQuadraticObjectiveApprox([]float64{1, 1}, []*Variable{x, y}, 10))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	// x's minimum is on a breakpoint; y's lies between two
+	assert.InDelta(t, 3, res.Value(x), delta)
+	assert.InDelta(t, -9-2.25+0.25, res.ObjectiveValue(), delta)
+
+	model.SetDirection(Maximize)
+	assert.Error(t, model.SetQuadraticObjectiveApprox([]float64{1}, []*Variable{x}, 10))
+
+	free, err := model.AddVariable("free")
+	require.NoError(t, err)
+	assert.Error(t, model.SetQuadraticObjectiveApprox([]float64{-1}, []*Variable{free}, 10))
+}
+
+func TestSolveFeasible(t *testing.T) {
+	model, err := knapsackModel(30)
+	require.NoError(t, err)
+
+	res, err := model.SolveFeasible()
+	require.NoError(t, err)
+	assert.Contains(t, []SolveStatus{SolutionOptimal, SolutionSuboptimal}, res.Status())
+
+	feasible := res.ObjectiveValue()
+	for _, c := range model.Constraints() {
+		vars, coefs := c.Terms()
+		total := 0.0
+		for i, v := range vars {
+			total += coefs[i] * res.Value(v)
+		}
+		_, u := c.Bounds()
+		assert.LessOrEqual(t, total, u+delta)
+	}
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.Equal(t, SolutionOptimal, res.Status())
+	assert.GreaterOrEqual(t, res.ObjectiveValue(), feasible-delta)
+}
+
+// knapsackModel returns a knapsack model with n items of pseudo-random
+// weights and values, which takes several branch-and-bound nodes to solve.
+func knapsackModel(n int) (*Model, error) {
+	model, err := NewModel("knapsack", Maximize)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]*Variable, n)
+	weights := make([]float64, n)
+	for i := range vars {
+		weights[i] = float64(17*i%23 + 5)
+		if vars[i], err = model.AddDefinedVariable("", BinaryVariable, float64(13*i%19+3), 0, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = model.AddConstraint(math.Inf(-1), float64(5*n), vars, weights)
+	return model, err
+}
+
+func TestFeasibilityPump(t *testing.T) {
+	model, err := knapsackModel(30)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 0, 0, math.Inf(1))
+	require.NoError(t, err)
+	_, err = model.AddConstraint(2.5, 7.5, []*Variable{x, y}, []float64{2, 1})
+	require.NoError(t, err)
+
+	solution, err := model.FeasibilityPump(100)
+	require.NoError(t, err)
+	require.Len(t, solution, model.VariableCount())
+
+	for _, v := range model.Variables() {
+		value := solution[v]
+		lower, upper := v.Bounds()
+		assert.GreaterOrEqual(t, value, lower-delta)
+		assert.LessOrEqual(t, value, upper+delta)
+		if v.Type() != ContinuousVariable {
+			assert.Equal(t, math.Round(value), value)
+		}
+	}
+	for _, c := range model.Constraints() {
+		vars, coefs := c.Terms()
+		total := 0.0
+		for i, v := range vars {
+			total += coefs[i] * solution[v]
+		}
+		l, u := c.Bounds()
+		assert.GreaterOrEqual(t, total, l-delta)
+		assert.LessOrEqual(t, total, u+delta)
+	}
+
+	assert.Equal(t, IntegerVariable, x.Type(), "original model must not be relaxed")
+}
+
+func TestFeasibilityPumpInfeasible(t *testing.T) {
+	model, err := NewModel("infeasible", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(11, math.Inf(1), []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	_, err = model.FeasibilityPump(10)
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+}
+
+func TestRoundAndRepair(t *testing.T) {
+	model, err := NewModel("repair", Minimize)
+	require.NoError(t, err)
+
+	// LP relaxation has x = 2.6, rounded to 3, leaving y to be repaired
+	x, err := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+	require.NoError(t, err)
+	_, err = model.AddConstraint(2.6, math.Inf(1), []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(4, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	solution, err := model.RoundAndRepair()
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, solution[x])
+	assert.InDelta(t, 1, solution[y], delta)
+	assert.Equal(t, IntegerVariable, x.Type(), "original model must not be relaxed")
+
+	// rounding x down to 2 cannot be repaired
+	model, err = NewModel("unrepairable", Minimize)
+	require.NoError(t, err)
+	x, err = model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(2.4, math.Inf(1), []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	_, err = model.RoundAndRepair()
+	assert.ErrorIs(t, err, ErrNoFeasibleFound)
+}
+
+func TestSolveNear(t *testing.T) {
+	model, err := knapsackModel(10)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	optimal := res.ObjectiveValue()
+
+	// a reference solution with no items selected
+	reference := make(map[*Variable]float64)
+	for _, v := range model.Variables() {
+		reference[v] = 0
+	}
+
+	for _, radius := range []int{0, 1, 2} {
+		res, err = model.SolveNear(reference, radius)
+		require.NoError(t, err)
+
+		changed := 0
+		for _, v := range model.Variables() {
+			changed += int(math.Round(res.Value(v)))
+		}
+		assert.LessOrEqual(t, changed, radius)
+		assert.LessOrEqual(t, res.ObjectiveValue(), optimal+delta)
+	}
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, optimal, res.ObjectiveValue(), delta, "distance constraint must be relaxed")
+
+	x, err := model.AddVariable("x")
+	require.NoError(t, err)
+	_, err = model.SolveNear(map[*Variable]float64{x: 1}, 1)
+	assert.Error(t, err)
+}
+
+func TestAddStabilityPenalty(t *testing.T) {
+	model, err := NewModel("stability", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+	c, err := model.AddConstraint(4, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	prev, err := model.Solve()
+	require.NoError(t, err)
+	require.InDelta(t, 3, prev.Value(x), delta)
+	require.InDelta(t, 1, prev.Value(y), delta)
+
+	// y is now cheaper, but moving away from the previous solution costs more
+	y.SetObjectiveCoefficient(0.5)
+	c.SetBounds(5, math.Inf(1))
+	require.NoError(t, model.AddStabilityPenalty(prev, map[*Variable]float64{x: 1, y: 0.1}))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.Value(x), delta)
+	assert.InDelta(t, 2, res.Value(y), delta)
+
+	assert.Error(t, model.AddStabilityPenalty(res, map[*Variable]float64{x: -1}))
+}
+
+func TestExcludeSolution(t *testing.T) {
+	model, err := NewModel("enumerate", Maximize)
+	require.NoError(t, err)
+
+	vars := make([]*Variable, 3)
+	for i := range vars {
+		vars[i], err = model.AddBinaryVariable("")
+		require.NoError(t, err)
+	}
+	_, err = model.AddConstraint(2, 2, vars, []float64{1, 1, 1})
+	require.NoError(t, err)
+
+	seen := make(map[[3]float64]bool)
+	for {
+		res, err := model.Solve()
+		if errors.Is(err, ErrModelInfeasible) {
+			break
+		}
+		require.NoError(t, err)
+
+		var solution [3]float64
+		for i, v := range vars {
+			solution[i] = res.Value(v)
+		}
+		assert.False(t, seen[solution], "solution %v found twice", solution)
+		seen[solution] = true
+
+		_, err = model.ExcludeSolution(res)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(seen), 3)
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestIntValue(t *testing.T) {
+	model, err := NewModel("rounding", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 2.4)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 2.9999999)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		opts []SolveOption
+		x, y int
+	}{
+		{nil, 2, 3},
+		{[]SolveOption{WithRounding(RoundDown, DefaultIntegralityTolerance)}, 2, 3},
+		{[]SolveOption{WithRounding(RoundDown, 0)}, 2, 2},
+		{[]SolveOption{WithRounding(RoundUp, DefaultIntegralityTolerance)}, 3, 3},
+	} {
+		res, err := model.Solve(tc.opts...)
+		require.NoError(t, err)
+		assert.Equal(t, tc.x, res.IntValue(x))
+		assert.Equal(t, tc.y, res.IntValue(y))
+	}
+}
+
+func TestBoolValue(t *testing.T) {
+	model, err := NewModel("bool", Maximize)
+	require.NoError(t, err)
+
+	a, err := model.AddDefinedVariable("a", BinaryVariable, 1, 0, 1)
+	require.NoError(t, err)
+	b, err := model.AddDefinedVariable("b", BinaryVariable, -1, 0, 1)
+	require.NoError(t, err)
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 1)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	value, err := res.BoolValue(a)
+	require.NoError(t, err)
+	assert.True(t, value)
+
+	value, err = res.BoolValue(b)
+	require.NoError(t, err)
+	assert.False(t, value)
+
+	_, err = res.BoolValue(x)
+	assert.Error(t, err)
+}
+
+func TestObjectiveValueInt(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	value, ok := res.ObjectiveValueInt()
+	require.True(t, ok)
+	assert.InDelta(t, res.ObjectiveValue(), float64(value), delta)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 0.5, 0, 1)
+	require.NoError(t, err)
+	res, err = model.Solve()
+	require.NoError(t, err)
+	_, ok = res.ObjectiveValueInt()
+	assert.False(t, ok, "continuous variables are not integral")
+
+	x.SetType(IntegerVariable)
+	res, err = model.Solve()
+	require.NoError(t, err)
+	_, ok = res.ObjectiveValueInt()
+	assert.False(t, ok, "fractional coefficients are not integral")
+
+	// named objectives are used when solved
+	require.NoError(t, model.AddObjective("x", []float64{3}, []*Variable{x}))
+	res, err = model.Solve(WithObjective("x"))
+	require.NoError(t, err)
+	value, ok = res.ObjectiveValueInt()
+	require.True(t, ok)
+	assert.Equal(t, int64(3), value)
+
+	// coefficients are read when called
+	require.NoError(t, model.AddObjective("x", []float64{5}, []*Variable{x}))
+	value, ok = res.ObjectiveValueInt()
+	require.True(t, ok)
+	assert.Equal(t, int64(5), value)
+
+	big, err := NewModel("big", Maximize)
+	require.NoError(t, err)
+	v, err := big.AddDefinedVariable("v", IntegerVariable, 1<<53, 0, 1<<20)
+	require.NoError(t, err)
+	res, err = big.Solve()
+	require.NoError(t, err)
+	_, ok = res.ObjectiveValueInt()
+	assert.False(t, ok, "overflowing objective values are rejected")
+	assert.InDelta(t, 1<<20, res.Value(v), delta)
+}
+
+func TestMaxViolation(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 0, res.MaxViolation(), delta)
+
+	// tightening a constraint after solving makes the solution violate it
+	c := model.Constraints()[0]
+	_, u := c.Bounds()
+	c.SetBounds(math.Inf(-1), u-1000)
+	assert.Greater(t, res.MaxViolation(), 1.0)
+}
+
+func TestSolveReusing(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	expected, err := model.Solve()
+	require.NoError(t, err)
+	values := make([]float64, model.VariableCount())
+	for i, v := range model.Variables() {
+		values[i] = expected.Value(v)
+	}
+
+	var ws Workspace
+	first, err := model.SolveReusing(&ws)
+	require.NoError(t, err)
+	second, err := model.SolveReusing(&ws)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	for i, v := range model.Variables() {
+		assert.InDelta(t, values[i], second.Value(v), delta)
+	}
+	objective := expected.ObjectiveValue()
+	assert.InDelta(t, objective, second.ObjectiveValue(), delta)
+
+	// later solves of the model don't affect the result
+	_, err = model.SolveAs(Minimize)
+	require.NoError(t, err)
+	assert.InDelta(t, objective, second.ObjectiveValue(), delta)
+}
+
+func BenchmarkSolveReusing(b *testing.B) {
+	model, err := knapsackModel(20)
+	require.NoError(b, err)
+	vars := model.Variables()
+
+	var ws Workspace
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		res, err := model.SolveReusing(&ws)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, v := range vars {
+			res.Value(v)
+		}
+	}
+}
+
+func TestTimings(t *testing.T) {
+	model, err := knapsackModel(30)
+	require.NoError(t, err)
+
+	start := time.Now()
+	res, err := model.Solve()
+	require.NoError(t, err)
+	elapsed := time.Since(start)
+
+	timings := res.Timings()
+	for _, d := range []time.Duration{timings.Build, timings.Presolve, timings.Simplex, timings.BranchAndBound, timings.Extract} {
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+	}
+	total := timings.Build + timings.Presolve + timings.Simplex + timings.BranchAndBound
+	assert.Greater(t, total, time.Duration(0))
+	assert.LessOrEqual(t, total, elapsed)
+	assert.Zero(t, timings.Extract)
+
+	var ws Workspace
+	res, err = model.SolveReusing(&ws)
+	require.NoError(t, err)
+	assert.Greater(t, res.Timings().Extract, time.Duration(0))
+}
+
+func TestSolveAsync(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.SolveAsync().Wait()
+	require.NoError(t, err)
+	assert.Equal(t, SolutionOptimal, res.Status())
+
+	// a large model which cannot be solved before being aborted
+	model, err = knapsackModel(2000)
+	require.NoError(t, err)
+
+	h := model.SolveAsync()
+	time.Sleep(10 * time.Millisecond)
+	aborted := time.Now()
+	h.Abort()
+
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("solve not aborted")
+	}
+	assert.Less(t, time.Since(aborted), time.Second)
+
+	res, err = h.Wait()
+	if err != nil {
+		assert.ErrorIs(t, err, ErrUserAbort)
+	} else {
+		assert.Contains(t, []SolveStatus{SolutionOptimal, SolutionSuboptimal}, res.Status())
+	}
+}
+
+func TestWithSoftDeadline(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.Solve(WithSoftDeadline(time.Minute, time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, SolutionOptimal, res.Status())
+
+	model, err = knapsackModel(2000)
+	require.NoError(t, err)
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 5, append(model.Variables()[:1:1], x), []float64{1, 1})
+	require.NoError(t, err)
+
+	start := time.Now()
+	res, err = model.Solve(WithSoftDeadline(10*time.Millisecond, 100*time.Millisecond))
+	assert.Less(t, time.Since(start), time.Second)
+	if err != nil {
+		assert.ErrorIs(t, err, ErrTimeout)
+		return
+	}
+
+	assert.InDelta(t, 0, res.MaxViolation(), delta)
+	for _, v := range model.Variables() {
+		if v.Type() != ContinuousVariable {
+			value := res.Value(v)
+			assert.InDelta(t, math.Round(value), value, delta)
+		}
+	}
+	assert.Equal(t, BinaryVariable, model.Variables()[0].Type(), "integrality must be restored")
+}
+
+func TestInputValidation(t *testing.T) {
+	model, err := NewModel("validation", Maximize)
+	require.NoError(t, err)
+	other, err := NewModel("other", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariable("x")
+	require.NoError(t, err)
+	y, err := other.AddVariable("y")
+	require.NoError(t, err)
+
+	_, err = model.AddDefinedVariable("nan", ContinuousVariable, math.NaN(), 0, 1)
+	assert.Error(t, err)
+	_, err = model.AddDefinedVariable("inverted", ContinuousVariable, 1, 2, 1)
+	assert.Error(t, err)
+	assert.Equal(t, 1, model.VariableCount())
+
+	for name, tc := range map[string]struct {
+		lower, upper float64
+		vars         []*Variable
+		coefs        []float64
+	}{
+		"length":    {0, 1, []*Variable{x}, []float64{1, 2}},
+		"nil":       {0, 1, []*Variable{nil}, []float64{1}},
+		"foreign":   {0, 1, []*Variable{y}, []float64{1}},
+		"nan coef":  {0, 1, []*Variable{x}, []float64{math.NaN()}},
+		"inf coef":  {0, 1, []*Variable{x}, []float64{math.Inf(1)}},
+		"nan bound": {math.NaN(), 1, []*Variable{x}, []float64{1}},
+		"inverted":  {2, 1, []*Variable{x}, []float64{1}},
+	} {
+		_, err := model.AddConstraint(tc.lower, tc.upper, tc.vars, tc.coefs)
+		assert.Error(t, err, name)
+	}
+	assert.Equal(t, 0, model.ConstraintCount())
+
+	assert.Error(t, model.SetObjectiveFunction([]float64{1}, []*Variable{y}))
+	assert.Error(t, model.SetObjectiveFunction([]float64{1, 2}, []*Variable{x}))
+}
+
+func TestTypedErrors(t *testing.T) {
+	model, err := NewModel("typed", Maximize)
+	require.NoError(t, err)
+	x, err := model.AddVariable("x")
+	require.NoError(t, err)
+	c, err := model.AddConstraint(0, 1, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	_, err = model.AddConstraint(math.Inf(1), math.Inf(1), []*Variable{x}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidBounds{Lower: math.Inf(1), Upper: math.Inf(1)})
+	_, err = model.AddConstraint(math.Inf(-1), math.Inf(-1), []*Variable{x}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidBounds{Lower: math.Inf(-1), Upper: math.Inf(-1)})
+
+	// setters ignore invalid values
+	lower, upper := x.Bounds()
+	x.SetBounds(math.NaN(), 1)
+	x.SetBounds(math.Inf(1), math.Inf(1))
+	l, u := x.Bounds()
+	assert.Equal(t, []float64{lower, upper}, []float64{l, u})
+	x.SetObjectiveCoefficient(math.Inf(1))
+	assert.Equal(t, 1.0, x.Coefficient())
+	c.SetBounds(2, 1)
+	l, u = c.Bounds()
+	assert.Equal(t, []float64{0, 1}, []float64{l, u})
+
+	_, err = model.AddConstraint(0, 1, []*Variable{x}, []float64{1, 2})
+	var constraintErr ErrConstraint
+	require.ErrorAs(t, err, &constraintErr)
+	assert.Equal(t, 1, constraintErr.Index)
+	assert.ErrorIs(t, err, ErrDimensionMismatch{Got: 2, Want: 1})
+
+	_, err = model.AddConstraint(0, 1, []*Variable{x}, []float64{math.Inf(-1)})
+	var coefErr ErrInvalidCoefficient
+	require.ErrorAs(t, err, &coefErr)
+	assert.Equal(t, "x", coefErr.Variable)
+	assert.Equal(t, 0, coefErr.Index)
+
+	_, err = model.AddDefinedVariable("y", ContinuousVariable, 0, 1, 0)
+	var varErr ErrVariable
+	require.ErrorAs(t, err, &varErr)
+	assert.Equal(t, "y", varErr.Name)
+	assert.ErrorIs(t, err, ErrInvalidBounds{Lower: 1, Upper: 0})
+
+	_, err = model.Solve(WithObjective("missing"))
+	assert.ErrorIs(t, err, ErrUnknownObjective{Name: "missing"})
+}
+
+func TestDuplicateNames(t *testing.T) {
+	model, err := NewModel("allow", Minimize)
+	require.NoError(t, err)
+	a, err := model.AddVariable("x")
+	require.NoError(t, err)
+	b, err := model.AddVariable("x")
+	require.NoError(t, err)
+	assert.Equal(t, a.Name(), b.Name())
+
+	model, err = NewModel("reject", Minimize, WithDuplicateNames(RejectDuplicateNames))
+	require.NoError(t, err)
+	_, err = model.AddVariable("x")
+	require.NoError(t, err)
+	_, err = model.AddVariable("x")
+	assert.ErrorIs(t, err, ErrDuplicateName{Name: "x"})
+	assert.Equal(t, 1, model.VariableCount())
+
+	_, err = model.Clone().AddVariable("x")
+	assert.ErrorIs(t, err, ErrDuplicateName{Name: "x"}, "clones keep the policy")
+
+	model, err = NewModel("uniquify", Minimize, WithDuplicateNames(UniquifyDuplicateNames))
+	require.NoError(t, err)
+	var names []string
+	for i := 0; i < 3; i++ {
+		v, err := model.AddVariable("x")
+		require.NoError(t, err)
+		names = append(names, v.Name())
+	}
+	v, err := model.AddVariable("x_2")
+	require.NoError(t, err)
+	names = append(names, v.Name())
+	assert.Equal(t, []string{"x", "x_2", "x_3", "x_2_2"}, names)
+}
+
+func TestAddAnonymousVariable(t *testing.T) {
+	model, err := NewModel("anonymous", Minimize, WithDuplicateNames(RejectDuplicateNames))
+	require.NoError(t, err)
+
+	_, err = model.AddVariable("_v1")
+	require.NoError(t, err)
+
+	names := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		v, err := model.AddAnonymousVariable(IntegerVariable, 0, 10)
+		require.NoError(t, err)
+		assert.False(t, names[v.Name()])
+		names[v.Name()] = true
+		assert.Equal(t, IntegerVariable, v.Type())
+		assert.Equal(t, 0.0, v.Coefficient())
+	}
+	assert.False(t, names["_v1"])
+
+	lp, err := model.ExportLP()
+	require.NoError(t, err)
+	assert.Contains(t, lp, "_v2")
+
+	// names are not reused after removing variables
+	model, err = NewModel("anonymous", Minimize)
+	require.NoError(t, err)
+	a, err := model.AddAnonymousVariable(ContinuousVariable, 0, 1)
+	require.NoError(t, err)
+	b, err := model.AddAnonymousVariable(ContinuousVariable, 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, model.RemoveVariable(a))
+	c, err := model.AddAnonymousVariable(ContinuousVariable, 0, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, b.Name(), c.Name())
+}
+
+func TestBoundConvenienceVariables(t *testing.T) {
+	model, err := NewModel("bounds", Minimize)
+	require.NoError(t, err)
+
+	free, err := model.AddFreeVariable("free")
+	require.NoError(t, err)
+	l, u := free.Bounds()
+	assert.True(t, math.IsInf(l, -1))
+	assert.True(t, math.IsInf(u, 1))
+
+	nonNeg, err := model.AddNonNegativeVariable("nonneg")
+	require.NoError(t, err)
+	l, u = nonNeg.Bounds()
+	assert.Equal(t, 0.0, l)
+	assert.True(t, math.IsInf(u, 1))
+
+	for _, v := range []*Variable{free, nonNeg} {
+		assert.Equal(t, ContinuousVariable, v.Type())
+		assert.Equal(t, 1.0, v.Coefficient())
+	}
+}
+
+func TestSetTypeAfterSolve(t *testing.T) {
+	model, err := NewModel("retype", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{2})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		typ      VariableType
+		value    float64
+		reported VariableType
+	}{
+		{ContinuousVariable, 1.5, ContinuousVariable},
+		{IntegerVariable, 1, IntegerVariable},
+		{ContinuousVariable, 1.5, ContinuousVariable},
+		{BinaryVariable, 1, BinaryVariable},
+		// the binary bounds are kept
+		{ContinuousVariable, 1, ContinuousVariable},
+		{IntegerVariable, 1, BinaryVariable},
+	} {
+		x.SetType(tc.typ)
+		assert.Equal(t, tc.reported, x.Type())
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, tc.value, res.Value(x), delta, "type %d", tc.typ)
+	}
+}
+
+func TestRemoveAllVariables(t *testing.T) {
+	model, err := knapsackModel(5)
+	require.NoError(t, err)
+
+	for _, v := range model.Variables() {
+		require.NoError(t, model.RemoveVariable(v))
+	}
+	assert.Equal(t, 0, model.VariableCount())
+	assert.Empty(t, model.Variables())
+}
+
+func TestRemoveVariableAndConstraint(t *testing.T) {
+	model, err := NewModel("remove", Maximize, WithDuplicateNames(RejectDuplicateNames))
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", ContinuousVariable, 3, 0, 10)
+	require.NoError(t, err)
+	c1, err := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y, z}, []float64{1, 1, 1})
+	require.NoError(t, err)
+	c2, err := model.AddConstraint(math.Inf(-1), 1, []*Variable{z}, []float64{1})
+	require.NoError(t, err)
+	require.NoError(t, model.AddObjective("alt", []float64{1, 1}, []*Variable{y, z}))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 9, res.ObjectiveValue(), delta)
+
+	require.NoError(t, model.RemoveVariable(y))
+	assert.Equal(t, []*Variable{x, z}, model.Variables())
+	assert.Equal(t, 1, z.index)
+	vars, _ := c1.Terms()
+	assert.Equal(t, []*Variable{x, z}, vars)
+	assert.Error(t, model.RemoveVariable(y))
+
+	// removed variables don't touch the objective constant
+	y.SetObjectiveCoefficient(100)
+	y.SetBounds(5, 5)
+	y.SetType(IntegerVariable)
+	assert.Equal(t, "", y.Name())
+	assert.Equal(t, 0.0, y.Coefficient())
+	lower, upper := y.Bounds()
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 0.0, upper)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 6, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 1, res.Value(z), delta)
+	assert.Equal(t, 0.0, res.Value(y))
+
+	res, err = model.Solve(WithObjective("alt"))
+	require.NoError(t, err)
+	assert.InDelta(t, 1, res.ObjectiveValue(), delta)
+
+	require.NoError(t, model.RemoveConstraint(c2))
+	assert.Equal(t, []*Constraint{c1}, model.Constraints())
+	assert.Error(t, model.RemoveConstraint(c2))
+
+	// removed constraints don't touch the objective row
+	c2.SetBounds(100, 100)
+	c2.SetName("gone")
+	assert.Equal(t, "", c2.Name())
+	lower, upper = c2.Bounds()
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 0.0, upper)
+	vars, _ = c2.Terms()
+	assert.Nil(t, vars)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 12, res.ObjectiveValue(), delta)
+
+	// the name can be reused
+	_, err = model.AddVariable("y")
+	assert.NoError(t, err)
+}
+
+func TestRemoveVariableConcurrently(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	v := model.Variables()[0]
+	c := model.Constraints()[0]
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	removed := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			varErr := model.RemoveVariable(v)
+			conErr := model.RemoveConstraint(c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if varErr == nil {
+				removed++
+			}
+			if conErr == nil {
+				removed++
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, removed)
+	assert.Len(t, model.Variables(), 19)
+	assert.Empty(t, model.Constraints())
+}
+
+func TestFreeze(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	expected, err := model.Solve()
+	require.NoError(t, err)
+	objective := expected.ObjectiveValue()
+
+	frozen := model.Freeze()
+	vars := frozen.Variables()
+
+	// changes to the original don't affect the snapshot
+	model.Constraints()[0].SetBounds(math.Inf(-1), 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := frozen.Solve()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.InDelta(t, objective, res.ObjectiveValue(), delta)
+
+			total := 0.0
+			for _, v := range vars {
+				total += v.Coefficient() * res.Value(v)
+			}
+			assert.InDelta(t, objective, total, delta)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	model, err := NewModel("undo", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+	c, err := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	objective := res.ObjectiveValue()
+
+	snap := model.Snapshot()
+
+	// modify everything
+	x.SetType(IntegerVariable)
+	x.SetBounds(1, 2)
+	y.SetObjectiveCoefficient(-1)
+	c.SetBounds(3, 3)
+	z, err := model.AddVariable("z")
+	require.NoError(t, err)
+	_, err = model.AddConstraint(0, 1, []*Variable{z, x}, []float64{1, 1})
+	require.NoError(t, err)
+	require.NoError(t, model.SetObjectiveFunction([]float64{5}, []*Variable{z}))
+
+	require.NoError(t, model.Restore(snap))
+	assert.Equal(t, []*Variable{x, y}, model.Variables())
+	assert.Equal(t, []*Constraint{c}, model.Constraints())
+	assert.Equal(t, ContinuousVariable, x.Type())
+	l, u := x.Bounds()
+	assert.Equal(t, []float64{0, 10}, []float64{l, u})
+	assert.Equal(t, 2.0, y.Coefficient())
+	l, u = c.Bounds()
+	assert.True(t, math.IsInf(l, -1))
+	assert.Equal(t, 4.0, u)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, objective, res.ObjectiveValue(), delta)
+
+	// removed items cannot be restored
+	snap = model.Snapshot()
+	require.NoError(t, model.RemoveVariable(y))
+	assert.Error(t, model.Restore(snap))
+}
+
+func TestHistory(t *testing.T) {
+	model, err := NewModel("history", Maximize, WithHistory(2))
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	require.NoError(t, model.AddObjective("neg", []float64{-1}, []*Variable{x}))
+
+	_, err = model.Solve()
+	require.NoError(t, err)
+	_, err = model.SolveAs(Minimize)
+	require.NoError(t, err)
+	_, err = model.Solve(WithObjective("neg"))
+	require.NoError(t, err)
+	_, err = model.Solve(WithObjective("missing"))
+	require.Error(t, err)
+
+	history := model.History()
+	require.Len(t, history, 2)
+
+	assert.True(t, history[0].Maximize)
+	assert.Equal(t, "neg", history[0].Objective)
+	assert.Equal(t, SolutionOptimal, history[0].Status)
+	assert.InDelta(t, 0, history[0].ObjectiveValue, delta)
+	assert.NoError(t, history[0].Err)
+
+	assert.ErrorIs(t, history[1].Err, ErrUnknownObjective{Name: "missing"})
+	assert.False(t, history[1].Start.Before(history[0].Start))
+
+	model, err = NewModel("no history", Maximize)
+	require.NoError(t, err)
+	_, err = model.Solve()
+	require.NoError(t, err)
+	assert.Empty(t, model.History())
+}
+
+func TestObjectiveBreakdown(t *testing.T) {
+	model, err := NewModel("breakdown", Minimize)
+	require.NoError(t, err)
+
+	labor, err := model.AddDefinedVariable("labor", ContinuousVariable, 20, 0, math.Inf(1))
+	require.NoError(t, err)
+	steel, err := model.AddDefinedVariable("steel", ContinuousVariable, 3, 0, math.Inf(1))
+	require.NoError(t, err)
+	wood, err := model.AddDefinedVariable("wood", ContinuousVariable, 1, 0, math.Inf(1))
+	require.NoError(t, err)
+	free, err := model.AddDefinedVariable("free", ContinuousVariable, 0, 0, 1)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(2, math.Inf(1), []*Variable{labor}, []float64{1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(10, math.Inf(1), []*Variable{steel, wood}, []float64{1, 2})
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	breakdown := res.ObjectiveBreakdown()
+	assert.NotContains(t, breakdown, free)
+	assert.InDelta(t, 40, breakdown[labor], delta)
+	assert.InDelta(t, 0, breakdown[steel], delta)
+	assert.InDelta(t, 5, breakdown[wood], delta)
+
+	total := 0.0
+	for _, contribution := range breakdown {
+		total += contribution
+	}
+	assert.InDelta(t, res.ObjectiveValue(), total, delta)
+
+	byCategory := res.ObjectiveBreakdownBy(func(v *Variable) string {
+		if v == labor {
+			return "labor"
+		}
+		return "material"
+	})
+	assert.InDelta(t, 40, byCategory["labor"], delta)
+	assert.InDelta(t, 5, byCategory["material"], delta)
+}
+
+func TestMarginalValue(t *testing.T) {
+	model, err := NewModel("whatif", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 5)
+	require.NoError(t, err)
+	capacity, err := model.AddConstraint(math.Inf(-1), 10, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	limit, err := model.AddConstraint(math.Inf(-1), 8, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+	slack, err := model.AddConstraint(math.Inf(-1), 100, []*Variable{y}, []float64{1})
+	require.NoError(t, err)
+
+	// x = 8, y = 2: objective 28
+	value, err := model.MarginalValue(limit)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, value, delta) // x = 10, y = 0: 30
+
+	value, err = model.MarginalValue(slack)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, value, delta)
+
+	value, err = model.MarginalValue(capacity)
+	require.NoError(t, err)
+	assert.InDelta(t, 6, value, delta) // x = 8, y = 5: 34
+
+	// when minimizing, the optimum x = y = 0 is not restricted
+	value, err = model.MarginalValue(limit, WithDirection(Minimize))
+	require.NoError(t, err)
+	assert.InDelta(t, 0, value, delta)
+
+	l, u := limit.Bounds()
+	assert.True(t, math.IsInf(l, -1))
+	assert.Equal(t, 8.0, u)
+
+	limit.SetBounds(math.Inf(-1), math.Inf(1))
+	value, err = model.MarginalValue(capacity)
+	require.NoError(t, err)
+	assert.True(t, math.IsInf(value, 1))
+}
+
+func TestRankVariables(t *testing.T) {
+	model, err := NewModel("ranking", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", ContinuousVariable, 0, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 12, []*Variable{x, y, z}, []float64{1, 1, 1})
+	require.NoError(t, err)
+
+	// optimum: x = 10, y = 2, z = 0 with objective 32
+	ranking, err := model.RankVariables()
+	require.NoError(t, err)
+	require.Len(t, ranking, 3)
+
+	assert.Equal(t, z, ranking[0].Variable) // z = 10 leaves x = 2
+	assert.InDelta(t, 0, ranking[0].LowerImpact, delta)
+	assert.InDelta(t, 26, ranking[0].UpperImpact, delta)
+
+	assert.Equal(t, x, ranking[1].Variable) // x = 0 leaves y = 10
+	assert.InDelta(t, 10, ranking[1].Value, delta)
+	assert.InDelta(t, 22, ranking[1].LowerImpact, delta)
+	assert.InDelta(t, 0, ranking[1].UpperImpact, delta)
+
+	assert.Equal(t, y, ranking[2].Variable) // y = 10 leaves x = 2
+	assert.InDelta(t, 16, ranking[2].Impact(), delta)
+
+	l, u := x.Bounds()
+	assert.Equal(t, []float64{0, 10}, []float64{l, u})
+}
+
+func TestPresolve(t *testing.T) {
+	model, err := NewModel("presolve", Minimize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", ContinuousVariable, 1, 0, 10)
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+
+	fix, err := model.AddConstraint(3, 3, []*Variable{z}, []float64{1})
+	require.NoError(t, err)
+	demand, err := model.AddConstraint(5, math.Inf(1), []*Variable{x, y, z}, []float64{1, 1, 1})
+	require.NoError(t, err)
+	loose, err := model.AddConstraint(math.Inf(-1), 100, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	scaled, err := model.AddConstraint(4, math.Inf(1), []*Variable{x, y}, []float64{2, 2})
+	require.NoError(t, err)
+
+	presolved, mapping, err := model.Presolve()
+	require.NoError(t, err)
+	assert.Equal(t, 2, presolved.VariableCount())
+	assert.Equal(t, 1, presolved.ConstraintCount())
+	assert.Equal(t, 3, model.VariableCount())
+	assert.Equal(t, 4, model.ConstraintCount())
+
+	assert.Nil(t, mapping.Variable(z))
+	value, ok := mapping.Fixed(z)
+	assert.True(t, ok)
+	assert.InDelta(t, 3, value, delta)
+	assert.InDelta(t, 6, mapping.ObjectiveOffset(), delta)
+	assert.Nil(t, mapping.Constraint(fix))
+	assert.Nil(t, mapping.Constraint(loose))
+	assert.Nil(t, mapping.Constraint(scaled))
+
+	c := mapping.Constraint(demand)
+	require.NotNil(t, c)
+	lower, upper := c.Bounds()
+	assert.InDelta(t, 2, lower, delta)
+	assert.True(t, math.IsInf(upper, 1))
+
+	res, err := presolved.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 8, mapping.ObjectiveValue(res), delta)
+
+	values := mapping.Values(res)
+	assert.InDelta(t, 3, values[z], delta)
+	assert.InDelta(t, 2, values[x]+values[y], delta)
+
+	_, err = model.AddConstraint(14, math.Inf(1), []*Variable{x, z}, []float64{1, 1})
+	require.NoError(t, err)
+	_, _, err = model.Presolve()
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+}
+
+func TestBreakSymmetry(t *testing.T) {
+	model, err := NewModel("symmetry", Maximize)
+	require.NoError(t, err)
+
+	a, err := model.AddDefinedVariable("a", BinaryVariable, 1, 0, 1)
+	require.NoError(t, err)
+	b, err := model.AddDefinedVariable("b", BinaryVariable, 1, 0, 1)
+	require.NoError(t, err)
+	d, err := model.AddDefinedVariable("d", BinaryVariable, 2, 0, 1)
+	require.NoError(t, err)
+	c, err := model.AddDefinedVariable("c", BinaryVariable, 1, 0, 1)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 2, []*Variable{a, b, c, d}, []float64{1, 1, 1, 1})
+	require.NoError(t, err)
+
+	groups, err := model.BreakSymmetry()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []*Variable{a, b, c}, groups[0].Variables)
+	assert.Len(t, groups[0].Constraints, 2)
+	assert.Equal(t, 3, model.ConstraintCount())
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 1, res.Value(a), delta)
+	assert.InDelta(t, 0, res.Value(b), delta)
+	assert.InDelta(t, 0, res.Value(c), delta)
+	assert.InDelta(t, 1, res.Value(d), delta)
+
+	for _, con := range groups[0].Constraints {
+		require.NoError(t, model.RemoveConstraint(con))
+	}
+
+	// the named objective tells a and b apart
+	require.NoError(t, model.AddObjective("alt", []float64{1}, []*Variable{a}))
+	groups, err = model.BreakSymmetry()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []*Variable{b, c}, groups[0].Variables)
+}
+
+func TestIterationStats(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.Solve(WithStallDetection(1000))
+	require.NoError(t, err)
+
+	stats := res.IterationStats()
+	assert.Greater(t, stats.Iterations, int64(0))
+	assert.Greater(t, stats.Nodes, int64(0))
+	assert.False(t, stats.Stalled)
+}
+
+func TestSolverCapabilities(t *testing.T) {
+	caps := SolverCapabilities()
+	assert.True(t, caps.RangedRows)
+	assert.True(t, caps.Duals)
+	assert.False(t, caps.LazyConstraints)
+	assert.False(t, caps.MIPStart)
+}
+
+func TestSolverInfo(t *testing.T) {
+	info := SolverInfo()
+	assert.Equal(t, 5, info.Major)
+	assert.Equal(t, 5, info.Minor)
+	assert.Equal(t, fmt.Sprintf("%d.%d.%d.%d", info.Major, info.Minor, info.Release, info.Build), info.Version)
+	assert.Contains(t, info.String(), "lp_solve "+info.Version)
+}
+
+func TestAddConstraintsCtx(t *testing.T) {
+	model, err := NewModel("ctx", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariableSlice("x", 2500, ContinuousVariable, 0, 10)
+	require.NoError(t, err)
+
+	specs := make([]ConstraintSpec, len(x))
+	for i, v := range x {
+		specs[i] = ConstraintSpec{Name: fmt.Sprintf("cap%d", i), Lower: math.Inf(-1), Upper: 1, Expr: Term(1, v)}
+	}
+
+	cons, err := model.AddConstraintsCtx(context.Background(), specs)
+	require.NoError(t, err)
+	require.Len(t, cons, len(specs))
+	assert.Equal(t, "cap2499", cons[2499].Name())
+	assert.Equal(t, len(specs), model.ConstraintCount())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cons, err = model.AddConstraintsCtx(ctx, specs)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, cons)
+	assert.Equal(t, len(specs), model.ConstraintCount())
+
+	// an invalid spec in the second batch keeps the first one
+	specs[ctxCheckInterval+1].Lower = 2
+	cons, err = model.AddConstraintsCtx(context.Background(), specs)
+	assert.ErrorIs(t, err, ErrInvalidBounds{Lower: 2, Upper: 1})
+	assert.Len(t, cons, ctxCheckInterval)
+	assert.Equal(t, len(specs)+ctxCheckInterval, model.ConstraintCount())
+}
+
+func TestConsume(t *testing.T) {
+	model, err := NewModel("consume", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddVariableSlice("x", 2500, ContinuousVariable, 0, 10)
+	require.NoError(t, err)
+
+	specs := make(chan ConstraintSpec)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(x); i += 4 {
+				specs <- ConstraintSpec{Lower: math.Inf(-1), Upper: 1, Expr: Term(1, x[i])}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(specs)
+	}()
+
+	cons, err := model.Consume(context.Background(), specs)
+	require.NoError(t, err)
+	assert.Len(t, cons, len(x))
+	assert.Equal(t, len(x), model.ConstraintCount())
+
+	require.NoError(t, model.SetObjectiveExpr(x.Sum()))
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, float64(len(x)), res.ObjectiveValue(), delta)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cons, err = model.Consume(ctx, make(chan ConstraintSpec))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, cons)
+
+	// an invalid spec in the second batch keeps the first one
+	buffered := make(chan ConstraintSpec, consumeBatchSize+1)
+	for i := 0; i < consumeBatchSize; i++ {
+		buffered <- ConstraintSpec{Lower: math.Inf(-1), Upper: 1, Expr: Term(1, x[i])}
+	}
+	buffered <- ConstraintSpec{Lower: 2, Upper: 1, Expr: Term(1, x[0])}
+	close(buffered)
+	cons, err = model.Consume(context.Background(), buffered)
+	assert.ErrorIs(t, err, ErrInvalidBounds{Lower: 2, Upper: 1})
+	assert.Len(t, cons, consumeBatchSize)
+}
+
+func TestBinaryFormat(t *testing.T) {
+	model, err := NewModel("binary", Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", IntegerVariable, -2, math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", BinaryVariable, 1, 0, 1)
+	require.NoError(t, err)
+
+	c, err := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	c.SetName("cap")
+	_, err = model.AddConstraint(-1, 2, []*Variable{x, y, z}, []float64{-1, 2, 1})
+	require.NoError(t, err)
+	_, err = model.AddConstraint(0, 0, nil, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, model.WriteBinary(&buf))
+
+	path := filepath.Join(t.TempDir(), "model.bin")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	loaded, err := OpenBinary(path)
+	require.NoError(t, err)
+	assert.Equal(t, model.Canonical(), loaded.Canonical())
+	assert.Equal(t, "cap", loaded.Constraints()[0].Name())
+	assert.Equal(t, "R2", loaded.Constraints()[1].Name())
+
+	_, err = readBinary(buf.Bytes()[:buf.Len()-8])
+	assert.Error(t, err)
+	_, err = readBinary([]byte("not a model"))
+	assert.Error(t, err)
+
+	// the type of x follows the header and the padded model name
+	corrupt := append([]byte(nil), buf.Bytes()...)
+	corrupt[binaryHeaderSize+8] = 9
+	_, err = readBinary(corrupt)
+	assert.EqualError(t, err, "invalid binary model: bad variable type")
+}
+
+func TestDecompress(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte("compressed"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	for input, want := range map[string]string{
+		buf.String(): "compressed",
+		"plain":      "plain",
+		"":           "",
+	} {
+		r, err := Decompress(strings.NewReader(input))
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	}
+
+	model, err := knapsackModel(5)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "model.bin.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gz = gzip.NewWriter(f)
+	require.NoError(t, model.WriteBinary(gz))
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	loaded, err := OpenBinary(path)
+	require.NoError(t, err)
+	assert.Equal(t, model.Canonical(), loaded.Canonical())
+}
+
+func TestResultPersistence(t *testing.T) {
+	model, err := knapsackModel(10)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = res.WriteTo(&buf)
+	require.NoError(t, err)
+	saved := buf.String()
+
+	objective := res.ObjectiveValue()
+	values := make(map[*Variable]float64)
+	for _, v := range model.Variables() {
+		values[v] = res.Value(v)
+	}
+
+	// later solves don't affect the loaded result
+	model.SetDirection(Minimize)
+	_, err = model.Solve()
+	require.NoError(t, err)
+
+	loaded, err := ReadResult(strings.NewReader(saved), model)
+	require.NoError(t, err)
+	assert.Equal(t, SolutionOptimal, loaded.Status())
+	assert.InDelta(t, objective, loaded.ObjectiveValue(), delta)
+	for v, value := range values {
+		assert.InDelta(t, value, loaded.Value(v), delta)
+	}
+
+	other, err := knapsackModel(11)
+	require.NoError(t, err)
+	_, err = ReadResult(strings.NewReader(saved), other)
+	var mismatch ErrDimensionMismatch
+	assert.ErrorAs(t, err, &mismatch)
+}
+
+func TestStats(t *testing.T) {
+	model, err := knapsackModel(20)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	stats := res.Stats()
+	assert.Greater(t, stats.WallTime, time.Duration(0))
+	assert.Equal(t, res.IterationStats().Iterations, stats.Iterations)
+	assert.Equal(t, res.IterationStats().Nodes, stats.Nodes)
+	if runtime.GOOS == "linux" {
+		assert.Greater(t, stats.PeakRSS, int64(0))
+	}
+}
+
+func TestSetCoefficient(t *testing.T) {
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 5, 0, math.Inf(1))
+	demand, _ := model.AddConstraint(10, math.Inf(1), []*Variable{x}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 30, res.ObjectiveValue(), delta)
+
+	coef, err := model.Coefficient(demand, y)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, coef)
+
+	require.NoError(t, model.SetCoefficient(demand, y, 2))
+	coef, err = model.Coefficient(demand, y)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, coef)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 25, res.ObjectiveValue(), delta)
+
+	require.NoError(t, model.SetCoefficient(demand, y, 0))
+	vars, _ := demand.Terms()
+	assert.Equal(t, []*Variable{x}, vars)
+
+	assert.Error(t, model.SetCoefficient(demand, y, math.NaN()))
+
+	other, err := NewModel("other", Minimize)
+	require.NoError(t, err)
+	z, _ := other.AddDefinedVariable("z", ContinuousVariable, 1, 0, 1)
+	assert.Error(t, model.SetCoefficient(demand, z, 1))
+	_, err = model.Coefficient(demand, z)
+	assert.Error(t, err)
+}
+
+func TestScale(t *testing.T) {
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 4)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+	c, _ := model.AddConstraint(10, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+
+	want, err := model.Clone().Solve()
+	require.NoError(t, err)
+
+	require.NoError(t, model.ScaleVariable(x, 1000))
+	require.NoError(t, model.ScaleConstraint(c, 0.001))
+
+	lower, upper := x.Bounds()
+	assert.InDelta(t, 0, lower, delta)
+	assert.InDelta(t, 0.004, upper, delta)
+	vars, coefs := c.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.InDeltaSlice(t, []float64{1, 0.001}, coefs, delta)
+	assert.InDelta(t, 1000, x.Coefficient(), delta)
+
+	for _, m := range []*Model{model, model.Clone()} {
+		res, err := m.Solve()
+		require.NoError(t, err)
+
+		vars := m.Variables()
+		cons := m.Constraints()
+		assert.InDelta(t, want.ObjectiveValue(), res.ObjectiveValue(), delta)
+		assert.InDelta(t, 4, res.Value(vars[0]), delta)
+		assert.InDelta(t, 6, res.Value(vars[1]), delta)
+		assert.InDelta(t, want.DualValue(x), res.DualValue(vars[0]), delta)
+		assert.InDelta(t, want.ShadowPrice(c), res.ShadowPrice(cons[0]), delta)
+		assert.InDelta(t, 0, res.MaxViolation(), delta)
+	}
+
+	// results of solves on copies are read with the original handles
+	res, err := model.Freeze().Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 4, res.Value(x), delta)
+	assert.InDelta(t, want.ShadowPrice(c), res.ShadowPrice(c), delta)
+
+	// factors move with renumbered variables and constraints
+	v, _ := model.AddDefinedVariable("v", ContinuousVariable, 0, 0, 1)
+	w, _ := model.AddDefinedVariable("w", ContinuousVariable, 0, 0, 1)
+	d, _ := model.AddConstraint(0, 1, []*Variable{v}, []float64{1})
+	e, _ := model.AddConstraint(0, 1, []*Variable{w}, []float64{1})
+	require.NoError(t, model.ScaleVariable(w, 2))
+	require.NoError(t, model.ScaleConstraint(e, 3))
+	require.NoError(t, model.RemoveVariable(v))
+	require.NoError(t, model.RemoveConstraint(d))
+	assert.InDelta(t, 2, model.scaling.col(w), delta)
+	assert.InDelta(t, 3, model.scaling.row(e), delta)
+	assert.InDelta(t, 1000, model.scaling.col(x), delta)
+
+	assert.Error(t, model.ScaleVariable(x, 0))
+	assert.Error(t, model.ScaleConstraint(c, math.Inf(1)))
+	b, _ := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+	assert.Error(t, model.ScaleVariable(b, 2))
+}
+
+func TestFixedPoint(t *testing.T) {
+	for s, want := range map[string]int64{"19.99": 1999, "-0.1": -10, ".5": 50, "+3": 300} {
+		amount, err := Cents.Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, amount, s)
+	}
+	for _, s := range []string{"1.234", "--1", "-", "1.2.3", "abc", "99999999999999999"} {
+		_, err := Cents.Parse(s)
+		assert.Error(t, err, s)
+	}
+	assert.Equal(t, "-0.05", Cents.Format(-5))
+	assert.Equal(t, "19.99", Cents.Format(1999))
+	assert.Equal(t, "42", FixedPoint{}.Format(42))
+
+	// 3 items at 19.99 and 7 at 0.10, in cents
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", IntegerVariable, 0, 3, 3)
+	y, _ := model.AddDefinedVariable("y", IntegerVariable, 0, 7, 7)
+
+	var prices []int64
+	for _, s := range []string{"19.99", "0.10"} {
+		price, err := Cents.Parse(s)
+		require.NoError(t, err)
+		prices = append(prices, price)
+	}
+	cost, err := DotAmounts(prices, []*Variable{x, y})
+	require.NoError(t, err)
+	require.NoError(t, model.SetObjectiveExpr(cost))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	total, err := res.ObjectiveAmount()
+	require.NoError(t, err)
+	assert.Equal(t, "60.67", Cents.Format(total))
+
+	_, err = DotAmounts([]int64{1 << 60}, []*Variable{x})
+	assert.Error(t, err)
+	_, err = Amount(math.Inf(1))
+	assert.Error(t, err)
+}
+
+func TestConstrainObjective(t *testing.T) {
+	model, err := knapsackModel(10)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	best := res.ObjectiveValue()
+
+	c, err := model.ConstrainObjective(math.Inf(-1), best-1)
+	require.NoError(t, err)
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, res.ObjectiveValue(), best-1+delta)
+
+	// the constraint keeps the objective at the time it was added
+	for _, v := range model.Variables() {
+		v.SetObjectiveCoefficient(0)
+	}
+	vars, _ := c.Terms()
+	assert.NotEmpty(t, vars)
+
+	c.SetBounds(best+1, math.Inf(1))
+	_, err = model.Solve()
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+}
+
+func TestSolveObjectiveThreshold(t *testing.T) {
+	model, err := knapsackModel(15)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	best := res.ObjectiveValue()
+
+	res, err = model.SolveObjectiveThreshold(best - 10)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, res.ObjectiveValue(), best-10-delta)
+	x := model.Variables()[0]
+	assert.Contains(t, []float64{0, 1}, math.Round(res.Value(x)))
+
+	_, err = model.SolveObjectiveThreshold(best + 1)
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+	assert.Equal(t, 1, model.ConstraintCount())
+
+	res, err = model.BisectObjective(0, 1000, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, best, res.ObjectiveValue(), delta)
+
+	_, err = model.BisectObjective(best+1, 1000, 0.5)
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+
+	// stops once the interval cannot be split
+	res, err = model.BisectObjective(0, 1000, math.SmallestNonzeroFloat64)
+	require.NoError(t, err)
+	assert.InDelta(t, best, res.ObjectiveValue(), delta)
+
+	res, err = model.BisectObjective(0, 1000, 0.5, WithDirection(Minimize))
+	require.NoError(t, err)
+	assert.InDelta(t, 0, res.ObjectiveValue(), delta)
+
+	_, err = model.BisectObjective(0, 1000, 0)
+	assert.Error(t, err)
+	_, err = model.BisectObjective(0, math.Inf(1), 0.5)
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpatest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+)
+
+// FeasibilityTolerance is the absolute violation of bounds, constraints and
+// integrality accepted by AssertFeasible.
+var FeasibilityTolerance = 1e-6
+
+// AssertOptimal asserts that the given result is non-nil and optimal.
+func AssertOptimal(t testing.TB, res *golpa.SolveResult) bool {
+	t.Helper()
+
+	if res == nil {
+		t.Errorf("expected optimal result, got nil")
+		return false
+	}
+
+	if res.Status() != golpa.SolutionOptimal {
+		t.Errorf("expected optimal result, got status %d", res.Status())
+		return false
+	}
+
+	return true
+}
+
+// AssertValueNear asserts that the value of v in the given result is within
+// eps of want.
+func AssertValueNear(t testing.TB, res *golpa.SolveResult, v *golpa.Variable, want, eps float64) bool {
+	t.Helper()
+
+	if res == nil {
+		t.Errorf("expected value of %s to be %g, got nil result", v.Name(), want)
+		return false
+	}
+
+	if got := res.Value(v); math.IsNaN(got) || math.Abs(got-want) > eps {
+		t.Errorf("expected value of %s to be %g (±%g), got %g", v.Name(), want, eps, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertFeasible asserts that the given assignment of values to variables
+// satisfies all bounds, integrality requirements and constraints of the
+// model, up to FeasibilityTolerance. Variables missing from the assignment
+// are assumed to be zero.
+// All violations are reported, not only the first one.
+func AssertFeasible(t testing.TB, model *golpa.Model, assignment map[*golpa.Variable]float64) bool {
+	t.Helper()
+
+	ok := true
+
+	for _, v := range model.Variables() {
+		value := assignment[v]
+
+		lower, upper := v.Bounds()
+		if value < lower-FeasibilityTolerance || value > upper+FeasibilityTolerance {
+			t.Errorf("variable %s = %g violates bounds [%g, %g]", v.Name(), value, lower, upper)
+			ok = false
+		}
+
+		if v.Type() != golpa.ContinuousVariable && math.Abs(value-math.Round(value)) > FeasibilityTolerance {
+			t.Errorf("variable %s = %g is not integral", v.Name(), value)
+			ok = false
+		}
+	}
+
+	for _, c := range model.Constraints() {
+		vars, coefs := c.Terms()
+
+		var activity float64
+		for i, v := range vars {
+			activity += coefs[i] * assignment[v]
+		}
+
+		lower, upper := c.Bounds()
+		if activity < lower-FeasibilityTolerance || activity > upper+FeasibilityTolerance {
+			t.Errorf("constraint %s = %g violates bounds [%g, %g]", c.Name(), activity, lower, upper)
+			ok = false
+		}
+	}
+
+	return ok
+}
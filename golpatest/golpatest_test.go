@@ -0,0 +1,125 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package golpatest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomLP(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		model, err := RandomLP(rand.New(rand.NewSource(seed)), 20, 15, 0.3)
+		require.NoError(t, err)
+
+		assert.Equal(t, 20, model.VariableCount())
+
+		res, err := model.Solve()
+		require.NoError(t, err, "seed %d", seed)
+		assert.Equal(t, golpa.SolutionOptimal, res.Status())
+	}
+}
+
+func TestRandomMIP(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		model, err := RandomMIP(rand.New(rand.NewSource(seed)), 15, 10, 0.3, 0.5)
+		require.NoError(t, err)
+
+		res, err := model.Solve()
+		require.NoError(t, err, "seed %d", seed)
+		assert.Equal(t, golpa.SolutionOptimal, res.Status())
+	}
+}
+
+func TestRandomDeterministic(t *testing.T) {
+	m1, err := RandomLP(rand.New(rand.NewSource(42)), 10, 10, 0.5)
+	require.NoError(t, err)
+	m2, err := RandomLP(rand.New(rand.NewSource(42)), 10, 10, 0.5)
+	require.NoError(t, err)
+
+	lp1, err := m1.ExportLP()
+	require.NoError(t, err)
+	lp2, err := m2.ExportLP()
+	require.NoError(t, err)
+
+	assert.Equal(t, lp1, lp2)
+}
+
+func TestAssertions(t *testing.T) {
+	model, err := golpa.NewModel("test", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", golpa.ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", golpa.IntegerVariable, 1, 0, 10)
+	_, err = model.AddConstraint(math.Inf(-1), 7.5, []*golpa.Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	AssertOptimal(t, res)
+	AssertValueNear(t, res, x, 7.5-res.Value(y), 1e-6)
+	AssertFeasible(t, model, map[*golpa.Variable]float64{x: res.Value(x), y: res.Value(y)})
+
+	assert.False(t, AssertFeasible(&recordingT{TB: t}, model, map[*golpa.Variable]float64{x: 5, y: 5}))
+	assert.False(t, AssertFeasible(&recordingT{TB: t}, model, map[*golpa.Variable]float64{x: 1, y: 0.5}))
+	assert.False(t, AssertOptimal(&recordingT{TB: t}, nil))
+}
+
+// recordingT swallows reported errors, so failing assertions can be tested.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+/* Benchmarks */
+
+func BenchmarkSolveRandomLP(b *testing.B) {
+	model, err := RandomLP(rand.New(rand.NewSource(1)), 500, 300, 0.05)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.Clone().Solve(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSolveRandomMIP(b *testing.B) {
+	model, err := RandomMIP(rand.New(rand.NewSource(1)), 50, 30, 0.2, 0.5)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.Clone().Solve(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package golpatest provides utilities for testing and benchmarking code
+// built on top of golpa.
+package golpatest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/costela/golpa"
+)
+
+// RandomLP generates a random linear programming model with nVars continuous
+// variables and nCons constraints. Each constraint uses each variable with
+// probability density (but always at least one variable).
+//
+// The generated models are always feasible and bounded: all variables have
+// finite bounds and the constraints are built around a randomly chosen point
+// inside those bounds. They therefore always have an optimal solution.
+//
+// Passing the same rng state always produces the same model.
+func RandomLP(rng *rand.Rand, nVars, nCons int, density float64) (*golpa.Model, error) {
+	return random(rng, "random LP", nVars, nCons, density, 0)
+}
+
+// RandomMIP generates a random mixed-integer model like RandomLP, but with
+// each variable being integer with probability integrality.
+//
+// Like with RandomLP, the generated models are always feasible and bounded:
+// the point used to build the constraints is integral in all integer
+// variables.
+func RandomMIP(rng *rand.Rand, nVars, nCons int, density, integrality float64) (*golpa.Model, error) {
+	return random(rng, "random MIP", nVars, nCons, density, integrality)
+}
+
+func random(rng *rand.Rand, name string, nVars, nCons int, density, integrality float64) (*golpa.Model, error) {
+	if nVars < 1 {
+		return nil, fmt.Errorf("need at least one variable, got %d", nVars)
+	}
+
+	dir := golpa.Minimize
+	if rng.Intn(2) == 0 {
+		dir = golpa.Maximize
+	}
+
+	model, err := golpa.NewModel(name, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]*golpa.Variable, nVars)
+	point := make([]float64, nVars)
+
+	for i := range vars {
+		lower := float64(rng.Intn(10))
+		upper := lower + float64(1+rng.Intn(20))
+		coef := math.Round((rng.Float64()*20-10)*100) / 100
+
+		varType := golpa.ContinuousVariable
+		point[i] = lower + rng.Float64()*(upper-lower)
+		if rng.Float64() < integrality {
+			varType = golpa.IntegerVariable
+			point[i] = math.Round(point[i])
+		}
+
+		vars[i], err = model.AddDefinedVariable(fmt.Sprintf("x%d", i), varType, coef, lower, upper)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < nCons; i++ {
+		var (
+			rowVars  []*golpa.Variable
+			rowCoefs []float64
+			activity float64
+		)
+
+		for j, v := range vars {
+			if rng.Float64() >= density {
+				continue
+			}
+
+			coef := float64(rng.Intn(19) - 9)
+			if coef == 0 {
+				coef = 1
+			}
+
+			rowVars = append(rowVars, v)
+			rowCoefs = append(rowCoefs, coef)
+			activity += coef * point[j]
+		}
+
+		if len(rowVars) == 0 {
+			j := rng.Intn(nVars)
+			rowVars = append(rowVars, vars[j])
+			rowCoefs = append(rowCoefs, 1)
+			activity = point[j]
+		}
+
+		lower, upper := math.Inf(-1), math.Inf(1)
+		switch rng.Intn(10) {
+		case 0:
+			lower, upper = activity, activity
+		case 1, 2:
+			lower = activity - rng.Float64()*10
+			upper = activity + rng.Float64()*10
+		case 3, 4, 5:
+			lower = activity - rng.Float64()*10
+		default:
+			upper = activity + rng.Float64()*10
+		}
+
+		if _, err := model.AddConstraint(lower, upper, rowVars, rowCoefs); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"context"
+	"errors"
+)
+
+// SolveHandle controls a solve running in the background, started with
+// SolveAsync.
+type SolveHandle struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	res    *SolveResult
+	err    error
+}
+
+// SolveAsync starts solving the model in a new goroutine and returns a
+// handle to wait for or abort the solve.
+func (model *Model) SolveAsync(opts ...SolveOption) *SolveHandle {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &SolveHandle{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(h.done)
+		defer cancel()
+
+		h.res, h.err = model.SolveWithContext(ctx, opts...)
+		if errors.Is(h.err, context.Canceled) {
+			h.err = ErrUserAbort
+		}
+	}()
+
+	return h
+}
+
+// Abort stops the solve. lp_solve checks for aborts during each simplex
+// iteration and branch-and-bound node, so the solve returns promptly, with
+// ErrUserAbort or, if some solution was already found, a result with status
+// SolutionSuboptimal. Aborting a finished solve has no effect.
+func (h *SolveHandle) Abort() {
+	h.cancel()
+}
+
+// Done returns a channel which is closed once the solve is finished.
+func (h *SolveHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait waits for the solve to finish and returns its outcome, like Solve.
+func (h *SolveHandle) Wait() (*SolveResult, error) {
+	<-h.done
+	return h.res, h.err
+}
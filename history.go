@@ -0,0 +1,95 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import "time"
+
+// SolveRecord describes a past solve of a model.
+type SolveRecord struct {
+	Start    time.Time
+	Duration time.Duration
+
+	// Maximize reports the direction the model was optimized in.
+	Maximize bool
+	// Objective is the name of the objective optimized, if one added with
+	// AddObjective was selected.
+	Objective string
+
+	Status         SolveStatus // only valid if Err is nil
+	Err            error
+	ObjectiveValue float64 // only valid if Err is nil
+	Timings        Timings
+}
+
+// WithHistory makes the model record its latest n solves, which can be
+// retrieved with History.
+func WithHistory(n int) Option {
+	return func(m *Model) error {
+		m.historyLimit = n
+
+		return nil
+	}
+}
+
+// History returns the records of the model's latest solves, oldest first,
+// if enabled with WithHistory.
+func (model *Model) History() []SolveRecord {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	return append([]SolveRecord(nil), model.history...)
+}
+
+// recordSolve adds a solve to the model's history, if enabled.
+// The caller must hold the model's write lock.
+func (model *Model) recordSolve(start time.Time, o *solveOptions, res *SolveResult, err error) {
+	if model.historyLimit <= 0 {
+		return
+	}
+
+	rec := SolveRecord{
+		Start:     start,
+		Duration:  time.Since(start),
+		Maximize:  C.is_maxim(model.prob) == C.TRUE,
+		Objective: o.objective,
+		Err:       err,
+	}
+	if o.direction != nil {
+		rec.Maximize = *o.direction == Maximize
+	}
+	if res != nil {
+		rec.Status = res.status
+		rec.Timings = res.timings
+		if err == nil {
+			rec.ObjectiveValue = float64(C.get_objective(model.prob))
+			if res.objective != nil {
+				rec.ObjectiveValue = *res.objective
+			}
+		}
+	}
+
+	if len(model.history) == model.historyLimit {
+		copy(model.history, model.history[1:])
+		model.history = model.history[:len(model.history)-1]
+	}
+	model.history = append(model.history, rec)
+}
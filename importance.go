@@ -0,0 +1,122 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// VariableImportance describes how much the optimal objective value depends
+// on a variable, as reported by RankVariables.
+type VariableImportance struct {
+	Variable *Variable
+	// Value is the variable's value in the optimal solution.
+	Value float64
+	// ReducedCost is the variable's reduced cost in the optimal solution.
+	ReducedCost float64
+	// LowerImpact and UpperImpact are how much worse the optimal objective
+	// value gets when the variable is fixed to its lower or upper bound,
+	// respectively. They are +Inf if that makes the model infeasible, and
+	// NaN for infinite bounds.
+	LowerImpact, UpperImpact float64
+}
+
+// Impact returns the larger of LowerImpact and UpperImpact, ignoring NaNs.
+func (vi VariableImportance) Impact() float64 {
+	switch {
+	case math.IsNaN(vi.LowerImpact):
+		return vi.UpperImpact
+	case math.IsNaN(vi.UpperImpact):
+		return vi.LowerImpact
+	default:
+		return math.Max(vi.LowerImpact, vi.UpperImpact)
+	}
+}
+
+// RankVariables analyzes the sensitivity of the optimal objective value to
+// each variable and returns the variables ordered by decreasing Impact.
+// Variables at the end of the list, which can be fixed to a bound without
+// worsening the objective, are candidates for simplifying the model.
+//
+// The analysis solves a copy of the model twice per variable, so it is
+// only suited for small and medium-sized models. The model itself is not
+// modified.
+func (model *Model) RankVariables() ([]VariableImportance, error) {
+	orig := model.Variables()
+	work := model.Clone()
+	vars := work.Variables()
+
+	res, err := work.Solve()
+	if err != nil {
+		return nil, err
+	}
+	base := res.ObjectiveValue()
+	sign := 1.0
+	if work.Direction() == Maximize {
+		sign = -1
+	}
+
+	ranking := make([]VariableImportance, len(vars))
+	for i, v := range vars {
+		ranking[i] = VariableImportance{
+			Variable:    orig[i],
+			Value:       res.Value(v),
+			ReducedCost: res.DualValue(v),
+		}
+	}
+
+	// impact of fixing v to value, compared to the base objective
+	impact := func(v *Variable, value float64) (float64, error) {
+		if math.IsInf(value, 0) {
+			return math.NaN(), nil
+		}
+
+		lower, upper := v.Bounds()
+		v.SetBounds(value, value)
+		defer v.SetBounds(lower, upper)
+
+		res, err := work.Solve()
+		switch {
+		case errors.Is(err, ErrModelInfeasible):
+			return math.Inf(1), nil
+		case errors.Is(err, ErrModelUnbounded):
+			return math.Inf(-1), nil
+		case err != nil:
+			return 0, err
+		}
+		return sign * (res.ObjectiveValue() - base), nil
+	}
+
+	for i, v := range vars {
+		lower, upper := v.Bounds()
+		if ranking[i].LowerImpact, err = impact(v, lower); err != nil {
+			return nil, err
+		}
+		if ranking[i].UpperImpact, err = impact(v, upper); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(ranking, func(a, b int) bool {
+		return ranking[a].Impact() > ranking[b].Impact()
+	})
+
+	return ranking, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Info describes the lp_solve library the package is linked against.
+type Info struct {
+	// Version is lp_solve's version, as major.minor.release.build.
+	Version                      string
+	Major, Minor, Release, Build int
+	// CFLAGS and LDFLAGS are the cgo flags the package was built with.
+	CFLAGS, LDFLAGS string
+	// Static reports whether lp_solve was linked statically.
+	Static bool
+	// GoVersion is the Go version the package was built with.
+	GoVersion string
+}
+
+// String returns a one-line summary of the information, e.g. for bug
+// reports.
+func (info Info) String() string {
+	linkage := "dynamic"
+	if info.Static {
+		linkage = "static"
+	}
+	return fmt.Sprintf("lp_solve %s (%s, CFLAGS=%q, LDFLAGS=%q, %s)", info.Version, linkage, info.CFLAGS, info.LDFLAGS, info.GoVersion)
+}
+
+// SolverInfo returns information about the lp_solve library in use.
+func SolverInfo() Info {
+	var major, minor, release, build C.int
+	C.lp_solve_version(&major, &minor, &release, &build)
+
+	return Info{
+		Version:   fmt.Sprintf("%d.%d.%d.%d", major, minor, release, build),
+		Major:     int(major),
+		Minor:     int(minor),
+		Release:   int(release),
+		Build:     int(build),
+		CFLAGS:    cgoCFLAGS,
+		LDFLAGS:   cgoLDFLAGS,
+		Static:    staticLink,
+		GoVersion: runtime.Version(),
+	}
+}
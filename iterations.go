@@ -0,0 +1,115 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// stallTolerance is the relative objective change below which simplex
+// iterations are considered to make no progress.
+const stallTolerance = 1e-9
+
+// stallPerturbation are the anti-degeneracy measures enabled when retrying
+// a stalled solve.
+const stallPerturbation = C.ANTIDEGEN_RHSPERTURB | C.ANTIDEGEN_BOUNDFLIP | C.ANTIDEGEN_DYNAMIC
+
+// IterationStats holds the solver's iteration counts for a solve.
+type IterationStats struct {
+	// Iterations is the number of simplex iterations, including those
+	// performed during branch-and-bound.
+	Iterations int64
+	// Nodes is the number of branch-and-bound nodes explored.
+	Nodes int64
+	// MaxDepth is the deepest branch-and-bound level reached.
+	MaxDepth int
+	// Stalled reports whether the solve stalled and was retried with
+	// perturbation (see WithStallDetection).
+	Stalled bool
+}
+
+// IterationStats returns the solver's iteration counts for the solve which
+// produced this result.
+func (res SolveResult) IterationStats() IterationStats {
+	return res.iterations
+}
+
+// iterationStats reads the iteration counts of the last solve.
+// The caller must hold the model's lock.
+func (model *Model) iterationStats() IterationStats {
+	return IterationStats{
+		Iterations: int64(C.get_total_iter(model.prob)),
+		Nodes:      int64(C.get_total_nodes(model.prob)),
+		MaxDepth:   int(C.get_max_level(model.prob)),
+	}
+}
+
+// abortMonitor decides whether a running solve should be aborted, either
+// because its context is done or because it stalled.
+type abortMonitor struct {
+	ctx context.Context
+
+	// stallWindow is the number of iterations without objective change
+	// after which the solve is considered stalled, or 0 to disable
+	// detection.
+	stallWindow int64
+	lastIter    int64
+	lastObj     float64
+	stalled     bool
+}
+
+// abort is called periodically by the solver.
+func (m *abortMonitor) abort(prob *C.lprec) bool {
+	if m.ctx != nil && m.ctx.Err() != nil {
+		return true
+	}
+
+	if m.stallWindow > 0 {
+		iter := int64(C.get_total_iter(prob))
+		obj := float64(C.get_working_objective(prob))
+		if !(math.Abs(obj-m.lastObj) <= stallTolerance*math.Max(1, math.Abs(obj))) {
+			m.lastIter, m.lastObj = iter, obj
+		} else if iter-m.lastIter >= m.stallWindow {
+			m.stalled = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryStalled solves the model again with perturbation enabled, after the
+// previous solve was aborted because it stalled.
+// The caller must hold the model's write lock.
+func (model *Model) retryStalled(m *abortMonitor, solve func() C.int) C.int {
+	model.logger.Print(fmt.Sprintf("simplex made no progress in %d iterations, retrying with perturbation", m.stallWindow))
+
+	m.stallWindow = 0
+
+	prev := C.get_anti_degen(model.prob)
+	C.set_anti_degen(model.prob, prev|stallPerturbation)
+	defer C.set_anti_degen(model.prob, prev)
+
+	return solve()
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddFixedCharge links a non-negative variable x to a binary variable y, so
+// that x can only be positive if y is 1, by adding the constraint
+// x <= maxX * y. This is the usual way of modeling setup costs: put the
+// fixed cost on y and the variable cost on x.
+//
+// If maxX is not positive or infinite, the upper bound of x is used instead,
+// which must then be finite. Tighter values of maxX give stronger
+// relaxations.
+func (model *Model) AddFixedCharge(x, y *Variable, maxX float64) (*Constraint, error) {
+	if x.model != model || y.model != model {
+		return nil, fmt.Errorf("variable does not belong to model")
+	}
+	if y.Type() != BinaryVariable {
+		return nil, fmt.Errorf("fixed charge indicator %q is not binary", y.Name())
+	}
+
+	lower, upper := x.Bounds()
+	if lower < 0 {
+		return nil, fmt.Errorf("fixed charge variable %q may be negative", x.Name())
+	}
+	if maxX <= 0 || math.IsInf(maxX, 1) {
+		maxX = upper
+	}
+	if math.IsInf(maxX, 1) {
+		return nil, fmt.Errorf("no finite upper bound for fixed charge variable %q", x.Name())
+	}
+
+	return model.AddConstraint(math.Inf(-1), 0, []*Variable{x, y}, []float64{1, -maxX})
+}
+
+// AddBinaryProduct adds a variable equal to the product of the binary
+// variables a and b, i.e. their logical AND, along with the constraints
+// z <= a, z <= b and z >= a + b - 1.
+func (model *Model) AddBinaryProduct(a, b *Variable) (*Variable, error) {
+	for _, v := range []*Variable{a, b} {
+		if v.model != model {
+			return nil, fmt.Errorf("variable does not belong to model")
+		}
+		if v.Type() != BinaryVariable {
+			return nil, fmt.Errorf("product factor %q is not binary", v.Name())
+		}
+	}
+
+	// integrality of z follows from the constraints
+	z, err := model.AddDefinedVariable("", ContinuousVariable, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range []*Variable{a, b} {
+		if _, err := model.AddConstraint(math.Inf(-1), 0, []*Variable{z, v}, []float64{1, -1}); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := model.AddConstraint(-1, math.Inf(1), []*Variable{z, a, b}, []float64{1, -1, -1}); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// AddProduct adds a variable equal to the product of the binary variable b
+// and the continuous variable x, which must have finite bounds L and U. The
+// product is enforced by the McCormick constraints
+//
+//	L*b <= z <= U*b
+//	x - U*(1-b) <= z <= x - L*(1-b)
+//
+// which are exact when b is binary.
+func (model *Model) AddProduct(b, x *Variable) (*Variable, error) {
+	if b.model != model || x.model != model {
+		return nil, fmt.Errorf("variable does not belong to model")
+	}
+	if b.Type() != BinaryVariable {
+		return nil, fmt.Errorf("product factor %q is not binary", b.Name())
+	}
+
+	lower, upper := x.Bounds()
+	if math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+		return nil, fmt.Errorf("product factor %q has infinite bounds", x.Name())
+	}
+
+	z, err := model.AddDefinedVariable("", ContinuousVariable, 0, math.Min(lower, 0), math.Max(upper, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, con := range []struct {
+		lower, upper float64
+		vars         []*Variable
+		coefs        []float64
+	}{
+		{0, math.Inf(1), []*Variable{z, b}, []float64{1, -lower}},
+		{math.Inf(-1), 0, []*Variable{z, b}, []float64{1, -upper}},
+		{-upper, math.Inf(1), []*Variable{z, x, b}, []float64{1, -1, -upper}},
+		{math.Inf(-1), -lower, []*Variable{z, x, b}, []float64{1, -1, -lower}},
+	} {
+		if _, err := model.AddConstraint(con.lower, con.upper, con.vars, con.coefs); err != nil {
+			return nil, err
+		}
+	}
+
+	return z, nil
+}
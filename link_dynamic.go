@@ -0,0 +1,35 @@
+//go:build !golpa_static && !windows
+// +build !golpa_static,!windows
+
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #cgo darwin CFLAGS: -I/usr/local/include
+// #cgo darwin LDFLAGS: -L/usr/local/lib
+import "C"
+
+// cgoCFLAGS and cgoLDFLAGS mirror the cgo directives above, as reported by
+// SolverInfo.
+const (
+	cgoCFLAGS  = "-I/usr/include/lpsolve/"
+	cgoLDFLAGS = "-llpsolve55 -lm -ldl -lcolamd"
+	staticLink = false
+)
@@ -0,0 +1,37 @@
+//go:build golpa_static && !windows
+// +build golpa_static,!windows
+
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// Building with the golpa_static tag links the static lp_solve library built
+// into third_party/lpsolve by scripts/build-lpsolve.sh, so the resulting
+// binaries do not depend on liblpsolve55.so.
+
+// #cgo CFLAGS: -I${SRCDIR}/third_party/lpsolve/include
+// #cgo LDFLAGS: ${SRCDIR}/third_party/lpsolve/lib/liblpsolve55.a -lm -ldl
+import "C"
+
+// cgoCFLAGS and cgoLDFLAGS mirror the cgo directives above, as reported by
+// SolverInfo.
+const (
+	cgoCFLAGS  = "-I${SRCDIR}/third_party/lpsolve/include"
+	cgoLDFLAGS = "${SRCDIR}/third_party/lpsolve/lib/liblpsolve55.a -lm -ldl"
+	staticLink = true
+)
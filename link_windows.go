@@ -0,0 +1,41 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// On Windows, lp_solve's 64-bit development package (lp_solve_5.5_dev_win64)
+// is expected in third_party/lpsolve: its headers in include and
+// lpsolve55.dll in lib, which MinGW links against directly. The DLL must
+// also be next to the executable or in the PATH at runtime.
+//
+// Only windows/amd64 is supported, since the 32-bit DLL expects callbacks
+// using the stdcall convention.
+
+// #cgo CFLAGS: -I${SRCDIR}/third_party/lpsolve/include
+// #cgo LDFLAGS: -L${SRCDIR}/third_party/lpsolve/lib -llpsolve55
+import "C"
+
+// cgoCFLAGS and cgoLDFLAGS mirror the cgo directives above, as reported by
+// SolverInfo.
+const (
+	cgoCFLAGS  = "-I${SRCDIR}/third_party/lpsolve/include"
+	cgoLDFLAGS = "-L${SRCDIR}/third_party/lpsolve/lib -llpsolve55"
+	staticLink = false
+)
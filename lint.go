@@ -0,0 +1,102 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultMaxCoefficientRatio is a sensible ratio between the largest and
+// smallest coefficient magnitudes of a single constraint, above which the
+// underlying solver may silently produce wrong results.
+const DefaultMaxCoefficientRatio = 1e9
+
+// CoefficientRangeWarning reports a constraint with badly scaled
+// coefficients, as returned by CheckCoefficientRanges.
+type CoefficientRangeWarning struct {
+	// Constraint is the offending constraint, or nil for the objective
+	// function.
+	Constraint *Constraint
+	// Min and Max are the smallest and largest absolute values of the
+	// non-zero coefficients.
+	Min, Max float64
+	// SuggestedScale is a factor which, applied to the constraint's
+	// coefficients and bounds, centers their magnitudes around 1.
+	SuggestedScale float64
+}
+
+// Ratio returns the ratio between the largest and smallest coefficients.
+func (w CoefficientRangeWarning) Ratio() float64 {
+	return w.Max / w.Min
+}
+
+func (w CoefficientRangeWarning) String() string {
+	name := "objective function"
+	if w.Constraint != nil {
+		name = "constraint " + w.Constraint.Name()
+	}
+
+	return fmt.Sprintf("%s: coefficients span %g to %g (ratio %g); consider rescaling by %g",
+		name, w.Min, w.Max, w.Ratio(), w.SuggestedScale)
+}
+
+// CheckCoefficientRanges returns a warning for each constraint (and the
+// objective function) whose coefficient magnitudes span more than maxRatio.
+// Such constraints are prone to numerical trouble, so their variables or
+// the constraints themselves should be rescaled. DefaultMaxCoefficientRatio
+// is a reasonable value for maxRatio.
+func (model *Model) CheckCoefficientRanges(maxRatio float64) []CoefficientRangeWarning {
+	data := model.readData()
+
+	var warnings []CoefficientRangeWarning
+
+	check := func(c *Constraint, coefs []float64) {
+		min, max := math.Inf(1), 0.0
+		for _, coef := range coefs {
+			if abs := math.Abs(coef); abs != 0 {
+				min = math.Min(min, abs)
+				max = math.Max(max, abs)
+			}
+		}
+
+		if max == 0 || max/min <= maxRatio {
+			return
+		}
+
+		warnings = append(warnings, CoefficientRangeWarning{
+			Constraint:     c,
+			Min:            min,
+			Max:            max,
+			SuggestedScale: 1 / math.Sqrt(min*max),
+		})
+	}
+
+	obj := make([]float64, len(data.vars))
+	for i, v := range data.vars {
+		obj[i] = v.obj
+	}
+	check(nil, obj)
+
+	cons := model.Constraints()
+	for i, c := range data.cons {
+		check(cons[i], c.coefs)
+	}
+
+	return warnings
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetCoefficient sets the coefficient of the variable in the constraint,
+// adding the variable to the constraint if needed. A value of zero removes
+// it.
+//
+// The model may already have been solved: solving it again starts from the
+// previous solution's basis.
+func (model *Model) SetCoefficient(c *Constraint, v *Variable, value float64) error {
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	return model.setCoefficient(c, v, value)
+}
+
+// setCoefficient implements SetCoefficient, keeping the constraint
+// deduplication up to date. The value is given as it is in the model, i.e.
+// with any scaling factors already applied. The caller must hold the
+// model's write lock.
+func (model *Model) setCoefficient(c *Constraint, v *Variable, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("coefficient is not finite: %g", value)
+	}
+	if err := model.checkEntry(c, v); err != nil {
+		return err
+	}
+
+	var oldKey string
+	if model.dedup != nil {
+		oldKey = model.rowKey(c)
+	}
+
+	C.set_mat(model.prob, C.int(c.index+1), C.int(v.index+1), C.REAL(value))
+
+	if model.dedup != nil {
+		if model.dedup[oldKey] == c {
+			delete(model.dedup, oldKey)
+		}
+		if key := model.rowKey(c); model.dedup[key] == nil {
+			model.dedup[key] = c
+		}
+	}
+
+	return nil
+}
+
+// Coefficient returns the coefficient of the variable in the constraint,
+// which is zero if the variable is not used in it.
+func (model *Model) Coefficient(c *Constraint, v *Variable) (float64, error) {
+	model.syncSpool()
+
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	if err := model.checkEntry(c, v); err != nil {
+		return 0, err
+	}
+
+	return float64(C.get_mat(model.prob, C.int(c.index+1), C.int(v.index+1))), nil
+}
+
+// checkEntry returns an error unless both the constraint and the variable
+// belong to the model. The caller must hold at least the model's read lock.
+func (model *Model) checkEntry(c *Constraint, v *Variable) error {
+	if c.model != model || c.index < 0 {
+		return fmt.Errorf("constraint does not belong to model")
+	}
+	if v.model != model || v.index < 0 {
+		return fmt.Errorf("variable does not belong to model")
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package mof reads and writes golpa models in MathOptFormat, the JSON
+// format (.mof.json) used by JuMP and MathOptInterface.
+//
+// Only the linear and integer subset of the format is supported: affine
+// objectives and constraints, variable bounds, and integrality.
+package mof
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+const (
+	majorVersion = 1
+	minorVersion = 2
+)
+
+type file struct {
+	Name        string       `json:"name,omitempty"`
+	Version     version      `json:"version"`
+	Variables   []variable   `json:"variables"`
+	Objective   objective    `json:"objective"`
+	Constraints []constraint `json:"constraints"`
+}
+
+type version struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+}
+
+type variable struct {
+	Name string `json:"name"`
+}
+
+type objective struct {
+	Sense    string    `json:"sense"`
+	Function *function `json:"function,omitempty"`
+}
+
+type constraint struct {
+	Name     string   `json:"name,omitempty"`
+	Function function `json:"function"`
+	Set      set      `json:"set"`
+}
+
+type term struct {
+	Coefficient float64 `json:"coefficient"`
+	Variable    string  `json:"variable"`
+}
+
+// function is either a ScalarAffineFunction or a Variable.
+type function struct {
+	Type     string  `json:"type"`
+	Terms    []term  `json:"terms"`
+	Constant float64 `json:"constant"`
+	Name     string  `json:"name"`
+}
+
+// MarshalJSON writes only the fields belonging to the function's type.
+func (f function) MarshalJSON() ([]byte, error) {
+	if f.Type == "Variable" {
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		}{f.Type, f.Name})
+	}
+
+	terms := f.Terms
+	if terms == nil {
+		terms = []term{}
+	}
+	return json.Marshal(struct {
+		Type     string  `json:"type"`
+		Terms    []term  `json:"terms"`
+		Constant float64 `json:"constant"`
+	}{f.Type, terms, f.Constant})
+}
+
+type set struct {
+	Type  string   `json:"type"`
+	Lower *float64 `json:"lower,omitempty"`
+	Upper *float64 `json:"upper,omitempty"`
+	Value *float64 `json:"value,omitempty"`
+}
+
+// boundSet returns the set for the given bounds, or false if both are
+// infinite.
+func boundSet(lower, upper float64) (set, bool) {
+	switch {
+	case lower == upper:
+		return set{Type: "EqualTo", Value: &lower}, true
+	case !math.IsInf(lower, -1) && !math.IsInf(upper, 1):
+		return set{Type: "Interval", Lower: &lower, Upper: &upper}, true
+	case !math.IsInf(lower, -1):
+		return set{Type: "GreaterThan", Lower: &lower}, true
+	case !math.IsInf(upper, 1):
+		return set{Type: "LessThan", Upper: &upper}, true
+	default:
+		return set{}, false
+	}
+}
+
+// Write writes the model to w in MathOptFormat. Constraints without finite
+// bounds are left out, since the format can't represent them.
+// Since variables are referenced by name, models with duplicate variable
+// names cannot be written and golpa.ErrDuplicateName is returned for them.
+func Write(w io.Writer, model *golpa.Model) error {
+	f := file{
+		Name:    model.Name(),
+		Version: version{majorVersion, minorVersion},
+	}
+
+	obj := &function{Type: "ScalarAffineFunction"}
+	names := make(map[string]bool)
+	for _, v := range model.Variables() {
+		name := v.Name()
+		if names[name] {
+			return golpa.ErrDuplicateName{Name: name}
+		}
+		names[name] = true
+		f.Variables = append(f.Variables, variable{name})
+
+		if coef := v.Coefficient(); coef != 0 {
+			obj.Terms = append(obj.Terms, term{coef, name})
+		}
+
+		if s, ok := boundSet(v.Bounds()); ok {
+			f.Constraints = append(f.Constraints, constraint{
+				Function: function{Type: "Variable", Name: name},
+				Set:      s,
+			})
+		}
+
+		switch v.Type() {
+		case golpa.IntegerVariable:
+			f.Constraints = append(f.Constraints, constraint{
+				Function: function{Type: "Variable", Name: name},
+				Set:      set{Type: "Integer"},
+			})
+		case golpa.BinaryVariable:
+			f.Constraints = append(f.Constraints, constraint{
+				Function: function{Type: "Variable", Name: name},
+				Set:      set{Type: "ZeroOne"},
+			})
+		}
+	}
+
+	f.Objective = objective{Sense: "min", Function: obj}
+	if model.Direction() == golpa.Maximize {
+		f.Objective.Sense = "max"
+	}
+
+	for _, c := range model.Constraints() {
+		s, ok := boundSet(c.Bounds())
+		if !ok {
+			continue
+		}
+
+		vars, coefs := c.Terms()
+		fn := function{Type: "ScalarAffineFunction"}
+		for i, v := range vars {
+			fn.Terms = append(fn.Terms, term{coefs[i], v.Name()})
+		}
+
+		f.Constraints = append(f.Constraints, constraint{
+			Name:     c.Name(),
+			Function: fn,
+			Set:      s,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Read reads a model in MathOptFormat from r, which may be gzip-compressed.
+// Variables without bound constraints are free. The model is created with the given options, e.g.
+// to apply a duplicate name policy.
+func Read(r io.Reader, opts ...golpa.Option) (*golpa.Model, error) {
+	r, err := golpa.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Version.Major != majorVersion {
+		return nil, fmt.Errorf("unsupported MathOptFormat version %d.%d", f.Version.Major, f.Version.Minor)
+	}
+
+	dir := golpa.Minimize
+	switch f.Objective.Sense {
+	case "min", "feasibility":
+	case "max":
+		dir = golpa.Maximize
+	default:
+		return nil, fmt.Errorf("unknown objective sense %q", f.Objective.Sense)
+	}
+
+	model, err := golpa.NewModel(f.Name, dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	type bounds struct {
+		lower, upper float64
+		typ          golpa.VariableType
+	}
+	vars := make(map[string]*golpa.Variable, len(f.Variables))
+	varBounds := make(map[*golpa.Variable]*bounds, len(f.Variables))
+	for _, fv := range f.Variables {
+		if vars[fv.Name] != nil {
+			return nil, fmt.Errorf("duplicate variable %q", fv.Name)
+		}
+		v, err := model.AddDefinedVariable(fv.Name, golpa.ContinuousVariable, 0, math.Inf(-1), math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		vars[fv.Name] = v
+		varBounds[v] = &bounds{math.Inf(-1), math.Inf(1), golpa.ContinuousVariable}
+	}
+
+	affine := func(fn function) ([]*golpa.Variable, []float64, error) {
+		if fn.Type != "ScalarAffineFunction" {
+			return nil, nil, fmt.Errorf("unsupported function type %q", fn.Type)
+		}
+		vs := make([]*golpa.Variable, len(fn.Terms))
+		coefs := make([]float64, len(fn.Terms))
+		for i, t := range fn.Terms {
+			if vs[i] = vars[t.Variable]; vs[i] == nil {
+				return nil, nil, fmt.Errorf("unknown variable %q", t.Variable)
+			}
+			coefs[i] = t.Coefficient
+		}
+		return vs, coefs, nil
+	}
+
+	if f.Objective.Function != nil && f.Objective.Sense != "feasibility" {
+		vs, coefs, err := affine(*f.Objective.Function)
+		if err != nil {
+			return nil, fmt.Errorf("objective: %w", err)
+		}
+		if f.Objective.Function.Constant != 0 {
+			return nil, fmt.Errorf("objective: constants are not supported")
+		}
+		for i, v := range vs {
+			v.SetObjectiveCoefficient(v.Coefficient() + coefs[i])
+		}
+	}
+
+	for i, c := range f.Constraints {
+		if c.Function.Type == "Variable" {
+			v := vars[c.Function.Name]
+			if v == nil {
+				return nil, fmt.Errorf("constraint %d: unknown variable %q", i, c.Function.Name)
+			}
+			b := varBounds[v]
+			switch c.Set.Type {
+			case "Integer":
+				b.typ = golpa.IntegerVariable
+			case "ZeroOne":
+				b.typ = golpa.BinaryVariable
+			default:
+				lower, upper, err := setBounds(c.Set)
+				if err != nil {
+					return nil, fmt.Errorf("constraint %d: %w", i, err)
+				}
+				b.lower = math.Max(b.lower, lower)
+				b.upper = math.Min(b.upper, upper)
+			}
+			continue
+		}
+
+		vs, coefs, err := affine(c.Function)
+		if err != nil {
+			return nil, fmt.Errorf("constraint %d: %w", i, err)
+		}
+		lower, upper, err := setBounds(c.Set)
+		if err != nil {
+			return nil, fmt.Errorf("constraint %d: %w", i, err)
+		}
+
+		added, err := model.AddConstraint(lower-c.Function.Constant, upper-c.Function.Constant, vs, coefs)
+		if err != nil {
+			return nil, err
+		}
+		if c.Name != "" {
+			added.SetName(c.Name)
+		}
+	}
+
+	for v, b := range varBounds {
+		if b.typ == golpa.BinaryVariable {
+			b.lower = math.Max(b.lower, 0)
+			b.upper = math.Min(b.upper, 1)
+		}
+		if b.typ != golpa.ContinuousVariable {
+			v.SetType(b.typ)
+		}
+		v.SetBounds(b.lower, b.upper)
+	}
+
+	return model, nil
+}
+
+// setBounds returns the bounds described by a scalar set.
+func setBounds(s set) (lower, upper float64, err error) {
+	lower, upper = math.Inf(-1), math.Inf(1)
+	switch {
+	case s.Type == "LessThan" && s.Upper != nil:
+		upper = *s.Upper
+	case s.Type == "GreaterThan" && s.Lower != nil:
+		lower = *s.Lower
+	case s.Type == "EqualTo" && s.Value != nil:
+		lower, upper = *s.Value, *s.Value
+	case s.Type == "Interval" && s.Lower != nil && s.Upper != nil:
+		lower, upper = *s.Lower, *s.Upper
+	default:
+		return 0, 0, fmt.Errorf("unsupported set %q", s.Type)
+	}
+	return lower, upper, nil
+}
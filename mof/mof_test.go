@@ -0,0 +1,127 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package mof
+
+import (
+	"bytes"
+	"compress/gzip"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestRoundTrip(t *testing.T) {
+	model, err := golpa.NewModel("round trip", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", golpa.ContinuousVariable, 3, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", golpa.IntegerVariable, 2, math.Inf(-1), 5)
+	require.NoError(t, err)
+	z, err := model.AddDefinedVariable("z", golpa.BinaryVariable, -1, 0, 1)
+	require.NoError(t, err)
+
+	c, err := model.AddConstraint(math.Inf(-1), 12, []*golpa.Variable{x, y, z}, []float64{1, 2, 1})
+	require.NoError(t, err)
+	c.SetName("capacity")
+	_, err = model.AddConstraint(1, 1, []*golpa.Variable{x, z}, []float64{1, -1})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, model))
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+
+	assert.True(t, model.Equal(read, delta), model.Diff(read).String())
+}
+
+func TestRead(t *testing.T) {
+	doc := `{
+  "name": "jump",
+  "version": {"major": 1, "minor": 5},
+  "variables": [{"name": "a"}, {"name": "b"}],
+  "objective": {
+    "sense": "min",
+    "function": {"type": "ScalarAffineFunction", "terms": [{"coefficient": 1, "variable": "a"}, {"coefficient": 1, "variable": "b"}], "constant": 0}
+  },
+  "constraints": [
+    {"name": "sum", "function": {"type": "ScalarAffineFunction", "terms": [{"coefficient": 1, "variable": "a"}, {"coefficient": 2, "variable": "b"}], "constant": 1}, "set": {"type": "GreaterThan", "lower": 4}},
+    {"function": {"type": "Variable", "name": "a"}, "set": {"type": "GreaterThan", "lower": 0}},
+    {"function": {"type": "Variable", "name": "b"}, "set": {"type": "Interval", "lower": 0, "upper": 1}},
+    {"function": {"type": "Variable", "name": "b"}, "set": {"type": "Integer"}}
+  ]
+}`
+
+	model, err := Read(strings.NewReader(doc))
+	require.NoError(t, err)
+	assert.Equal(t, "jump", model.Name())
+	assert.Equal(t, golpa.Minimize, model.Direction())
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	// a + 2b >= 3 with b integer in [0, 1]
+	assert.InDelta(t, 2, res.ObjectiveValue(), delta)
+}
+
+func TestReadErrors(t *testing.T) {
+	for name, doc := range map[string]string{
+		"version":  `{"version": {"major": 2, "minor": 0}, "objective": {"sense": "min"}}`,
+		"sense":    `{"version": {"major": 1, "minor": 0}, "objective": {"sense": "up"}}`,
+		"variable": `{"version": {"major": 1, "minor": 0}, "objective": {"sense": "min"}, "constraints": [{"function": {"type": "Variable", "name": "x"}, "set": {"type": "Integer"}}]}`,
+		"function": `{"version": {"major": 1, "minor": 0}, "variables": [{"name": "x"}], "objective": {"sense": "min"}, "constraints": [{"function": {"type": "ScalarQuadraticFunction"}, "set": {"type": "EqualTo", "value": 1}}]}`,
+	} {
+		_, err := Read(strings.NewReader(doc))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestWriteDuplicateNames(t *testing.T) {
+	model, err := golpa.NewModel("duplicates", golpa.Minimize)
+	require.NoError(t, err)
+	_, err = model.AddVariable("x")
+	require.NoError(t, err)
+	_, err = model.AddVariable("x")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, Write(&buf, model), golpa.ErrDuplicateName{Name: "x"})
+}
+
+func TestReadCompressed(t *testing.T) {
+	model, err := golpa.NewModel("compressed", golpa.Minimize)
+	require.NoError(t, err)
+	x, err := model.AddDefinedVariable("x", golpa.ContinuousVariable, 1, 2, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(3, math.Inf(1), []*golpa.Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	require.NoError(t, Write(gz, model))
+	require.NoError(t, gz.Close())
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.True(t, model.Equal(read, delta), model.Diff(read).String())
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import "fmt"
+
+// DuplicateNamePolicy determines how a model handles variables added with
+// the name of an existing variable.
+type DuplicateNamePolicy int
+
+const (
+	// AllowDuplicateNames accepts duplicate names. Exported models may then
+	// be ambiguous.
+	AllowDuplicateNames DuplicateNamePolicy = iota
+	// RejectDuplicateNames makes adding a variable with a duplicate name
+	// fail with ErrDuplicateName.
+	RejectDuplicateNames
+	// UniquifyDuplicateNames appends the suffix "_2", "_3", etc. to
+	// duplicate names.
+	UniquifyDuplicateNames
+)
+
+// WithDuplicateNames sets the model's policy for duplicate variable names,
+// which also applies to automatically generated names and to clones of the
+// model. By default, duplicate names are allowed.
+func WithDuplicateNames(policy DuplicateNamePolicy) Option {
+	return func(m *Model) error {
+		m.namePolicy = policy
+		if policy != AllowDuplicateNames {
+			m.names = make(map[string]bool)
+		}
+
+		return nil
+	}
+}
+
+// claimName returns the name a new variable gets under the model's
+// duplicate name policy, and records it as used.
+// The caller must hold the model's write lock.
+func (model *Model) claimName(name string) (string, error) {
+	if model.names == nil {
+		return name, nil
+	}
+
+	if model.names[name] {
+		switch model.namePolicy {
+		case RejectDuplicateNames:
+			return "", ErrDuplicateName{Name: name}
+		case UniquifyDuplicateNames:
+			base := name
+			for i := 2; model.names[name]; i++ {
+				name = fmt.Sprintf("%s_%d", base, i)
+			}
+		}
+	}
+	model.names[name] = true
+
+	return name, nil
+}
+
+// AddAnonymousVariable adds a variable with an automatically generated
+// unique name of the form "_v123" and an objective coefficient of 0. This is
+// useful for auxiliary variables which are only referred to through the
+// returned value. The generated names are valid in all export formats.
+func (model *Model) AddAnonymousVariable(typ VariableType, low, high float64) (*Variable, error) {
+	return model.addVariable("", true, typ, 0, low, high)
+}
+
+// anonymousName returns the next name for AddAnonymousVariable. Names are
+// numbered by a counter rather than by the number of variables, so they are
+// not repeated after variables are removed.
+// The caller must hold the model's write lock.
+func (model *Model) anonymousName() string {
+	for {
+		name := fmt.Sprintf("_v%d", model.anonymous)
+		model.anonymous++
+		if !model.names[name] {
+			return name
+		}
+	}
+}
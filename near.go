@@ -0,0 +1,116 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// SolveNear solves the model restricted to solutions within the given
+// Hamming distance of a reference solution: at most radius of the binary
+// variables in reference may take a value different from their reference
+// value. This is useful when re-optimizing after changes to the model, to
+// obtain a solution close to a previously published one.
+//
+// All variables in reference must be binary and have values of 0 or 1.
+// Variables not in reference are unrestricted.
+//
+// The distance constraint is relaxed again once the solve is finished, but
+// remains in the model as a free row.
+func (model *Model) SolveNear(reference map[*Variable]float64, radius int, opts ...SolveOption) (*SolveResult, error) {
+	if radius < 0 {
+		return nil, fmt.Errorf("negative radius %d", radius)
+	}
+
+	vars, coefs, ones, err := model.hammingTerms(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := model.AddConstraint(math.Inf(-1), float64(radius-ones), vars, coefs)
+	if err != nil {
+		return nil, err
+	}
+	defer c.SetBounds(math.Inf(-1), math.Inf(1))
+
+	return model.Solve(opts...)
+}
+
+// ExcludeSolution adds a constraint forbidding the values of all binary
+// variables in the given result, so the next solve finds a different
+// solution. Solving and excluding repeatedly enumerates the model's
+// solutions.
+//
+// Only binary variables are considered, so solutions which differ from res
+// merely in other variables are excluded as well. res must be the result of
+// the latest solve of this model.
+func (model *Model) ExcludeSolution(res *SolveResult) (*Constraint, error) {
+	reference := make(map[*Variable]float64)
+	for _, v := range model.Variables() {
+		if v.Type() == BinaryVariable {
+			reference[v] = math.Round(res.Value(v))
+		}
+	}
+	if len(reference) == 0 {
+		return nil, fmt.Errorf("model has no binary variables")
+	}
+
+	vars, coefs, ones, err := model.hammingTerms(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	// the distance to the solution must be at least 1
+	return model.AddConstraint(float64(1-ones), math.Inf(1), vars, coefs)
+}
+
+// hammingTerms returns the terms of the Hamming distance to the given
+// reference values of binary variables, which equals the terms' sum plus
+// the returned number of variables with reference value 1.
+func (model *Model) hammingTerms(reference map[*Variable]float64) (vars []*Variable, coefs []float64, ones int, err error) {
+	vars = make([]*Variable, 0, len(reference))
+	coefs = make([]float64, 0, len(reference))
+	for _, v := range model.Variables() {
+		value, ok := reference[v]
+		if !ok {
+			continue
+		}
+		if v.Type() != BinaryVariable {
+			return nil, nil, 0, fmt.Errorf("reference variable %q is not binary", v.Name())
+		}
+
+		// flipping a variable from its reference value adds 1 to the
+		// distance: x for reference 0, 1 - x for reference 1
+		switch value {
+		case 0:
+			coefs = append(coefs, 1)
+		case 1:
+			coefs = append(coefs, -1)
+			ones++
+		default:
+			return nil, nil, 0, fmt.Errorf("reference value %g of %q is not binary", value, v.Name())
+		}
+		vars = append(vars, v)
+	}
+	if len(vars) != len(reference) {
+		return nil, nil, 0, fmt.Errorf("reference variable does not belong to model")
+	}
+
+	return vars, coefs, ones, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"sort"
+)
+
+// namedObjective is an alternative objective function stored on the model.
+type namedObjective struct {
+	vars  []*Variable
+	coefs []float64
+}
+
+// row returns the objective's coefficients for the first n variables of the
+// model.
+func (obj *namedObjective) row(n int) []float64 {
+	row := make([]float64, n)
+	for i, v := range obj.vars {
+		row[v.index] += obj.coefs[i]
+	}
+	return row
+}
+
+// clone returns a copy of the objective referring to the given variables of
+// a cloned model.
+func (obj *namedObjective) clone(vars []*Variable) *namedObjective {
+	out := &namedObjective{
+		vars:  make([]*Variable, len(obj.vars)),
+		coefs: append([]float64(nil), obj.coefs...),
+	}
+	for i, v := range obj.vars {
+		out.vars[i] = vars[v.index]
+	}
+	return out
+}
+
+// remove drops the terms of the given variable from the objective.
+func (obj *namedObjective) remove(v *Variable) {
+	vars, coefs := obj.vars[:0], obj.coefs[:0]
+	for i, w := range obj.vars {
+		if w != v {
+			vars = append(vars, w)
+			coefs = append(coefs, obj.coefs[i])
+		}
+	}
+	obj.vars, obj.coefs = vars, coefs
+}
+
+// AddObjective stores an alternative objective function under the given
+// name, replacing any previous one with the same name. It can be optimized
+// by passing WithObjective to Solve; the model's own objective function is
+// not affected.
+func (model *Model) AddObjective(name string, coefs []float64, vars []*Variable) error {
+	if len(vars) != len(coefs) {
+		return fmt.Errorf("inconsistent number of variables and coefficients: %d != %d", len(vars), len(coefs))
+	}
+	for _, v := range vars {
+		if v.model != model {
+			return fmt.Errorf("variable does not belong to model")
+		}
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if model.objectives == nil {
+		model.objectives = make(map[string]*namedObjective)
+	}
+	model.objectives[name] = &namedObjective{
+		vars:  append([]*Variable(nil), vars...),
+		coefs: append([]float64(nil), coefs...),
+	}
+
+	return nil
+}
+
+// Objectives returns the names of the objectives added with AddObjective, in
+// lexical order.
+func (model *Model) Objectives() []string {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	names := make([]string, 0, len(model.objectives))
+	for name := range model.objectives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ConstrainObjective adds a constraint requiring the objective function to
+// lie between low and high, e.g. to keep the achievement of one objective
+// while optimizing another, or to ask whether a solution better than some
+// value exists. To leave one side unbounded, pass math.Inf(-1) or
+// math.Inf(1) respectively.
+//
+// The constraint uses the objective coefficients at the time of the call:
+// later changes to the objective function do not affect it, but its bounds
+// can be changed with SetBounds.
+func (model *Model) ConstrainObjective(low, high float64) (*Constraint, error) {
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	vars, coefs := model.rowTerms(0)
+	return model.addConstraint(low, high, vars, coefs)
+}
+
+// objectiveRow returns the current objective coefficient of every
+// variable. The caller must hold at least the model's read lock.
+func (model *Model) objectiveRow() []float64 {
+	row := make([]float64, len(model.vars))
+	for i := range row {
+		row[i] = float64(C.get_mat(model.prob, 0, C.int(i+1)))
+	}
+	return row
+}
+
+// setObjectiveRow sets the objective coefficient of every variable. The
+// caller must hold the model's write lock.
+func (model *Model) setObjectiveRow(row []float64) {
+	for i, coef := range row {
+		C.set_mat(model.prob, 0, C.int(i+1), C.REAL(coef))
+	}
+}
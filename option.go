@@ -1,5 +1,10 @@
 package golpa
 
+import (
+	"context"
+	"time"
+)
+
 type Option func(*Model) error
 
 func WithLogger(logger Logger) Option {
@@ -9,3 +14,101 @@ func WithLogger(logger Logger) Option {
 		return nil
 	}
 }
+
+// WithConstraintSpool makes the model write constraints added via
+// AddConstraint to a temporary file in dir (or the default temporary
+// directory, if dir is empty) instead of loading them into the solver right
+// away. The spooled constraints are loaded when the model is solved, cloned
+// or exported. If writing or loading the spool fails, the model cannot be
+// solved anymore and Solve returns the error.
+// This is useful for generating very large models, which would otherwise
+// need to be kept in memory in their entirety during generation.
+func WithConstraintSpool(dir string) Option {
+	return func(m *Model) error {
+		spool, err := newConstraintSpool(dir)
+		if err != nil {
+			return err
+		}
+
+		m.spool = spool
+
+		return nil
+	}
+}
+
+// WithConstraintDeduplication makes AddConstraint check whether an identical
+// constraint, with the same terms and bounds, was already added to the model.
+// If so, no new constraint is added and the existing one is returned instead.
+// Terms are compared regardless of their order.
+func WithConstraintDeduplication() Option {
+	return func(m *Model) error {
+		m.dedup = make(map[string]*Constraint)
+
+		return nil
+	}
+}
+
+// SolveOption configures a single call to Solve.
+type SolveOption func(*solveOptions)
+
+type solveOptions struct {
+	direction    *direction
+	objective    string
+	breakAtFirst bool
+	rounding     RoundingPolicy
+	intTolerance float64
+	workspace    *Workspace
+	ctx          context.Context
+	softDeadline time.Duration
+	grace        time.Duration
+	stallWindow  int64
+}
+
+// WithDirection optimizes in the given direction instead of the model's own,
+// without changing the model.
+func WithDirection(dir direction) SolveOption {
+	return func(o *solveOptions) {
+		o.direction = &dir
+	}
+}
+
+// WithObjective optimizes the named objective added with AddObjective
+// instead of the model's own, without changing the model.
+func WithObjective(name string) SolveOption {
+	return func(o *solveOptions) {
+		o.objective = name
+	}
+}
+
+// WithRounding sets how the returned result's IntValue converts variable
+// values to integers. Values within tolerance of an integer are always
+// converted to that integer, regardless of the policy.
+func WithRounding(policy RoundingPolicy, tolerance float64) SolveOption {
+	return func(o *solveOptions) {
+		o.rounding = policy
+		o.intTolerance = tolerance
+	}
+}
+
+// WithSoftDeadline stops the search for better solutions after d. If an
+// integer solution was found by then, up to grace is spent polishing it:
+// its integer variables are fixed and the LP over the continuous variables
+// is solved again, and the result has status SolutionSuboptimal. Otherwise,
+// ErrTimeout is returned.
+func WithSoftDeadline(d, grace time.Duration) SolveOption {
+	return func(o *solveOptions) {
+		o.softDeadline = d
+		o.grace = grace
+	}
+}
+
+// WithStallDetection aborts the simplex when the objective value does not
+// change for the given number of iterations, as happens on highly degenerate
+// models, and solves again with perturbation of the right-hand sides and
+// bounds enabled. A warning is logged in that case and the result's
+// IterationStats report the stall.
+func WithStallDetection(iterations int64) SolveOption {
+	return func(o *solveOptions) {
+		o.stallWindow = iterations
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+)
+
+// Param is a named value which can be bound to any number of coefficients
+// and bounds in a model. Changing the parameter's value updates all bound
+// occurrences at once, so a single model can be reused as a template for
+// several scenarios without being rebuilt.
+//
+// Like the setters they stand for, bound parameters take their values in
+// the scaled form of constraints and variables scaled with ScaleConstraint
+// or ScaleVariable.
+//
+// Parameters are not copied by Model.Clone.
+type Param struct {
+	model    *Model
+	name     string
+	value    float64
+	bindings []func(float64)
+}
+
+// NewParam adds a named parameter with an initial value to the model.
+func (model *Model) NewParam(name string, value float64) (*Param, error) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if _, ok := model.params[name]; ok {
+		return nil, fmt.Errorf("parameter %q already exists", name)
+	}
+
+	p := &Param{
+		model: model,
+		name:  name,
+		value: value,
+	}
+
+	if model.params == nil {
+		model.params = make(map[string]*Param)
+	}
+	model.params[name] = p
+
+	return p, nil
+}
+
+// Param returns the parameter with the given name, or nil if there is none.
+func (model *Model) Param(name string) *Param {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	return model.params[name]
+}
+
+// SetParam changes the value of the named parameter and updates all
+// coefficients and bounds bound to it.
+func (model *Model) SetParam(name string, value float64) error {
+	p := model.Param(name)
+	if p == nil {
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	p.Set(value)
+
+	return nil
+}
+
+// Name returns the parameter's name.
+func (p *Param) Name() string {
+	return p.name
+}
+
+// Value returns the parameter's current value.
+func (p *Param) Value() float64 {
+	p.model.mu.RLock()
+	defer p.model.mu.RUnlock()
+
+	return p.value
+}
+
+// Set changes the value of the parameter and updates all coefficients and
+// bounds bound to it.
+func (p *Param) Set(value float64) {
+	p.model.mu.Lock()
+	p.value = value
+	bindings := p.bindings
+	p.model.mu.Unlock()
+
+	for _, apply := range bindings {
+		apply(value)
+	}
+}
+
+// bind registers a binding and immediately applies the current value.
+func (p *Param) bind(apply func(float64)) {
+	p.model.mu.Lock()
+	p.bindings = append(p.bindings, apply)
+	value := p.value
+	p.model.mu.Unlock()
+
+	apply(value)
+}
+
+// BindObjectiveCoefficient binds the parameter to the variable's objective
+// coefficient.
+func (p *Param) BindObjectiveCoefficient(v *Variable) {
+	p.bind(v.SetObjectiveCoefficient)
+}
+
+// BindLowerBound binds the parameter to the variable's lower bound.
+func (p *Param) BindLowerBound(v *Variable) {
+	p.bind(func(value float64) {
+		_, upper := v.Bounds()
+		v.SetBounds(value, upper)
+	})
+}
+
+// BindUpperBound binds the parameter to the variable's upper bound.
+func (p *Param) BindUpperBound(v *Variable) {
+	p.bind(func(value float64) {
+		lower, _ := v.Bounds()
+		v.SetBounds(lower, value)
+	})
+}
+
+// BindConstraintLower binds the parameter to the constraint's lower bound.
+func (p *Param) BindConstraintLower(c *Constraint) {
+	p.bind(func(value float64) {
+		_, upper := c.Bounds()
+		c.SetBounds(value, upper)
+	})
+}
+
+// BindConstraintUpper binds the parameter to the constraint's upper bound.
+func (p *Param) BindConstraintUpper(c *Constraint) {
+	p.bind(func(value float64) {
+		lower, _ := c.Bounds()
+		c.SetBounds(lower, value)
+	})
+}
+
+// BindCoefficient binds the parameter to the coefficient of the variable in
+// the constraint. Values which are not finite, and constraints or variables
+// which do not belong to the parameter's model, are ignored.
+func (p *Param) BindCoefficient(c *Constraint, v *Variable) {
+	model := p.model
+	p.bind(func(value float64) {
+		model.syncSpool()
+
+		model.mu.Lock()
+		defer model.mu.Unlock()
+
+		_ = model.setCoefficient(c, v, value)
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// ParametricPoint is the outcome of solving a model for a single value of a
+// parameter, as returned by the SolveParametric* functions.
+type ParametricPoint struct {
+	// Parameter is the parameter value used for this solution.
+	Parameter float64
+	// Err is the error returned by solving the model, if any. All other
+	// fields except Parameter are only meaningful if Err is nil.
+	Err error
+
+	Status         SolveStatus
+	ObjectiveValue float64
+	// Values holds the values of all variables, in the same order as
+	// returned by Model.Variables.
+	Values []float64
+}
+
+// SolveParametricRHS solves the model once for each of the given values of
+// the constraint's right-hand side and returns the resulting objective
+// values and solutions, in the same order as values.
+// The right-hand side is the constraint's finite bound, or both bounds for
+// equality constraints. Constraints with two different finite bounds are
+// shifted as a whole, keeping the distance between their bounds.
+//
+// Each solve starts from the final basis of the previous one, which is
+// usually much faster than solving from scratch. Errors solving individual
+// points (e.g. infeasibility) are reported in the respective
+// ParametricPoint. The constraint's original bounds are restored before
+// returning.
+func (model *Model) SolveParametricRHS(c *Constraint, values []float64) ([]ParametricPoint, error) {
+	if c.model != model {
+		return nil, fmt.Errorf("constraint does not belong to model")
+	}
+
+	lower, upper := c.Bounds()
+	defer c.SetBounds(lower, upper)
+
+	var setRHS func(float64)
+	switch {
+	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
+		return nil, fmt.Errorf("constraint %s has no right-hand side", c.Name())
+	case math.IsInf(lower, 0):
+		setRHS = func(rhs float64) { c.SetBounds(lower, rhs) }
+	case math.IsInf(upper, 0):
+		setRHS = func(rhs float64) { c.SetBounds(rhs, upper) }
+	default:
+		setRHS = func(rhs float64) { c.SetBounds(rhs-(upper-lower), rhs) }
+	}
+
+	return model.solveParametric(values, setRHS), nil
+}
+
+// SolveParametricObjective solves the model once for each of the given
+// objective coefficients of the variable and returns the resulting objective
+// values and solutions, in the same order as coefValues. Like with
+// SolveParametricRHS, each solve starts from the previous one's final basis
+// and the original coefficient is restored before returning.
+func (model *Model) SolveParametricObjective(v *Variable, coefValues []float64) ([]ParametricPoint, error) {
+	if v.model != model {
+		return nil, fmt.Errorf("variable does not belong to model")
+	}
+
+	coef := v.Coefficient()
+	defer v.SetObjectiveCoefficient(coef)
+
+	return model.solveParametric(coefValues, v.SetObjectiveCoefficient), nil
+}
+
+// solveParametric solves the model once for each of the given values, after
+// applying it with set.
+func (model *Model) solveParametric(values []float64, set func(float64)) []ParametricPoint {
+	points := make([]ParametricPoint, len(values))
+
+	for i, value := range values {
+		set(value)
+
+		points[i].Parameter = value
+
+		res, err := model.Solve()
+		if err != nil {
+			points[i].Err = err
+			continue
+		}
+
+		points[i].Status = res.Status()
+		points[i].ObjectiveValue = res.ObjectiveValue()
+
+		vars := model.Variables()
+		points[i].Values = make([]float64, len(vars))
+		for j, v := range vars {
+			points[i].Values[j] = res.Value(v)
+		}
+	}
+
+	return points
+}
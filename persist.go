@@ -0,0 +1,132 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// statusNames are the names of solve statuses in persisted results.
+var statusNames = map[SolveStatus]string{
+	SolutionOptimal:    "optimal",
+	SolutionSuboptimal: "suboptimal",
+	SolutionInfeasible: "infeasible",
+	SolutionUnbounded:  "unbounded",
+}
+
+// resultFile is the persisted form of a SolveResult.
+type resultFile struct {
+	Model     string        `json:"model"`
+	Status    string        `json:"status"`
+	Objective float64       `json:"objective"`
+	Variables []resultValue `json:"variables"`
+}
+
+type resultValue struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// WriteTo writes the result's status, objective value and variable values
+// to w as JSON, so it can be archived and later loaded with ReadResult.
+func (res SolveResult) WriteTo(w io.Writer) (int64, error) {
+	res.model.mu.RLock()
+	f := resultFile{
+		Model:     res.model.name(),
+		Status:    statusNames[res.status],
+		Variables: make([]resultValue, len(res.model.vars)),
+	}
+	values := res.values
+	if len(values) < len(res.model.vars) {
+		values = make([]float64, len(res.model.vars))
+		if len(values) > 0 {
+			C.get_variables(res.model.prob, (*C.REAL)(unsafe.Pointer(&values[0])))
+		}
+	}
+	for i := range res.model.vars {
+		f.Variables[i] = resultValue{res.model.colName(i + 1), values[i] * res.scaling.col(res.model.vars[i])}
+	}
+	res.model.mu.RUnlock()
+	f.Objective = res.ObjectiveValue()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return 0, err
+	}
+
+	return buf.WriteTo(w)
+}
+
+// ReadResult loads a result written by SolveResult.WriteTo for the given
+// model, which must have the same variables, in the same order, as the
+// model the result was written for. The reader may be gzip-compressed.
+//
+// The loaded result provides the status, objective value and variable
+// values; methods depending on the solver's state, like DualValue and
+// ShadowPrice, refer to the model's latest solve instead.
+func ReadResult(r io.Reader, model *Model) (*SolveResult, error) {
+	r, err := Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var f resultFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+
+	res := &SolveResult{
+		model:        model,
+		intTolerance: DefaultIntegralityTolerance,
+		objective:    &f.Objective,
+	}
+
+	found := false
+	for status, name := range statusNames {
+		if name == f.Status {
+			res.status, found = status, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown result status %q", f.Status)
+	}
+
+	vars := model.Variables()
+	if len(f.Variables) != len(vars) {
+		return nil, ErrDimensionMismatch{Got: len(f.Variables), Want: len(vars)}
+	}
+	res.values = make([]float64, len(vars))
+	for i, v := range vars {
+		if name := v.Name(); f.Variables[i].Name != name {
+			return nil, fmt.Errorf("result variable %d is %q, model has %q", i, f.Variables[i].Name, name)
+		}
+		res.values[i] = f.Variables[i].Value
+	}
+
+	return res, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"math"
+)
+
+// Mapping relates a model returned by Presolve to the original model, so
+// solutions of the presolved model can be translated back.
+type Mapping struct {
+	orig   []*Variable
+	vars   map[*Variable]*Variable
+	fixed  map[*Variable]float64
+	cons   map[*Constraint]*Constraint
+	offset float64
+}
+
+// Variable returns the presolved model's variable corresponding to the given
+// variable of the original model, or nil if it was removed.
+func (m Mapping) Variable(v *Variable) *Variable {
+	return m.vars[v]
+}
+
+// Constraint returns the presolved model's constraint corresponding to the
+// given constraint of the original model, or nil if it was removed.
+func (m Mapping) Constraint(c *Constraint) *Constraint {
+	return m.cons[c]
+}
+
+// Fixed returns the value a variable of the original model was fixed to,
+// and whether it was removed from the presolved model at all.
+func (m Mapping) Fixed(v *Variable) (float64, bool) {
+	value, ok := m.fixed[v]
+	return value, ok
+}
+
+// ObjectiveOffset returns the objective value contributed by the removed
+// variables, which the presolved model does not account for.
+func (m Mapping) ObjectiveOffset() float64 {
+	return m.offset
+}
+
+// ObjectiveValue returns the original model's objective value for a
+// solution of the presolved model.
+func (m Mapping) ObjectiveValue(res *SolveResult) float64 {
+	return res.ObjectiveValue() + m.offset
+}
+
+// Values translates a solution of the presolved model into values for all
+// variables of the original model.
+func (m Mapping) Values(res *SolveResult) map[*Variable]float64 {
+	values := make(map[*Variable]float64, len(m.orig))
+	for _, v := range m.orig {
+		if reduced, ok := m.vars[v]; ok {
+			values[v] = res.Value(reduced)
+		} else {
+			values[v] = m.fixed[v]
+		}
+	}
+	return values
+}
+
+// Presolve returns a reduced copy of the model, along with the mapping
+// between both. The reductions are:
+//
+//   - variable bounds are tightened by bound propagation,
+//   - fixed variables are removed, moving their contributions into the
+//     constraint bounds and the objective offset,
+//   - constraints with a single remaining variable are turned into bounds,
+//   - constraints without remaining variables are removed, and
+//   - redundant constraints, as reported by FindRedundantConstraints, are
+//     removed.
+//
+// The presolved model has the same optimal solutions as the original one,
+// but no named objectives. The original model is not modified. If the
+// reductions prove that the model is infeasible, ErrModelInfeasible is
+// returned.
+func (model *Model) Presolve() (*Model, Mapping, error) {
+	data := model.readData()
+	vars, cons := model.Variables(), model.Constraints()
+
+	lower, upper, err := data.propagateBounds()
+	if err != nil {
+		return nil, Mapping{}, err
+	}
+
+	fixed := func(col int) bool {
+		return lower[col] == upper[col]
+	}
+	tolerance := func(f float64) float64 {
+		return propagationTolerance * math.Max(1, math.Abs(f))
+	}
+
+	// remove empty rows and turn singleton rows into bounds, until no more
+	// variables get fixed
+	removed := make([]bool, len(data.cons))
+	for changed := true; changed; {
+		changed = false
+		for i, c := range data.cons {
+			if removed[i] {
+				continue
+			}
+
+			low, high := c.lower, c.upper
+			var remaining []int
+			for j, col := range c.cols {
+				switch {
+				case fixed(col):
+					low -= c.coefs[j] * lower[col]
+					high -= c.coefs[j] * lower[col]
+				case c.coefs[j] != 0:
+					remaining = append(remaining, j)
+				}
+			}
+
+			switch len(remaining) {
+			case 0:
+				if low > tolerance(low) || high < -tolerance(high) {
+					return nil, Mapping{}, ErrModelInfeasible
+				}
+			case 1:
+				j := remaining[0]
+				col, coef := c.cols[j], c.coefs[j]
+				newLower, newUpper := low/coef, high/coef
+				if coef < 0 {
+					newLower, newUpper = newUpper, newLower
+				}
+				if data.vars[col].typ != ContinuousVariable {
+					newLower = math.Ceil(newLower - propagationTolerance)
+					newUpper = math.Floor(newUpper + propagationTolerance)
+				}
+				lower[col] = math.Max(lower[col], newLower)
+				upper[col] = math.Min(upper[col], newUpper)
+				if lower[col] > upper[col]+tolerance(upper[col]) {
+					return nil, Mapping{}, ErrModelInfeasible
+				}
+				if lower[col] > upper[col] {
+					upper[col] = lower[col]
+				}
+				changed = changed || fixed(col)
+			default:
+				continue
+			}
+			removed[i] = true
+		}
+	}
+
+	m := Mapping{
+		orig:  vars,
+		vars:  make(map[*Variable]*Variable, len(vars)),
+		fixed: make(map[*Variable]float64),
+		cons:  make(map[*Constraint]*Constraint, len(cons)),
+	}
+
+	reduced := &modelData{
+		name:     data.name,
+		maximize: data.maximize,
+	}
+	cols := make([]int, len(data.vars))
+	for i, v := range data.vars {
+		if fixed(i) {
+			cols[i] = -1
+			m.fixed[vars[i]] = lower[i]
+			m.offset += v.obj * lower[i]
+			continue
+		}
+		cols[i] = len(reduced.vars)
+		v.lower, v.upper = lower[i], upper[i]
+		reduced.vars = append(reduced.vars, v)
+	}
+
+	var rows []int // original index of each reduced constraint
+	for i, c := range data.cons {
+		if removed[i] {
+			continue
+		}
+		rc := conData{
+			name:  c.name,
+			lower: c.lower,
+			upper: c.upper,
+		}
+		for j, col := range c.cols {
+			if fixed(col) {
+				rc.lower -= c.coefs[j] * lower[col]
+				rc.upper -= c.coefs[j] * lower[col]
+				continue
+			}
+			rc.cols = append(rc.cols, cols[col])
+			rc.coefs = append(rc.coefs, c.coefs[j])
+		}
+		reduced.cons = append(reduced.cons, rc)
+		rows = append(rows, i)
+	}
+
+	redundant := make(map[int]bool)
+	for _, i := range reduced.redundantConstraints() {
+		redundant[i] = true
+	}
+
+	presolved, err := NewModel(reduced.name, model.Direction())
+	if err != nil {
+		return nil, Mapping{}, err
+	}
+
+	newVars := make([]*Variable, len(reduced.vars))
+	for i, v := range reduced.vars {
+		if newVars[i], err = presolved.AddDefinedVariable(v.name, v.typ, v.obj, v.lower, v.upper); err != nil {
+			return nil, Mapping{}, err
+		}
+	}
+	for i, col := range cols {
+		if col >= 0 {
+			m.vars[vars[i]] = newVars[col]
+		}
+	}
+
+	for i, c := range reduced.cons {
+		if redundant[i] {
+			continue
+		}
+		terms := make([]*Variable, len(c.cols))
+		for j, col := range c.cols {
+			terms[j] = newVars[col]
+		}
+		nc, err := presolved.AddConstraint(c.lower, c.upper, terms, c.coefs)
+		if err != nil {
+			return nil, Mapping{}, err
+		}
+		if c.name != "" {
+			nc.SetName(c.name)
+		}
+		m.cons[cons[rows[i]]] = nc
+	}
+
+	return presolved, m, nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package problems provides builders for common combinatorial optimization
+// problems, returning golpa models along with typed accessors for their
+// solutions.
+package problems
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Assignment is an assignment problem: each agent is assigned to at most one
+// task and each task to at most one agent, minimizing the total cost.
+type Assignment struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	vars [][]*golpa.Variable
+}
+
+// BuildAssignment builds an assignment problem where costs[i][j] is the cost
+// of assigning agent i to task j. A cost of math.Inf(1) forbids the
+// assignment.
+//
+// If there are no more agents than tasks, every agent gets assigned a task;
+// otherwise, every task gets assigned an agent.
+func BuildAssignment(costs [][]float64) (*Assignment, error) {
+	agents := len(costs)
+	tasks := 0
+	if agents > 0 {
+		tasks = len(costs[0])
+	}
+	for i, row := range costs {
+		if len(row) != tasks {
+			return nil, fmt.Errorf("inconsistent number of tasks for agent %d: %d != %d", i, len(row), tasks)
+		}
+	}
+
+	model, err := golpa.NewModel("assignment", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Assignment{
+		Model: model,
+		vars:  make([][]*golpa.Variable, agents),
+	}
+
+	taskVars := make([][]*golpa.Variable, tasks)
+	for i, row := range costs {
+		a.vars[i] = make([]*golpa.Variable, tasks)
+		for j, cost := range row {
+			if math.IsInf(cost, 1) {
+				continue
+			}
+			v, err := model.AddDefinedVariable(fmt.Sprintf("x_%d_%d", i, j), golpa.BinaryVariable, cost, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			a.vars[i][j] = v
+			taskVars[j] = append(taskVars[j], v)
+		}
+	}
+
+	agentLower, taskLower := 1.0, 0.0
+	if agents > tasks {
+		agentLower, taskLower = 0, 1
+	}
+
+	for i := range a.vars {
+		vars := nonNil(a.vars[i])
+		if _, err := model.AddConstraint(agentLower, 1, vars, ones(len(vars))); err != nil {
+			return nil, err
+		}
+	}
+	for j := range taskVars {
+		if _, err := model.AddConstraint(taskLower, 1, taskVars[j], ones(len(taskVars[j]))); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Variable returns the binary variable deciding whether agent i is assigned
+// to task j, or nil if the assignment is forbidden.
+func (a *Assignment) Variable(i, j int) *golpa.Variable {
+	return a.vars[i][j]
+}
+
+// Solve solves the assignment problem.
+func (a *Assignment) Solve() (*AssignmentResult, error) {
+	res, err := a.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]int, len(a.vars))
+	for i, row := range a.vars {
+		assignments[i] = -1
+		for j, v := range row {
+			if v != nil && res.Value(v) > 0.5 {
+				assignments[i] = j
+			}
+		}
+	}
+
+	return &AssignmentResult{
+		SolveResult: res,
+		assignments: assignments,
+	}, nil
+}
+
+// AssignmentResult is the solution of an assignment problem.
+type AssignmentResult struct {
+	*golpa.SolveResult
+
+	assignments []int
+}
+
+// Assignments returns the task assigned to each agent, or -1 for agents
+// without a task.
+func (res *AssignmentResult) Assignments() []int {
+	return res.assignments
+}
+
+func nonNil(vars []*golpa.Variable) []*golpa.Variable {
+	var out []*golpa.Variable
+	for _, v := range vars {
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func ones(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = 1
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package problems
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Subset is a candidate subset of a set cover or set partitioning problem.
+// Elements are indices into the universe.
+type Subset struct {
+	Elements []int
+	Cost     float64
+}
+
+// SetCover is a set cover or set partitioning problem: choose subsets of
+// minimum total cost such that every element of the universe is in at least
+// one (cover) or exactly one (partitioning) chosen subset.
+type SetCover struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	vars []*golpa.Variable
+}
+
+// BuildSetCover builds a set cover problem over the universe of elements 0
+// to universe-1.
+func BuildSetCover(universe int, subsets []Subset) (*SetCover, error) {
+	return buildSetCover("set cover", universe, subsets, math.Inf(1))
+}
+
+// BuildSetPartitioning builds a set partitioning problem over the universe
+// of elements 0 to universe-1.
+func BuildSetPartitioning(universe int, subsets []Subset) (*SetCover, error) {
+	return buildSetCover("set partitioning", universe, subsets, 1)
+}
+
+func buildSetCover(name string, universe int, subsets []Subset, upper float64) (*SetCover, error) {
+	model, err := golpa.NewModel(name, golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SetCover{
+		Model: model,
+		vars:  make([]*golpa.Variable, len(subsets)),
+	}
+
+	covering := make([][]*golpa.Variable, universe)
+	for i, subset := range subsets {
+		if s.vars[i], err = model.AddDefinedVariable(fmt.Sprintf("s_%d", i), golpa.BinaryVariable, subset.Cost, 0, 1); err != nil {
+			return nil, err
+		}
+
+		seen := make(map[int]bool, len(subset.Elements))
+		for _, e := range subset.Elements {
+			if e < 0 || e >= universe {
+				return nil, fmt.Errorf("element of subset %d out of range [0, %d): %d", i, universe, e)
+			}
+			if !seen[e] {
+				seen[e] = true
+				covering[e] = append(covering[e], s.vars[i])
+			}
+		}
+	}
+
+	for e, vars := range covering {
+		if len(vars) == 0 {
+			return nil, fmt.Errorf("element %d is not in any subset", e)
+		}
+		c, err := model.AddConstraint(1, upper, vars, ones(len(vars)))
+		if err != nil {
+			return nil, err
+		}
+		c.SetName(fmt.Sprintf("element_%d", e))
+	}
+
+	return s, nil
+}
+
+// Variable returns the binary variable deciding whether subset i is chosen.
+func (s *SetCover) Variable(i int) *golpa.Variable {
+	return s.vars[i]
+}
+
+// Solve solves the set cover or set partitioning problem.
+func (s *SetCover) Solve() (*SetCoverResult, error) {
+	res, err := s.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	var chosen []int
+	for i, v := range s.vars {
+		if res.Value(v) > 0.5 {
+			chosen = append(chosen, i)
+		}
+	}
+
+	return &SetCoverResult{
+		SolveResult: res,
+		chosen:      chosen,
+	}, nil
+}
+
+// SetCoverResult is the solution of a set cover or set partitioning problem.
+type SetCoverResult struct {
+	*golpa.SolveResult
+
+	chosen []int
+}
+
+// Chosen returns the indices of the chosen subsets, in increasing order.
+func (res *SetCoverResult) Chosen() []int {
+	return res.chosen
+}
@@ -0,0 +1,258 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package problems
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Item is an item of a knapsack problem.
+type Item struct {
+	Weight float64
+	Value  float64
+}
+
+// Knapsack is a 0-1 knapsack problem: select items maximizing their total
+// value without exceeding the capacity.
+type Knapsack struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	vars []*golpa.Variable
+}
+
+// BuildKnapsack builds a 0-1 knapsack problem. Items heavier than the
+// capacity are fixed to zero up front.
+func BuildKnapsack(items []Item, capacity float64) (*Knapsack, error) {
+	if capacity < 0 {
+		return nil, fmt.Errorf("negative capacity: %g", capacity)
+	}
+
+	model, err := golpa.NewModel("knapsack", golpa.Maximize)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Knapsack{
+		Model: model,
+		vars:  make([]*golpa.Variable, len(items)),
+	}
+
+	weights := make([]float64, len(items))
+	for i, item := range items {
+		if item.Weight < 0 {
+			return nil, fmt.Errorf("negative weight for item %d: %g", i, item.Weight)
+		}
+
+		if k.vars[i], err = model.AddDefinedVariable(fmt.Sprintf("x_%d", i), golpa.BinaryVariable, item.Value, 0, 1); err != nil {
+			return nil, err
+		}
+		// binary variables ignore the bounds passed when adding them
+		if item.Weight > capacity {
+			k.vars[i].SetBounds(0, 0)
+		}
+		weights[i] = item.Weight
+	}
+
+	if _, err := model.AddConstraint(math.Inf(-1), capacity, k.vars, weights); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Variable returns the binary variable deciding whether item i is selected.
+func (k *Knapsack) Variable(i int) *golpa.Variable {
+	return k.vars[i]
+}
+
+// Solve solves the knapsack problem.
+func (k *Knapsack) Solve() (*KnapsackResult, error) {
+	res, err := k.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []int
+	for i, v := range k.vars {
+		if res.Value(v) > 0.5 {
+			selected = append(selected, i)
+		}
+	}
+
+	return &KnapsackResult{
+		SolveResult: res,
+		selected:    selected,
+	}, nil
+}
+
+// KnapsackResult is the solution of a knapsack problem.
+type KnapsackResult struct {
+	*golpa.SolveResult
+
+	selected []int
+}
+
+// Selected returns the indices of the selected items, in increasing order.
+func (res *KnapsackResult) Selected() []int {
+	return res.selected
+}
+
+// BinPacking is a bin packing problem: pack items into as few bins of a given
+// size as possible.
+type BinPacking struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	used   []*golpa.Variable
+	assign [][]*golpa.Variable
+}
+
+// BuildBinPacking builds a bin packing problem with the given item sizes and
+// at most maxBins bins (or one bin per item, if maxBins is not positive).
+//
+// Besides the usual assignment and capacity constraints, the formulation
+// links each assignment to its bin's usage variable and breaks the symmetry
+// between bins: bins are used in order and item i may only be packed into
+// bins 0 to i.
+func BuildBinPacking(sizes []float64, binSize float64, maxBins int) (*BinPacking, error) {
+	if maxBins <= 0 || maxBins > len(sizes) {
+		maxBins = len(sizes)
+	}
+
+	total := 0.0
+	for i, size := range sizes {
+		if size < 0 || size > binSize {
+			return nil, fmt.Errorf("size of item %d out of range [0, %g]: %g", i, binSize, size)
+		}
+		total += size
+	}
+
+	model, err := golpa.NewModel("bin packing", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &BinPacking{
+		Model:  model,
+		used:   make([]*golpa.Variable, maxBins),
+		assign: make([][]*golpa.Variable, len(sizes)),
+	}
+
+	for b := range p.used {
+		if p.used[b], err = model.AddDefinedVariable(fmt.Sprintf("y_%d", b), golpa.BinaryVariable, 1, 0, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	binVars := make([][]*golpa.Variable, maxBins)
+	binSizes := make([][]float64, maxBins)
+	for i, size := range sizes {
+		p.assign[i] = make([]*golpa.Variable, maxBins)
+		for b := 0; b < maxBins && b <= i; b++ {
+			v, err := model.AddDefinedVariable(fmt.Sprintf("x_%d_%d", i, b), golpa.BinaryVariable, 0, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			p.assign[i][b] = v
+			binVars[b] = append(binVars[b], v)
+			binSizes[b] = append(binSizes[b], size)
+
+			if _, err := model.AddConstraint(math.Inf(-1), 0, []*golpa.Variable{v, p.used[b]}, []float64{1, -1}); err != nil {
+				return nil, err
+			}
+		}
+
+		vars := nonNil(p.assign[i])
+		if _, err := model.AddConstraint(1, 1, vars, ones(len(vars))); err != nil {
+			return nil, err
+		}
+	}
+
+	for b := range p.used {
+		vars := append(binVars[b], p.used[b])
+		coefs := append(binSizes[b], -binSize)
+		if _, err := model.AddConstraint(math.Inf(-1), 0, vars, coefs); err != nil {
+			return nil, err
+		}
+
+		if b > 0 {
+			if _, err := model.AddConstraint(0, math.Inf(1), []*golpa.Variable{p.used[b-1], p.used[b]}, []float64{1, -1}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if binSize > 0 {
+		if _, err := model.AddConstraint(math.Ceil(total/binSize-1e-9), math.Inf(1), p.used, ones(maxBins)); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Solve solves the bin packing problem.
+func (p *BinPacking) Solve() (*BinPackingResult, error) {
+	res, err := p.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	out := &BinPackingResult{
+		SolveResult: res,
+		assignments: make([]int, len(p.assign)),
+	}
+	for i, row := range p.assign {
+		out.assignments[i] = -1
+		for b, v := range row {
+			if v != nil && res.Value(v) > 0.5 {
+				out.assignments[i] = b
+				for len(out.bins) <= b {
+					out.bins = append(out.bins, nil)
+				}
+				out.bins[b] = append(out.bins[b], i)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// BinPackingResult is the solution of a bin packing problem.
+type BinPackingResult struct {
+	*golpa.SolveResult
+
+	assignments []int
+	bins        [][]int
+}
+
+// Assignments returns the bin each item is packed into.
+func (res *BinPackingResult) Assignments() []int {
+	return res.assignments
+}
+
+// Bins returns the items packed into each used bin.
+func (res *BinPackingResult) Bins() [][]int {
+	return res.bins
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package problems
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestAssignment(t *testing.T) {
+	a, err := BuildAssignment([][]float64{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	})
+	require.NoError(t, err)
+
+	res, err := a.Solve()
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 0, 2}, res.Assignments())
+	assert.InDelta(t, 5, res.ObjectiveValue(), delta)
+}
+
+func TestAssignmentRectangular(t *testing.T) {
+	a, err := BuildAssignment([][]float64{
+		{1, 9},
+		{2, math.Inf(1)},
+		{8, 7},
+	})
+	require.NoError(t, err)
+
+	res, err := a.Solve()
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{0, -1, 1}, res.Assignments())
+	assert.InDelta(t, 8, res.ObjectiveValue(), delta)
+	assert.Nil(t, a.Variable(1, 1))
+}
+
+func TestAssignmentInconsistent(t *testing.T) {
+	_, err := BuildAssignment([][]float64{{1, 2}, {3}})
+	assert.Error(t, err)
+}
+
+func TestKnapsack(t *testing.T) {
+	k, err := BuildKnapsack([]Item{
+		{Weight: 5, Value: 10},
+		{Weight: 4, Value: 40},
+		{Weight: 6, Value: 30},
+		{Weight: 3, Value: 50},
+		{Weight: 11, Value: 100},
+	}, 10)
+	require.NoError(t, err)
+
+	// items heavier than the capacity are fixed to zero
+	_, upper := k.Model.Variables()[4].Bounds()
+	assert.Equal(t, 0.0, upper)
+
+	res, err := k.Solve()
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 3}, res.Selected())
+	assert.InDelta(t, 90, res.ObjectiveValue(), delta)
+}
+
+func TestBinPacking(t *testing.T) {
+	sizes := []float64{4, 8, 1, 4, 2, 1}
+	p, err := BuildBinPacking(sizes, 10, 0)
+	require.NoError(t, err)
+
+	res, err := p.Solve()
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2, res.ObjectiveValue(), delta)
+	require.Len(t, res.Bins(), 2)
+	for b, items := range res.Bins() {
+		total := 0.0
+		for _, i := range items {
+			total += sizes[i]
+			assert.Equal(t, b, res.Assignments()[i])
+		}
+		assert.LessOrEqual(t, total, 10.0)
+	}
+}
+
+func TestBinPackingOversized(t *testing.T) {
+	_, err := BuildBinPacking([]float64{1, 11}, 10, 0)
+	assert.Error(t, err)
+}
+
+func TestSetCover(t *testing.T) {
+	subsets := []Subset{
+		{Elements: []int{0, 1, 2}, Cost: 3},
+		{Elements: []int{1, 2, 3}, Cost: 1.5},
+		{Elements: []int{3}, Cost: 1},
+		{Elements: []int{0, 1}, Cost: 1.5},
+	}
+
+	s, err := BuildSetCover(4, subsets)
+	require.NoError(t, err)
+	res, err := s.Solve()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, res.Chosen())
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+
+	s, err = BuildSetPartitioning(4, subsets)
+	require.NoError(t, err)
+	res, err = s.Solve()
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, res.Chosen())
+	assert.InDelta(t, 4, res.ObjectiveValue(), delta)
+}
+
+func TestSetCoverUncovered(t *testing.T) {
+	_, err := BuildSetCover(3, []Subset{{Elements: []int{0, 1}, Cost: 1}})
+	assert.Error(t, err)
+
+	_, err = BuildSetCover(1, []Subset{{Elements: []int{0, 1}, Cost: 1}})
+	assert.Error(t, err)
+}
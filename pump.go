@@ -0,0 +1,170 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"math"
+	"sort"
+)
+
+// pumpFlips is the number of roundings flipped by the feasibility pump when
+// it detects a cycle.
+const pumpFlips = 10
+
+// FeasibilityPump searches for a feasible solution of a mixed-integer model
+// using the feasibility pump heuristic: the LP relaxation's solution is
+// rounded, and then the LP solution closest to the rounding is found, until
+// both coincide. The model itself is not modified.
+//
+// The returned solution satisfies all constraints and integrality
+// requirements, but is usually not optimal. If none is found within
+// maxIterations roundings, ErrNoFeasibleFound is returned; if the LP
+// relaxation is infeasible, so is the model and ErrModelInfeasible is
+// returned.
+func (model *Model) FeasibilityPump(maxIterations int) (map[*Variable]float64, error) {
+	orig := model.Variables()
+	relaxed := model.Clone()
+	vars := relaxed.Variables()
+
+	type intVar struct {
+		col          int
+		lower, upper float64
+		binary       bool
+		dist         *Variable   // |x - rounding| for general integers
+		above, below *Constraint // dist >= x - rounding, dist >= rounding - x
+	}
+	var ints []*intVar
+	for i, v := range vars {
+		typ := v.Type()
+		if typ == ContinuousVariable {
+			continue
+		}
+		lower, upper := v.Bounds()
+		ints = append(ints, &intVar{
+			col:    i,
+			lower:  lower,
+			upper:  upper,
+			binary: typ == BinaryVariable,
+		})
+		v.SetType(ContinuousVariable)
+	}
+
+	res, err := relaxed.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iv := range ints {
+		if iv.binary {
+			continue
+		}
+		iv.dist, err = relaxed.AddDefinedVariable("", ContinuousVariable, 0, 0, math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		if iv.above, err = relaxed.AddConstraint(0, math.Inf(1), []*Variable{iv.dist, vars[iv.col]}, []float64{1, -1}); err != nil {
+			return nil, err
+		}
+		if iv.below, err = relaxed.AddConstraint(0, math.Inf(1), []*Variable{iv.dist, vars[iv.col]}, []float64{1, 1}); err != nil {
+			return nil, err
+		}
+	}
+	relaxed.SetDirection(Minimize)
+
+	values := make([]float64, len(vars))
+	rounding := make([]float64, len(ints))
+	prev := make([]float64, len(ints))
+	for iter := 0; ; iter++ {
+		for i, v := range vars {
+			values[i] = res.Value(v)
+		}
+
+		integral := true
+		for _, iv := range ints {
+			if math.Abs(values[iv.col]-math.Round(values[iv.col])) > DefaultIntegralityTolerance {
+				integral = false
+				break
+			}
+		}
+		if integral {
+			solution := make(map[*Variable]float64, len(orig))
+			for i, v := range orig {
+				solution[v] = values[i]
+			}
+			for _, iv := range ints {
+				solution[orig[iv.col]] = math.Round(values[iv.col])
+			}
+			return solution, nil
+		}
+
+		if iter >= maxIterations {
+			return nil, ErrNoFeasibleFound
+		}
+
+		cycling := iter > 0
+		for k, iv := range ints {
+			prev[k] = rounding[k]
+			rounding[k] = math.Max(iv.lower, math.Min(iv.upper, math.Round(values[iv.col])))
+			if rounding[k] != prev[k] {
+				cycling = false
+			}
+		}
+		if cycling {
+			// flip the roundings farthest from the LP solution
+			order := make([]int, len(ints))
+			for k := range order {
+				order[k] = k
+			}
+			dist := func(k int) float64 { return math.Abs(values[ints[k].col] - rounding[k]) }
+			sort.SliceStable(order, func(a, b int) bool { return dist(order[a]) > dist(order[b]) })
+			if len(order) > pumpFlips {
+				order = order[:pumpFlips]
+			}
+			for _, k := range order {
+				flipped := rounding[k] + 1
+				if values[ints[k].col] < rounding[k] {
+					flipped = rounding[k] - 1
+				}
+				if flipped >= ints[k].lower && flipped <= ints[k].upper {
+					rounding[k] = flipped
+				}
+			}
+		}
+
+		for _, v := range relaxed.Variables() {
+			v.SetObjectiveCoefficient(0)
+		}
+		for k, iv := range ints {
+			x := vars[iv.col]
+			switch {
+			case iv.binary && rounding[k] == 0:
+				x.SetObjectiveCoefficient(1)
+			case iv.binary:
+				x.SetObjectiveCoefficient(-1)
+			default:
+				iv.dist.SetObjectiveCoefficient(1)
+				iv.above.SetBounds(-rounding[k], math.Inf(1))
+				iv.below.SetBounds(rounding[k], math.Inf(1))
+			}
+		}
+
+		if res, err = relaxed.Solve(); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetQuadraticObjectiveApprox adds the separable quadratic terms
+// q[i] * vars[i]^2 to the objective function, approximated by piecewise
+// linear functions with the given number of equally sized segments over
+// each variable's bounds. The linear objective coefficients are kept, so
+// together they form a quadratic objective; calling this function again adds
+// further quadratic terms.
+//
+// The approximation interpolates the quadratic terms between breakpoints,
+// which requires them to be convex when minimizing (q[i] >= 0) and concave
+// when maximizing (q[i] <= 0), as well as finite variable bounds. It is
+// exact at the breakpoints and overestimates the terms' cost in between by
+// at most |q[i]| * (segment width)^2 / 4.
+//
+// One auxiliary variable and one constraint per segment are added for each
+// non-zero term.
+func (model *Model) SetQuadraticObjectiveApprox(q []float64, vars []*Variable, segments int) error {
+	if len(vars) != len(q) {
+		return fmt.Errorf("inconsistent number of variables and coefficients: %d != %d", len(vars), len(q))
+	}
+	if segments < 1 {
+		return fmt.Errorf("need at least one segment, got %d", segments)
+	}
+
+	maximize := model.Direction() == Maximize
+	for i, v := range vars {
+		if v.model != model {
+			return fmt.Errorf("variable does not belong to model")
+		}
+		if maximize && q[i] > 0 || !maximize && q[i] < 0 {
+			return fmt.Errorf("quadratic term of %q is not convex in the optimization direction", v.Name())
+		}
+		if l, u := v.Bounds(); math.IsInf(l, 0) || math.IsInf(u, 0) {
+			return fmt.Errorf("quadratic term of %q requires finite bounds", v.Name())
+		}
+	}
+
+	for i, v := range vars {
+		if q[i] == 0 {
+			continue
+		}
+
+		// t holds the term's value: it is bounded by each chord between
+		// consecutive breakpoints, which for convex terms is tight at the
+		// interpolation
+		t, err := model.AddDefinedVariable("", ContinuousVariable, 1, math.Inf(-1), math.Inf(1))
+		if err != nil {
+			return err
+		}
+
+		lower, upper := v.Bounds()
+		width := (upper - lower) / float64(segments)
+		for k := 0; k < segments; k++ {
+			a := lower + float64(k)*width
+			b := a + width
+			slope := q[i] * (a + b)
+			intercept := -q[i] * a * b
+
+			// t - slope*v >= intercept when minimizing, <= when maximizing
+			low, high := intercept, math.Inf(1)
+			if maximize {
+				low, high = math.Inf(-1), intercept
+			}
+			if _, err := model.AddConstraint(low, high, []*Variable{t, v}, []float64{1, -slope}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FindRedundantConstraints returns the constraints which are implied by the
+// rest of the model and can therefore be removed without changing the set of
+// feasible solutions. A constraint is considered redundant if either:
+//
+//   - it cannot be violated by any values within the variables' bounds, or
+//   - another constraint over the same variables, with proportional
+//     coefficients, is at least as strict.
+//
+// Of several identical constraints, all but the first are considered
+// redundant.
+func (model *Model) FindRedundantConstraints() []*Constraint {
+	data := model.readData()
+
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	var redundant []*Constraint
+	for _, i := range data.redundantConstraints() {
+		redundant = append(redundant, model.cons[i])
+	}
+
+	return redundant
+}
+
+// RemoveRedundantConstraints removes all constraints reported by
+// FindRedundantConstraints from the model and returns them. The removed
+// constraints may not be used anymore.
+func (model *Model) RemoveRedundantConstraints() []*Constraint {
+	redundant := model.FindRedundantConstraints()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	model.removeConstraints(redundant)
+
+	return redundant
+}
+
+// redundantConstraints returns the indices of redundant constraints, in
+// increasing order.
+func (data *modelData) redundantConstraints() []int {
+	redundant := make(map[int]bool)
+
+	for i, c := range data.cons {
+		minActivity, maxActivity := data.activityBounds(c)
+		if minActivity >= c.lower && maxActivity <= c.upper {
+			redundant[i] = true
+		}
+	}
+
+	type normalized struct {
+		index        int
+		lower, upper float64
+	}
+
+	groups := make(map[string][]normalized)
+	for i, c := range data.cons {
+		if len(c.cols) == 0 {
+			continue
+		}
+
+		key, lower, upper := normalizeConstraint(c)
+		groups[key] = append(groups[key], normalized{i, lower, upper})
+	}
+
+	for _, group := range groups {
+		for _, a := range group {
+			for _, b := range group {
+				if a.index == b.index {
+					continue
+				}
+
+				// a is implied by b?
+				if b.lower >= a.lower && b.upper <= a.upper &&
+					(b.lower != a.lower || b.upper != a.upper || b.index < a.index) {
+					redundant[a.index] = true
+					break
+				}
+			}
+		}
+	}
+
+	indices := make([]int, 0, len(redundant))
+	for i := range redundant {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	return indices
+}
+
+// activityBounds returns the lowest and highest values the constraint's
+// terms can take given the variables' bounds.
+func (data *modelData) activityBounds(c conData) (min, max float64) {
+	for i, col := range c.cols {
+		coef := c.coefs[i]
+		lower, upper := data.vars[col].lower, data.vars[col].upper
+
+		if coef > 0 {
+			min += coef * lower
+			max += coef * upper
+		} else {
+			min += coef * upper
+			max += coef * lower
+		}
+	}
+
+	return min, max
+}
+
+// normalizeConstraint scales the constraint so its coefficient for the
+// variable with the lowest index is 1. It returns a key identifying the
+// scaled coefficients along with the scaled bounds.
+func normalizeConstraint(c conData) (key string, lower, upper float64) {
+	order := make([]int, len(c.cols))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return c.cols[order[i]] < c.cols[order[j]]
+	})
+
+	scale := c.coefs[order[0]]
+
+	var b strings.Builder
+	for _, i := range order {
+		b.WriteString(strconv.Itoa(c.cols[i]))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatFloat(c.coefs[i]/scale, 'g', -1, 64))
+		b.WriteByte(' ')
+	}
+
+	lower, upper = c.lower/scale, c.upper/scale
+	if scale < 0 {
+		lower, upper = upper, lower
+	}
+
+	return b.String(), lower, upper
+}
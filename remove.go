@@ -0,0 +1,114 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import "fmt"
+
+// RemoveConstraint deletes the constraint from the model. The remaining
+// constraints are renumbered; c's methods have no effect afterwards.
+//
+// Results of previous solves refer to the model as it was and must not be
+// used after removing constraints.
+func (model *Model) RemoveConstraint(c *Constraint) error {
+	if c == nil || c.model != model {
+		return fmt.Errorf("constraint does not belong to model %q", model.Name())
+	}
+
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	// a concurrent removal sets the index under the write lock
+	if c.index < 0 {
+		return fmt.Errorf("constraint does not belong to model %q", model.name())
+	}
+
+	model.removeConstraints([]*Constraint{c})
+
+	return nil
+}
+
+// RemoveVariable deletes the variable from the model, including its terms
+// in all constraints and objectives. The remaining variables are
+// renumbered; v's methods, and parameters bound to it, have no effect
+// afterwards.
+//
+// Results of previous solves refer to the model as it was and must not be
+// used after removing variables.
+func (model *Model) RemoveVariable(v *Variable) error {
+	if v == nil || v.model != model {
+		return fmt.Errorf("variable does not belong to model %q", model.Name())
+	}
+
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if v.index < 0 {
+		return fmt.Errorf("variable does not belong to model %q", model.name())
+	}
+
+	if model.names != nil {
+		delete(model.names, model.colName(v.index+1))
+	}
+
+	C.del_column(model.prob, C.int(v.index+1))
+
+	// slices returned by Variables must not change
+	vars := make([]*Variable, 0, len(model.vars)-1)
+	vars = append(vars, model.vars[:v.index]...)
+	model.vars = append(vars, model.vars[v.index+1:]...)
+	for i := v.index; i < len(model.vars); i++ {
+		model.vars[i].index = i
+	}
+
+	for _, obj := range model.objectives {
+		obj.remove(v)
+	}
+
+	removed := v.index
+	model.scaling = model.scaling.renumber(func(i int) int {
+		switch {
+		case i < removed:
+			return i
+		case i == removed:
+			return -1
+		}
+		return i - 1
+	}, nil)
+
+	// the keys of deduplicated constraints contain variable indices
+	if model.dedup != nil {
+		model.dedup = make(map[string]*Constraint, len(model.cons))
+		for _, c := range model.cons {
+			if key := model.rowKey(c); model.dedup[key] == nil {
+				model.dedup[key] = c
+			}
+		}
+	}
+
+	v.index = -1
+
+	return nil
+}
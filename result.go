@@ -17,24 +17,59 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package golpa
 
-// #cgo CFLAGS: -I/usr/include/lpsolve/
-// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
 // #include <lp_lib.h>
 // #include <stdlib.h>
 import "C"
 
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
 /* Types */
 
 type SolveResult struct {
-	model  *Model
-	status SolveStatus
+	model        *Model
+	status       SolveStatus
+	rounding     RoundingPolicy
+	intTolerance float64
+	values       []float64 // variable values, if read all at once
+	scaling      *scaling  // in effect when solved
+	objective    *float64  // objective value, if read with values
+	objectiveFn  string    // named objective solved, if any
+	timings      Timings
+	iterations   IterationStats
+	stats        Stats
 }
 
+// RoundingPolicy determines how SolveResult.IntValue converts values to
+// integers.
+type RoundingPolicy int
+
+const (
+	// RoundNearest rounds values to the nearest integer.
+	RoundNearest RoundingPolicy = iota
+	// RoundDown rounds values down, unless they are within the integrality
+	// tolerance of the next integer.
+	RoundDown
+	// RoundUp rounds values up, unless they are within the integrality
+	// tolerance of the previous integer.
+	RoundUp
+)
+
+// DefaultIntegralityTolerance is the distance from the nearest integer
+// below which values are considered integral, unless configured otherwise
+// with WithRounding.
+const DefaultIntegralityTolerance = 1e-6
+
 type SolveStatus C.int
 
 const (
 	SolutionOptimal    = SolveStatus(C.OPTIMAL)
 	SolutionSuboptimal = SolveStatus(C.SUBOPTIMAL)
+	SolutionInfeasible = SolveStatus(C.INFEASIBLE)
+	SolutionUnbounded  = SolveStatus(C.UNBOUNDED)
 )
 
 type SolveError C.int
@@ -87,7 +122,9 @@ func (e SolveError) Error() string {
 }
 
 // Status reports if the solution is optimal (SolutionOptimal) or
-// not (SolutionSuboptimal)
+// not (SolutionSuboptimal). Results returned along with ErrModelInfeasible
+// or ErrModelUnbounded have the status SolutionInfeasible or
+// SolutionUnbounded, respectively.
 func (res SolveResult) Status() SolveStatus {
 	return res.status
 }
@@ -100,13 +137,56 @@ func (res SolveResult) Value(v *Variable) float64 {
 }
 
 // PrimalValue returns the computed value of the given variable for
-// this optimization result.
+// this optimization result, or 0 if the variable has been removed.
 func (res SolveResult) PrimalValue(v *Variable) float64 {
+	if v.index < 0 {
+		return 0
+	}
+	if v.index < len(res.values) {
+		return res.values[v.index] * res.scaling.col(v)
+	}
+
 	res.model.mu.RLock()
 	defer res.model.mu.RUnlock()
 
 	// get_var_*result uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
-	return float64(C.get_var_primalresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1)))
+	return float64(C.get_var_primalresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1))) * res.scaling.col(v)
+}
+
+// IntValue returns the computed value of the given variable converted to an
+// integer according to the rounding policy set with WithRounding. By
+// default, values are rounded to the nearest integer, which avoids
+// truncating values like 2.9999999 reported for integer variables to 2.
+func (res SolveResult) IntValue(v *Variable) int {
+	value := res.PrimalValue(v)
+	switch res.rounding {
+	case RoundDown:
+		return int(math.Floor(value + res.intTolerance))
+	case RoundUp:
+		return int(math.Ceil(value - res.intTolerance))
+	default:
+		return int(math.Round(value))
+	}
+}
+
+// BoolValue returns the computed value of the given binary variable as a
+// boolean. An error is returned if the variable is not binary or if its
+// value is farther than the integrality tolerance (see WithRounding) from
+// both 0 and 1.
+func (res SolveResult) BoolValue(v *Variable) (bool, error) {
+	if v.Type() != BinaryVariable {
+		return false, fmt.Errorf("variable %q is not binary", v.Name())
+	}
+
+	value := res.PrimalValue(v)
+	switch {
+	case math.Abs(value) <= res.intTolerance:
+		return false, nil
+	case math.Abs(value-1) <= res.intTolerance:
+		return true, nil
+	default:
+		return false, fmt.Errorf("value %g of %q is not binary", value, v.Name())
+	}
 }
 
 // DualValue returns the dual value of the given variable in this
@@ -116,15 +196,82 @@ func (res SolveResult) DualValue(v *Variable) float64 {
 	defer res.model.mu.RUnlock()
 
 	// get_var_*result uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
-	return float64(C.get_var_dualresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1)))
+	return float64(C.get_var_dualresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1))) / res.scaling.col(v)
+}
+
+// ShadowPrice returns the dual value of the given constraint in this
+// optimization result, i.e. the rate at which the objective value changes
+// per unit increase of the constraint's active bound.
+func (res SolveResult) ShadowPrice(c *Constraint) float64 {
+	res.model.mu.RLock()
+	defer res.model.mu.RUnlock()
+
+	// get_var_*result uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
+	return float64(C.get_var_dualresult(res.model.prob, C.int(c.index+1))) * res.scaling.row(c)
 }
 
 // ObjectiveValue returns the value of the objective function for
 // this optimization result. This value is only optimal if Status
 // also returns SolutionOptimal.
 func (res SolveResult) ObjectiveValue() float64 {
+	if res.objective != nil {
+		return *res.objective
+	}
+
 	res.model.mu.RLock()
 	defer res.model.mu.RUnlock()
 
 	return float64(C.get_objective(res.model.prob))
 }
+
+// ObjectiveValueInt returns the value of the objective function as an
+// exact integer, computed from the rounded values of the variables instead
+// of the solver's floating point objective value. The objective is the one
+// which was solved, i.e. the one selected with WithObjective, if any. The
+// boolean result is false, and the value meaningless, unless all variables
+// with non-zero objective coefficients are integer or binary, their
+// coefficients are integral, their values are within the integrality
+// tolerance (see WithRounding) of an integer and the objective value fits
+// into an int64.
+//
+// The objective coefficients and the variables' types are read from the
+// model when called, not when solving, so changes made to them after solving
+// affect the value returned.
+func (res SolveResult) ObjectiveValueInt() (int64, bool) {
+	res.model.mu.RLock()
+	vars := append([]*Variable(nil), res.model.vars...)
+	coefs := res.model.objectiveRow()
+	if res.objectiveFn != "" {
+		obj, ok := res.model.objectives[res.objectiveFn]
+		if !ok {
+			res.model.mu.RUnlock()
+			return 0, false
+		}
+		coefs = obj.row(len(vars))
+	}
+	res.model.mu.RUnlock()
+
+	total, term := new(big.Int), new(big.Int)
+	for i, v := range vars {
+		coef := coefs[i]
+		if coef == 0 {
+			continue
+		}
+		if v.Type() == ContinuousVariable || coef != math.Trunc(coef) || math.Abs(coef) > 1<<53 {
+			return 0, false
+		}
+
+		value := res.PrimalValue(v)
+		rounded := math.Round(value)
+		if math.Abs(value-rounded) > res.intTolerance || math.Abs(rounded) >= 1<<63 {
+			return 0, false
+		}
+		term.SetInt64(int64(coef))
+		total.Add(total, term.Mul(term, big.NewInt(int64(rounded))))
+	}
+	if !total.IsInt64() {
+		return 0, false
+	}
+
+	return total.Int64(), true
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package risk provides linear risk measures for scenario-based golpa
+// models, such as portfolio models where each scenario's loss is a linear
+// expression of the decisions.
+package risk
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// probabilityTolerance is the largest deviation from 1 accepted for the sum
+// of scenario probabilities.
+const probabilityTolerance = 1e-9
+
+// CVaR is the conditional value at risk of a loss distribution, i.e. the
+// expected loss in the worst 1-alpha of cases, as linearized by Rockafellar
+// and Uryasev:
+//
+//	CVaR = VaR + 1/(1-alpha) * sum(p[s] * Excess[s])
+//	Excess[s] >= loss[s] - VaR, Excess[s] >= 0
+//
+// The expression is at least the CVaR for any values of the variables and
+// equals it when minimized, so it can be minimized or bounded from above,
+// but not maximized or bounded from below.
+type CVaR struct {
+	// VaR holds the value at risk at the optimum.
+	VaR *golpa.Variable
+	// Excess holds each scenario's loss exceeding VaR.
+	Excess []*golpa.Variable
+
+	alpha float64
+	expr  golpa.Expr
+}
+
+// AddCVaR adds the variables and constraints of the CVaR at confidence level
+// alpha (e.g. 0.95) of the given scenario losses, which are linear
+// expressions of the model's variables. Probabilities are given per
+// scenario and must add up to 1; if nil, scenarios are equally likely.
+func AddCVaR(model *golpa.Model, losses []golpa.Expr, probabilities []float64, alpha float64) (*CVaR, error) {
+	if alpha < 0 || alpha >= 1 {
+		return nil, fmt.Errorf("confidence level must be in [0, 1), got %g", alpha)
+	}
+	if len(losses) == 0 {
+		return nil, fmt.Errorf("no scenarios")
+	}
+	if probabilities == nil {
+		probabilities = make([]float64, len(losses))
+		for i := range probabilities {
+			probabilities[i] = 1 / float64(len(losses))
+		}
+	}
+	if len(probabilities) != len(losses) {
+		return nil, fmt.Errorf("inconsistent number of probabilities and losses: %d != %d", len(probabilities), len(losses))
+	}
+	total := 0.0
+	for s, p := range probabilities {
+		if p < 0 {
+			return nil, fmt.Errorf("scenario %d has negative probability %g", s, p)
+		}
+		total += p
+	}
+	if math.Abs(total-1) > probabilityTolerance {
+		return nil, fmt.Errorf("probabilities add up to %g", total)
+	}
+
+	valueAtRisk, err := model.AddDefinedVariable("", golpa.ContinuousVariable, 0, math.Inf(-1), math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CVaR{
+		VaR:    valueAtRisk,
+		Excess: make([]*golpa.Variable, len(losses)),
+		alpha:  alpha,
+		expr:   golpa.Term(1, valueAtRisk),
+	}
+	for s, loss := range losses {
+		excess, err := model.AddDefinedVariable("", golpa.ContinuousVariable, 0, 0, math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		c.Excess[s] = excess
+
+		// excess + VaR - loss >= 0
+		e := golpa.Term(1, excess).Plus(golpa.Term(1, valueAtRisk)).Plus(loss.Scale(-1))
+		if _, err := model.AddExprConstraint(0, math.Inf(1), e); err != nil {
+			return nil, fmt.Errorf("scenario %d: %w", s, err)
+		}
+
+		c.expr = c.expr.Plus(golpa.Term(probabilities[s]/(1-alpha), excess))
+	}
+
+	return c, nil
+}
+
+// Expr returns the CVaR as a linear expression, e.g. for minimizing it with
+// Model.SetObjectiveExpr.
+func (c *CVaR) Expr() golpa.Expr {
+	return c.expr
+}
+
+// Limit adds the constraint CVaR <= limit.
+func (c *CVaR) Limit(model *golpa.Model, limit float64) (*golpa.Constraint, error) {
+	return model.AddExprConstraint(math.Inf(-1), limit, c.expr)
+}
+
+// Value returns the CVaR in a solution of the model. It is exact if the CVaR
+// was minimized or its limit is binding, and otherwise an upper bound.
+func (c *CVaR) Value(res *golpa.SolveResult) float64 {
+	vars, coefs := c.expr.Terms()
+	value := c.expr.Constant()
+	for i, v := range vars {
+		value += coefs[i] * res.Value(v)
+	}
+	return value
+}
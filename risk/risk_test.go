@@ -0,0 +1,95 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.000001
+
+func TestCVaR(t *testing.T) {
+	model, err := golpa.NewModel("cvar", golpa.Minimize)
+	require.NoError(t, err)
+
+	var losses []golpa.Expr
+	for _, loss := range []float64{1, 2, 3, 4} {
+		losses = append(losses, golpa.Expr{}.PlusConstant(loss))
+	}
+
+	// the expected loss of the worst half of the scenarios
+	c, err := AddCVaR(model, losses, nil, 0.5)
+	require.NoError(t, err)
+	require.NoError(t, model.SetObjectiveExpr(c.Expr()))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3.5, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 3.5, c.Value(res), delta)
+}
+
+func TestCVaRLimit(t *testing.T) {
+	model, err := golpa.NewModel("portfolio", golpa.Minimize)
+	require.NoError(t, err)
+
+	// a safe asset with a steady gain of 1 and a risky one gaining 3 in
+	// three of four scenarios and losing 2 in the fourth; the expected
+	// loss is minimized
+	safe, err := model.AddDefinedVariable("safe", golpa.ContinuousVariable, -1, 0, math.Inf(1))
+	require.NoError(t, err)
+	risky, err := model.AddDefinedVariable("risky", golpa.ContinuousVariable, -1.75, 0, math.Inf(1))
+	require.NoError(t, err)
+	_, err = model.AddExprConstraint(1, 1, golpa.Sum(safe, risky))
+	require.NoError(t, err)
+
+	var losses []golpa.Expr
+	for _, loss := range []float64{-3, -3, -3, 2} {
+		losses = append(losses, golpa.Dot([]float64{-1, loss}, []*golpa.Variable{safe, risky}))
+	}
+	c, err := AddCVaR(model, losses, []float64{0.25, 0.25, 0.25, 0.25}, 0.75)
+	require.NoError(t, err)
+
+	// the CVaR at 75% is the worst scenario's loss, -safe + 2 risky
+	_, err = c.Limit(model, 0)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0/3, res.Value(safe), delta)
+	assert.InDelta(t, 1.0/3, res.Value(risky), delta)
+	assert.InDelta(t, -1.25, res.ObjectiveValue(), delta)
+}
+
+func TestCVaRErrors(t *testing.T) {
+	model, err := golpa.NewModel("cvar", golpa.Minimize)
+	require.NoError(t, err)
+
+	losses := []golpa.Expr{{}, {}}
+	_, err = AddCVaR(model, losses, nil, 1)
+	assert.Error(t, err)
+	_, err = AddCVaR(model, losses, []float64{0.5}, 0.9)
+	assert.Error(t, err)
+	_, err = AddCVaR(model, losses, []float64{0.5, 0.6}, 0.9)
+	assert.Error(t, err)
+	_, err = AddCVaR(model, nil, nil, 0.9)
+	assert.Error(t, err)
+}
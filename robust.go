@@ -0,0 +1,135 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddRobustConstraint adds the robust counterpart of a constraint whose
+// coefficients are uncertain: the coefficient of vars[i] may take any value
+// within nominal[i] ± deviations[i]. The resulting constraints guarantee that
+// lower <= Σ a[i]·vars[i] <= upper holds for every realization of the
+// coefficients in the uncertainty set.
+//
+// The uncertainty set is determined by budget:
+//
+//   - if budget is at least the number of uncertain coefficients (or
+//     infinite), all coefficients may deviate at once (box uncertainty);
+//   - otherwise, at most budget coefficients may deviate simultaneously
+//     (budgeted uncertainty, as proposed by Bertsimas and Sim). Fractional
+//     budgets are allowed.
+//
+// Auxiliary variables and constraints are added as needed. The returned
+// constraints correspond to the lower and upper bounds, in that order, and
+// are nil for infinite bounds.
+func (model *Model) AddRobustConstraint(lower, upper float64, vars []*Variable, nominal, deviations []float64, budget float64) (lowerCons, upperCons *Constraint, err error) {
+	if len(vars) != len(nominal) || len(vars) != len(deviations) {
+		return nil, nil, fmt.Errorf("inconsistent number of variables, nominal coefficients and deviations: %d, %d, %d", len(vars), len(nominal), len(deviations))
+	}
+	if budget < 0 {
+		return nil, nil, fmt.Errorf("negative uncertainty budget %g", budget)
+	}
+
+	// abs[i] is a term equal to (or, where needed, bounding) |vars[i]|
+	abs := make([]*Variable, len(vars))
+	absCoefs := make([]float64, len(vars))
+	var uncertain []int
+	for i, v := range vars {
+		if deviations[i] == 0 {
+			continue
+		}
+		uncertain = append(uncertain, i)
+
+		l, u := v.Bounds()
+		switch {
+		case l >= 0:
+			abs[i], absCoefs[i] = v, 1
+		case u <= 0:
+			abs[i], absCoefs[i] = v, -1
+		default:
+			y, err := model.AddAnonymousVariable(ContinuousVariable, 0, math.Inf(1))
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err := model.AddConstraint(0, math.Inf(1), []*Variable{y, v}, []float64{1, -1}); err != nil {
+				return nil, nil, err
+			}
+			if _, err := model.AddConstraint(0, math.Inf(1), []*Variable{y, v}, []float64{1, 1}); err != nil {
+				return nil, nil, err
+			}
+			abs[i], absCoefs[i] = y, 1
+		}
+	}
+
+	box := math.IsInf(budget, 1) || budget >= float64(len(uncertain))
+
+	// side adds sign·Σ a·x + protection <= sign·bound
+	side := func(sign, bound float64) (*Constraint, error) {
+		rowVars := append([]*Variable{}, vars...)
+		rowCoefs := make([]float64, len(vars))
+		for i := range vars {
+			rowCoefs[i] = sign * nominal[i]
+		}
+
+		if box {
+			for _, i := range uncertain {
+				rowVars = append(rowVars, abs[i])
+				rowCoefs = append(rowCoefs, math.Abs(deviations[i])*absCoefs[i])
+			}
+		} else {
+			z, err := model.AddAnonymousVariable(ContinuousVariable, 0, math.Inf(1))
+			if err != nil {
+				return nil, err
+			}
+			rowVars = append(rowVars, z)
+			rowCoefs = append(rowCoefs, budget)
+
+			for _, i := range uncertain {
+				p, err := model.AddAnonymousVariable(ContinuousVariable, 0, math.Inf(1))
+				if err != nil {
+					return nil, err
+				}
+				rowVars = append(rowVars, p)
+				rowCoefs = append(rowCoefs, 1)
+
+				// z + p >= |deviation|·|x|
+				if _, err := model.AddConstraint(0, math.Inf(1),
+					[]*Variable{z, p, abs[i]}, []float64{1, 1, -math.Abs(deviations[i]) * absCoefs[i]}); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return model.AddConstraint(math.Inf(-1), sign*bound, rowVars, rowCoefs)
+	}
+
+	if !math.IsInf(lower, 0) {
+		if lowerCons, err = side(-1, lower); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !math.IsInf(upper, 0) {
+		if upperCons, err = side(1, upper); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return lowerCons, upperCons, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"errors"
+	"math"
+)
+
+// RoundAndRepair quickly searches for a feasible solution of a
+// mixed-integer model: the LP relaxation is solved, its integer variables
+// are rounded to the nearest integer within their bounds and fixed, and a
+// repair LP over the remaining continuous variables is solved with the
+// model's objective function. The model itself is not modified.
+//
+// The returned solution's objective value is a primal bound for the model,
+// but rounding often fails on models with tight integer constraints, in
+// which case ErrNoFeasibleFound is returned. If the LP relaxation is
+// infeasible, so is the model and ErrModelInfeasible is returned.
+func (model *Model) RoundAndRepair() (map[*Variable]float64, error) {
+	orig := model.Variables()
+	relaxed := model.Clone()
+	vars := relaxed.Variables()
+
+	var ints []*Variable
+	for _, v := range vars {
+		if v.Type() != ContinuousVariable {
+			ints = append(ints, v)
+			v.SetType(ContinuousVariable)
+		}
+	}
+
+	res, err := relaxed.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range ints {
+		lower, upper := v.Bounds()
+		rounded := math.Max(math.Ceil(lower), math.Min(math.Floor(upper), math.Round(res.Value(v))))
+		v.SetBounds(rounded, rounded)
+	}
+
+	if res, err = relaxed.Solve(); err != nil {
+		if errors.Is(err, ErrModelInfeasible) {
+			return nil, ErrNoFeasibleFound
+		}
+		return nil, err
+	}
+
+	solution := make(map[*Variable]float64, len(orig))
+	for i, v := range orig {
+		solution[v] = res.Value(vars[i])
+	}
+	for _, v := range ints {
+		lower, _ := v.Bounds()
+		solution[orig[v.index]] = lower
+	}
+
+	return solution, nil
+}
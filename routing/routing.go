@@ -0,0 +1,285 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package routing provides helpers for building traveling salesman and
+// vehicle routing models with golpa.
+//
+// Routing models are built on an undirected Graph with one binary variable
+// per edge. Degree constraints make every node part of a cycle; subtour
+// elimination constraints are then separated iteratively: the model is
+// solved, cycles not visiting the depot are cut off and the model is solved
+// again. lp_solve offers no lazy constraint callback, so separation happens
+// between solves instead of inside the branch-and-bound.
+package routing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// DefaultMaxRounds is the default limit on the number of separation rounds
+// performed by Graph.Solve.
+const DefaultMaxRounds = 100
+
+// Graph is a complete undirected graph whose edges are binary variables of a
+// model.
+type Graph struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	edges [][]*golpa.Variable
+}
+
+// AddEdges adds one binary variable per edge of the graph described by the
+// given cost matrix to the model. Only costs[i][j] with i < j are used; a
+// cost of math.Inf(1) leaves the edge out.
+func AddEdges(model *golpa.Model, costs [][]float64) (*Graph, error) {
+	n := len(costs)
+	g := &Graph{
+		Model: model,
+		edges: make([][]*golpa.Variable, n),
+	}
+
+	for i := range g.edges {
+		if len(costs[i]) != n {
+			return nil, fmt.Errorf("cost matrix is not square: row %d has %d columns, expected %d", i, len(costs[i]), n)
+		}
+		g.edges[i] = make([]*golpa.Variable, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if math.IsInf(costs[i][j], 1) {
+				continue
+			}
+			v, err := model.AddDefinedVariable(fmt.Sprintf("e_%d_%d", i, j), golpa.BinaryVariable, costs[i][j], 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			g.edges[i][j] = v
+			g.edges[j][i] = v
+		}
+	}
+
+	return g, nil
+}
+
+// BuildTSP builds a symmetric traveling salesman problem with the given
+// distance matrix, including degree constraints.
+func BuildTSP(dist [][]float64) (*Graph, error) {
+	model, err := golpa.NewModel("tsp", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := AddEdges(model, dist)
+	if err != nil {
+		return nil, err
+	}
+
+	degrees := make([]float64, len(dist))
+	for i := range degrees {
+		degrees[i] = 2
+	}
+
+	return g, g.AddDegreeConstraints(degrees)
+}
+
+// Edge returns the variable of the edge between nodes i and j, or nil if the
+// edge was left out.
+func (g *Graph) Edge(i, j int) *golpa.Variable {
+	return g.edges[i][j]
+}
+
+// AddDegreeConstraints requires each node i to be the endpoint of exactly
+// degrees[i] edges. Use 2 for every node to get tours; for vehicle routing,
+// use 2 times the number of vehicles for the depot.
+func (g *Graph) AddDegreeConstraints(degrees []float64) error {
+	if len(degrees) != len(g.edges) {
+		return fmt.Errorf("inconsistent number of degrees and nodes: %d != %d", len(degrees), len(g.edges))
+	}
+
+	for i, degree := range degrees {
+		var vars []*golpa.Variable
+		for _, v := range g.edges[i] {
+			if v != nil {
+				vars = append(vars, v)
+			}
+		}
+		coefs := make([]float64, len(vars))
+		for k := range coefs {
+			coefs[k] = 1
+		}
+
+		c, err := g.Model.AddConstraint(degree, degree, vars, coefs)
+		if err != nil {
+			return err
+		}
+		c.SetName(fmt.Sprintf("degree_%d", i))
+	}
+
+	return nil
+}
+
+// AddSubtourElimination forbids cycles within the given set of nodes, by
+// allowing at most len(nodes)-1 edges between them.
+func (g *Graph) AddSubtourElimination(nodes []int) (*golpa.Constraint, error) {
+	var vars []*golpa.Variable
+	for a, i := range nodes {
+		for _, j := range nodes[a+1:] {
+			if v := g.edges[i][j]; v != nil {
+				vars = append(vars, v)
+			}
+		}
+	}
+	coefs := make([]float64, len(vars))
+	for k := range coefs {
+		coefs[k] = 1
+	}
+
+	return g.Model.AddConstraint(math.Inf(-1), float64(len(nodes)-1), vars, coefs)
+}
+
+// Subtours returns the connected components of the solution which don't
+// contain the depot node. Each of them violates a subtour elimination
+// constraint.
+func (g *Graph) Subtours(res *golpa.SolveResult, depot int) [][]int {
+	var subtours [][]int
+	for _, component := range g.components(res) {
+		if !contains(component, depot) {
+			subtours = append(subtours, component)
+		}
+	}
+	return subtours
+}
+
+// Solve solves the model, adding subtour elimination constraints for each
+// subtour found in the solution and solving again, until the solution has
+// none left. At most maxRounds rounds are performed (DefaultMaxRounds if
+// maxRounds is not positive); if the limit is reached, an error is returned.
+func (g *Graph) Solve(depot, maxRounds int) (*golpa.SolveResult, error) {
+	if maxRounds <= 0 {
+		maxRounds = DefaultMaxRounds
+	}
+
+	for round := 0; round < maxRounds; round++ {
+		res, err := g.Model.Solve()
+		if err != nil {
+			return nil, err
+		}
+
+		subtours := g.Subtours(res, depot)
+		if len(subtours) == 0 {
+			return res, nil
+		}
+
+		for _, nodes := range subtours {
+			if _, err := g.AddSubtourElimination(nodes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("subtours left after %d rounds", maxRounds)
+}
+
+// Routes returns the cycles through the depot in the solution, as sequences
+// of nodes starting after the depot. For a TSP solution, this is a single
+// tour.
+func (g *Graph) Routes(res *golpa.SolveResult, depot int) [][]int {
+	used := make(map[[2]int]bool)
+	var routes [][]int
+
+	for {
+		next := -1
+		for j, v := range g.edges[depot] {
+			if v != nil && !used[edgeKey(depot, j)] && res.Value(v) > 0.5 {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return routes
+		}
+
+		var route []int
+		prev := depot
+		for next != depot && next >= 0 {
+			used[edgeKey(prev, next)] = true
+			route = append(route, next)
+
+			cur := next
+			next = -1
+			for j, v := range g.edges[cur] {
+				if v != nil && !used[edgeKey(cur, j)] && res.Value(v) > 0.5 {
+					next = j
+					break
+				}
+			}
+			prev = cur
+		}
+		if next == depot {
+			used[edgeKey(prev, depot)] = true
+		}
+		routes = append(routes, route)
+	}
+}
+
+// components returns the connected components of the graph formed by the
+// edges selected in the solution.
+func (g *Graph) components(res *golpa.SolveResult) [][]int {
+	seen := make([]bool, len(g.edges))
+	var components [][]int
+
+	for start := range g.edges {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		component := []int{start}
+		for k := 0; k < len(component); k++ {
+			for j, v := range g.edges[component[k]] {
+				if v != nil && !seen[j] && res.Value(v) > 0.5 {
+					seen[j] = true
+					component = append(component, j)
+				}
+			}
+		}
+		components = append(components, component)
+	}
+
+	return components
+}
+
+func edgeKey(i, j int) [2]int {
+	if i > j {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
+
+func contains(nodes []int, node int) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package routing
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+// lineDistances returns the distances between points on a line.
+func lineDistances(xs []float64) [][]float64 {
+	dist := make([][]float64, len(xs))
+	for i := range xs {
+		dist[i] = make([]float64, len(xs))
+		for j := range xs {
+			dist[i][j] = math.Abs(xs[i] - xs[j])
+		}
+	}
+	return dist
+}
+
+func TestTSP(t *testing.T) {
+	g, err := BuildTSP(lineDistances([]float64{0, 1, 2, 10, 11, 12}))
+	require.NoError(t, err)
+
+	// the degree constraints alone allow two triangles
+	res, err := g.Model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 8, res.ObjectiveValue(), delta)
+	assert.Equal(t, [][]int{{3, 4, 5}}, sortedComponents(g.Subtours(res, 0)))
+
+	res, err = g.Solve(0, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 24, res.ObjectiveValue(), delta)
+
+	routes := g.Routes(res, 0)
+	require.Len(t, routes, 1)
+	tour := append([]int(nil), routes[0]...)
+	sort.Ints(tour)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, tour)
+}
+
+func TestAddEdgesNotSquare(t *testing.T) {
+	_, err := BuildTSP([][]float64{{0, 1}, {1}})
+	assert.Error(t, err)
+}
+
+func sortedComponents(components [][]int) [][]int {
+	for _, c := range components {
+		sort.Ints(c)
+	}
+	return components
+}
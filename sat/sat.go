@@ -0,0 +1,131 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package sat encodes boolean satisfiability constraints on binary
+// variables of golpa models.
+//
+// Clauses in conjunctive normal form and pseudo-boolean constraints, i.e.
+// weighted sums of literals, become linear constraints, so constraint-style
+// feasibility problems can be mixed with other model constraints and solved
+// like any other model, e.g. with Model.SolveFeasible.
+package sat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Literal is a binary variable or its negation.
+type Literal struct {
+	Var     *golpa.Variable
+	Negated bool
+}
+
+// Pos returns the literal which is true if v is 1.
+func Pos(v *golpa.Variable) Literal {
+	return Literal{Var: v}
+}
+
+// Not returns the literal which is true if v is 0.
+func Not(v *golpa.Variable) Literal {
+	return Literal{Var: v, Negated: true}
+}
+
+// Negate returns the negation of the literal.
+func (l Literal) Negate() Literal {
+	return Literal{Var: l.Var, Negated: !l.Negated}
+}
+
+// Value returns the truth value of the literal in a solution of the model.
+func (l Literal) Value(res *golpa.SolveResult) bool {
+	return (res.Value(l.Var) > 0.5) != l.Negated
+}
+
+// AddPseudoBoolean adds the constraint
+// lower <= weights[0]*lits[0] + weights[1]*lits[1] + ... <= upper, where
+// literals count as 1 if true and 0 otherwise. To leave one side
+// unbounded, pass math.Inf(-1) or math.Inf(1) respectively.
+//
+// All literals must be of binary variables.
+func AddPseudoBoolean(model *golpa.Model, weights []float64, lits []Literal, lower, upper float64) (*golpa.Constraint, error) {
+	if len(weights) != len(lits) {
+		return nil, fmt.Errorf("inconsistent number of weights and literals: %d != %d", len(weights), len(lits))
+	}
+
+	vars := make([]*golpa.Variable, len(lits))
+	coefs := make([]float64, len(lits))
+	for i, l := range lits {
+		if l.Var.Type() != golpa.BinaryVariable {
+			return nil, fmt.Errorf("variable %q is not binary", l.Var.Name())
+		}
+		vars[i] = l.Var
+		coefs[i] = weights[i]
+		if l.Negated {
+			// w * (1 - x) = w - w * x
+			coefs[i] = -weights[i]
+			lower -= weights[i]
+			upper -= weights[i]
+		}
+	}
+
+	return model.AddConstraint(lower, upper, vars, coefs)
+}
+
+// AddClause adds the clause requiring at least one of the literals to be
+// true.
+func AddClause(model *golpa.Model, lits ...Literal) (*golpa.Constraint, error) {
+	return AddPseudoBoolean(model, ones(len(lits)), lits, 1, math.Inf(1))
+}
+
+// AddCNF adds each of the clauses, so that all of them must be satisfied.
+func AddCNF(model *golpa.Model, clauses [][]Literal) ([]*golpa.Constraint, error) {
+	cons := make([]*golpa.Constraint, len(clauses))
+	for i, clause := range clauses {
+		c, err := AddClause(model, clause...)
+		if err != nil {
+			return nil, fmt.Errorf("clause %d: %w", i, err)
+		}
+		cons[i] = c
+	}
+	return cons, nil
+}
+
+// AddImplication requires b to be true if a is.
+func AddImplication(model *golpa.Model, a, b Literal) (*golpa.Constraint, error) {
+	return AddClause(model, a.Negate(), b)
+}
+
+// AddAtMostOne requires at most one of the literals to be true.
+func AddAtMostOne(model *golpa.Model, lits ...Literal) (*golpa.Constraint, error) {
+	return AddPseudoBoolean(model, ones(len(lits)), lits, math.Inf(-1), 1)
+}
+
+// AddExactlyOne requires exactly one of the literals to be true.
+func AddExactlyOne(model *golpa.Model, lits ...Literal) (*golpa.Constraint, error) {
+	return AddPseudoBoolean(model, ones(len(lits)), lits, 1, 1)
+}
+
+// ones returns a slice of n ones.
+func ones(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = 1
+	}
+	return s
+}
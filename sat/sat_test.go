@@ -0,0 +1,94 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package sat
+
+import (
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// binaries returns a model with n binary variables.
+func binaries(t *testing.T, n int) (*golpa.Model, []*golpa.Variable) {
+	model, err := golpa.NewModel("sat", golpa.Minimize)
+	require.NoError(t, err)
+
+	vars := make([]*golpa.Variable, n)
+	for i := range vars {
+		vars[i], err = model.AddDefinedVariable("", golpa.BinaryVariable, 0, 0, 1)
+		require.NoError(t, err)
+	}
+	return model, vars
+}
+
+func TestCNF(t *testing.T) {
+	model, vars := binaries(t, 3)
+	x, y, z := vars[0], vars[1], vars[2]
+
+	// (x or y) and (not x or y) and (not y or z) and (not x or not z)
+	_, err := AddCNF(model, [][]Literal{
+		{Pos(x), Pos(y)},
+		{Not(x), Pos(y)},
+		{Not(y), Pos(z)},
+		{Not(x), Not(z)},
+	})
+	require.NoError(t, err)
+
+	res, err := model.SolveFeasible()
+	require.NoError(t, err)
+	assert.False(t, Pos(x).Value(res))
+	assert.True(t, Pos(y).Value(res))
+	assert.True(t, Pos(z).Value(res))
+	assert.True(t, Not(x).Value(res))
+
+	_, err = AddImplication(model, Pos(z), Pos(x))
+	require.NoError(t, err)
+	_, err = model.SolveFeasible()
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+}
+
+func TestPseudoBoolean(t *testing.T) {
+	model, vars := binaries(t, 3)
+	x, y, z := vars[0], vars[1], vars[2]
+
+	// 2x + 3(not y) + z >= 5 and exactly one of x, y, z
+	_, err := AddPseudoBoolean(model, []float64{2, 3, 1}, []Literal{Pos(x), Not(y), Pos(z)}, 5, 10)
+	require.NoError(t, err)
+	_, err = AddExactlyOne(model, Pos(x), Pos(y), Pos(z))
+	require.NoError(t, err)
+
+	// only x = 1 gives 2 + 3 = 5, z = 1 gives 3 + 1 = 4
+	res, err := model.SolveFeasible()
+	require.NoError(t, err)
+	assert.True(t, Pos(x).Value(res))
+	assert.False(t, Pos(y).Value(res))
+	assert.False(t, Pos(z).Value(res))
+
+	_, err = AddAtMostOne(model, Pos(x), Not(z))
+	require.NoError(t, err)
+	_, err = model.SolveFeasible()
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+
+	c, err := model.AddDefinedVariable("c", golpa.ContinuousVariable, 0, 0, 1)
+	require.NoError(t, err)
+	_, err = AddClause(model, Pos(c))
+	assert.Error(t, err)
+	_, err = AddPseudoBoolean(model, []float64{1}, nil, 0, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+// scaling holds the factors applied by ScaleVariable and ScaleConstraint.
+// It is never modified once created, so results can keep the one in effect
+// when they were solved.
+type scaling struct {
+	// keyed by index, so that copies of the model, which have their own
+	// variables and constraints, can share it
+	cols map[int]float64
+	rows map[int]float64
+}
+
+// col returns the factor of the given variable, which is 1 if it was not
+// scaled.
+func (s *scaling) col(v *Variable) float64 {
+	if s == nil {
+		return 1
+	}
+	if f, ok := s.cols[v.index]; ok {
+		return f
+	}
+	return 1
+}
+
+// row returns the factor of the given constraint, which is 1 if it was not
+// scaled.
+func (s *scaling) row(c *Constraint) float64 {
+	if s == nil {
+		return 1
+	}
+	if f, ok := s.rows[c.index]; ok {
+		return f
+	}
+	return 1
+}
+
+// with returns a copy of the scaling, with the given factor applied to
+// either the variable or the constraint.
+func (s *scaling) with(v *Variable, c *Constraint, factor float64) *scaling {
+	out := s.renumber(nil, nil)
+	if out == nil {
+		out = &scaling{
+			cols: make(map[int]float64),
+			rows: make(map[int]float64),
+		}
+	}
+	if v != nil {
+		out.cols[v.index] = s.col(v) * factor
+	}
+	if c != nil {
+		out.rows[c.index] = s.row(c) * factor
+	}
+	return out
+}
+
+// renumber returns a copy of the scaling after variables or constraints were
+// removed. col and row map old indices to new ones, or to -1 for removed
+// ones; a nil function keeps the indices unchanged.
+func (s *scaling) renumber(col, row func(int) int) *scaling {
+	if s == nil {
+		return nil
+	}
+	out := &scaling{
+		cols: make(map[int]float64, len(s.cols)),
+		rows: make(map[int]float64, len(s.rows)),
+	}
+	for i, f := range s.cols {
+		if col != nil {
+			i = col(i)
+		}
+		if i >= 0 {
+			out.cols[i] = f
+		}
+	}
+	for i, f := range s.rows {
+		if row != nil {
+			i = row(i)
+		}
+		if i >= 0 {
+			out.rows[i] = f
+		}
+	}
+	return out
+}
+
+// checkScale returns an error unless the factor can be used for scaling.
+func checkScale(factor float64) error {
+	if factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return fmt.Errorf("scaling factor must be positive and finite, got %g", factor)
+	}
+	return nil
+}
+
+// ScaleConstraint multiplies the constraint's coefficients and bounds by the
+// given positive factor, which can improve the numerical behavior of models
+// whose constraints have coefficients of very different magnitudes.
+//
+// Scaling does not change the solutions of the model, and results take the
+// factor into account: ShadowPrice returns the dual value of the original
+// constraint. The constraint's Terms and Bounds, on the other hand, are
+// reported in scaled form, and new bounds or coefficients, including those
+// set through bound parameters, must be given in scaled form too.
+func (model *Model) ScaleConstraint(c *Constraint, factor float64) error {
+	if err := checkScale(factor); err != nil {
+		return err
+	}
+
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if c.model != model || c.index < 0 {
+		return fmt.Errorf("constraint does not belong to model")
+	}
+
+	row := c.index + 1
+	vars, coefs := model.rowTerms(row)
+	for i, v := range vars {
+		C.set_mat(model.prob, C.int(row), C.int(v.index+1), C.REAL(coefs[i]*factor))
+	}
+	lower, upper := model.rowBounds(row)
+	model.setRowBounds(row, lower*factor, upper*factor)
+
+	model.scaling = model.scaling.with(nil, c, factor)
+
+	return nil
+}
+
+// ScaleVariable replaces the variable by its value divided by the given
+// positive factor, multiplying its coefficients in the constraints and
+// objective functions by the factor and dividing its bounds by it. This can
+// improve the numerical behavior of models whose variables have values of
+// very different magnitudes. Only continuous variables can be scaled.
+//
+// Scaling does not change the solutions of the model, and results take the
+// factor into account: Value and DualValue return the value and reduced cost
+// of the original variable. The variable's Bounds and coefficients, on the
+// other hand, are reported in scaled form, and new bounds or coefficients,
+// including those set through bound parameters, must be given in scaled form
+// too.
+func (model *Model) ScaleVariable(v *Variable, factor float64) error {
+	if err := checkScale(factor); err != nil {
+		return err
+	}
+
+	model.syncSpool()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if v.model != model || v.index < 0 {
+		return fmt.Errorf("variable does not belong to model")
+	}
+	col := v.index + 1
+	if model.colType(col) != ContinuousVariable {
+		return fmt.Errorf("variable %q is not continuous", model.colName(col))
+	}
+
+	// the column includes the objective function as row 0
+	n := len(model.cons) + 1
+	vals := make([]C.REAL, n)
+	rows := make([]C.int, n)
+	count := int(C.get_columnex(model.prob, C.int(col), &vals[0], &rows[0]))
+	for i := 0; i < count; i++ {
+		C.set_mat(model.prob, rows[i], C.int(col), vals[i]*C.REAL(factor))
+	}
+	for _, obj := range model.objectives {
+		for i, w := range obj.vars {
+			if w == v {
+				obj.coefs[i] *= factor
+			}
+		}
+	}
+	lower, upper := model.colBounds(col)
+	model.setColBounds(col, lower/factor, upper/factor)
+
+	model.scaling = model.scaling.with(v, nil, factor)
+
+	return nil
+}
+
+// scaledValue returns the value of the variable as it is in the model, i.e.
+// not taking its scaling factor into account.
+func (res SolveResult) scaledValue(v *Variable) float64 {
+	return res.PrimalValue(v) / res.scaling.col(v)
+}
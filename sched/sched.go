@@ -0,0 +1,369 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package sched runs golpa solves as prioritized jobs on a bounded number
+// of workers.
+//
+// Jobs with a higher priority run first; among equal priorities, jobs with
+// an earlier deadline, and then those submitted earlier, run first. When a
+// job is waiting for a worker and a job with lower priority is running, the
+// running job is aborted to make room. If it already found an integer
+// solution, it ends with StatusPreempted and that solution; otherwise, since
+// lp_solve cannot resume an aborted search, it is queued again and solved
+// from scratch later.
+//
+// Jobs can belong to tenants, whose concurrency and solver time can be
+// limited with Scheduler.SetTenantLimits, so one tenant's jobs cannot keep
+// all workers busy.
+package sched
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/costela/golpa"
+)
+
+// JobStatus is the state of a job.
+type JobStatus int
+
+const (
+	// StatusQueued jobs are waiting for a worker.
+	StatusQueued JobStatus = iota
+	// StatusRunning jobs are being solved.
+	StatusRunning
+	// StatusDone jobs were solved; see Job.Wait for the outcome.
+	StatusDone
+	// StatusPreempted jobs were stopped for a job with higher priority, after
+	// finding a possibly suboptimal solution.
+	StatusPreempted
+	// StatusFailed jobs ended with an error, including cancellation and
+	// missed deadlines.
+	StatusFailed
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case StatusQueued:
+		return "queued"
+	case StatusRunning:
+		return "running"
+	case StatusDone:
+		return "done"
+	case StatusPreempted:
+		return "preempted"
+	case StatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// JobSpec describes a solve to be run by a Scheduler.
+type JobSpec struct {
+	Model    *golpa.Model
+	Priority int
+	// Tenant the job belongs to, for TenantLimits.
+	Tenant string
+	// Deadline, if not zero, is when the job is aborted: it then ends with
+	// the best solution found so far, or fails with
+	// context.DeadlineExceeded.
+	Deadline time.Time
+	Options  []golpa.SolveOption
+}
+
+// Job is a solve submitted to a Scheduler.
+type Job struct {
+	sched *Scheduler
+	spec  JobSpec
+	seq   uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// guarded by the scheduler's lock
+	status     JobStatus
+	index      int // in the queue
+	started    time.Time
+	abortRun   context.CancelFunc
+	preempting bool
+
+	done chan struct{}
+	res  *golpa.SolveResult
+	err  error
+}
+
+// Status returns the job's current state.
+func (j *Job) Status() JobStatus {
+	select {
+	case <-j.done:
+		return j.status
+	default:
+	}
+
+	j.sched.mu.Lock()
+	defer j.sched.mu.Unlock()
+	return j.status
+}
+
+// Spec returns the job's description.
+func (j *Job) Spec() JobSpec {
+	return j.spec
+}
+
+// Cancel aborts the job, whether queued or running. A running job ends with
+// the best solution found so far, if any. Canceling a finished job has no
+// effect.
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// Done returns a channel which is closed once the job is finished.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait waits for the job to finish and returns its outcome, like
+// golpa.Model.Solve.
+func (j *Job) Wait() (*golpa.SolveResult, error) {
+	<-j.done
+	return j.res, j.err
+}
+
+// Scheduler runs jobs on a bounded number of workers.
+type Scheduler struct {
+	mu      sync.Mutex
+	workers int
+	seq     uint64
+	queue   jobQueue
+	running map[*Job]bool
+	tenants map[string]*tenant
+}
+
+// New returns a scheduler running at most workers jobs at a time.
+func New(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Scheduler{
+		workers: workers,
+		running: make(map[*Job]bool),
+		tenants: make(map[string]*tenant),
+	}
+}
+
+// Submit queues a job and returns it. The job starts as soon as a worker is
+// available, possibly by preempting a job with lower priority.
+func (s *Scheduler) Submit(spec JobSpec) *Job {
+	j := &Job{
+		sched: s,
+		spec:  spec,
+		done:  make(chan struct{}),
+	}
+	if spec.Deadline.IsZero() {
+		j.ctx, j.cancel = context.WithCancel(context.Background())
+	} else {
+		j.ctx, j.cancel = context.WithDeadline(context.Background(), spec.Deadline)
+	}
+
+	s.mu.Lock()
+	s.seq++
+	j.seq = s.seq
+	heap.Push(&s.queue, j)
+	s.dispatch()
+	s.mu.Unlock()
+
+	// drop jobs which are canceled or expire while queued
+	go func() {
+		select {
+		case <-j.ctx.Done():
+		case <-j.done:
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if j.status == StatusQueued {
+			heap.Remove(&s.queue, j.index)
+			s.finish(j, StatusFailed, nil, j.ctx.Err())
+		}
+	}()
+
+	return j
+}
+
+// Queued returns the number of jobs waiting for a worker.
+func (s *Scheduler) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.Len()
+}
+
+// Running returns the number of jobs being solved.
+func (s *Scheduler) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.running)
+}
+
+// dispatch starts queued jobs within their tenant's limits while workers
+// are available, and otherwise preempts a running job with lower priority
+// than the next job which could start.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) dispatch() {
+	now := time.Now()
+
+	var skipped []*Job
+	defer func() {
+		for _, j := range skipped {
+			heap.Push(&s.queue, j)
+		}
+	}()
+
+	for s.queue.Len() > 0 {
+		j := heap.Pop(&s.queue).(*Job)
+		if !s.tenant(j).admits(now) {
+			skipped = append(skipped, j)
+			continue
+		}
+		if len(s.running) < s.workers {
+			s.start(j, now)
+			continue
+		}
+
+		skipped = append(skipped, j)
+		s.preemptFor(j)
+		return
+	}
+}
+
+// preemptFor aborts the running job with the lowest priority, if it is
+// lower than next's and no other job is being preempted already.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) preemptFor(next *Job) {
+	var victim *Job
+	for j := range s.running {
+		if j.preempting {
+			return
+		}
+		if j.spec.Priority >= next.spec.Priority {
+			continue
+		}
+		if victim == nil || j.spec.Priority < victim.spec.Priority ||
+			j.spec.Priority == victim.spec.Priority && j.seq > victim.seq {
+			victim = j
+		}
+	}
+	if victim != nil {
+		victim.preempting = true
+		victim.abortRun()
+	}
+}
+
+// start runs the job in a new goroutine.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) start(j *Job, now time.Time) {
+	t := s.tenant(j)
+	ctx, abort := context.WithCancel(j.ctx)
+	if budget, ok := t.remaining(); ok {
+		ctx, abort = context.WithTimeout(j.ctx, budget)
+	}
+	j.status = StatusRunning
+	j.started = now
+	j.abortRun = abort
+	t.running++
+	s.running[j] = true
+
+	go func() {
+		defer abort()
+		res, err := j.spec.Model.SolveWithContext(ctx, j.spec.Options...)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.running, j)
+		t.running--
+		t.charge(j, res)
+		if errors.Is(err, context.DeadlineExceeded) && j.ctx.Err() == nil {
+			err = ErrQuotaExceeded
+		}
+
+		switch {
+		case j.preempting && j.ctx.Err() == nil && err == nil:
+			s.finish(j, StatusPreempted, res, nil)
+		case j.preempting && j.ctx.Err() == nil:
+			j.status = StatusQueued
+			j.preempting = false
+			heap.Push(&s.queue, j)
+		case err != nil:
+			s.finish(j, StatusFailed, res, err)
+		default:
+			s.finish(j, StatusDone, res, nil)
+		}
+
+		s.dispatch()
+	}()
+}
+
+// finish records the job's outcome.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) finish(j *Job, status JobStatus, res *golpa.SolveResult, err error) {
+	j.status = status
+	j.res, j.err = res, err
+	j.cancel()
+	close(j.done)
+}
+
+// jobQueue is a heap of jobs, ordered by decreasing priority, then by
+// deadline and submission.
+type jobQueue []*Job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(a, b int) bool {
+	ja, jb := q[a], q[b]
+	if ja.spec.Priority != jb.spec.Priority {
+		return ja.spec.Priority > jb.spec.Priority
+	}
+	da, db := ja.spec.Deadline, jb.spec.Deadline
+	if !da.Equal(db) {
+		return db.IsZero() || !da.IsZero() && da.Before(db)
+	}
+	return ja.seq < jb.seq
+}
+
+func (q jobQueue) Swap(a, b int) {
+	q[a], q[b] = q[b], q[a]
+	q[a].index = a
+	q[b].index = b
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	j := x.(*Job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	j := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	j.index = -1
+	return j
+}
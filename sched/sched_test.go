@@ -0,0 +1,190 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package sched
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+// knapsack returns a small knapsack model and its optimal objective value.
+func knapsack(t *testing.T, capacity float64) *golpa.Model {
+	model, err := golpa.NewModel("knapsack", golpa.Maximize)
+	require.NoError(t, err)
+
+	var vars []*golpa.Variable
+	var weights []float64
+	for i := 1; i <= 8; i++ {
+		v, err := model.AddDefinedVariable("", golpa.BinaryVariable, float64(i*i%7+1), 0, 1)
+		require.NoError(t, err)
+		vars = append(vars, v)
+		weights = append(weights, float64(i))
+	}
+	_, err = model.AddConstraint(math.Inf(-1), capacity, vars, weights)
+	require.NoError(t, err)
+
+	return model
+}
+
+// occupy marks the scheduler's workers as busy with a job of the given
+// priority, returning a channel receiving a value if the job is preempted.
+func occupy(s *Scheduler, priority int) <-chan struct{} {
+	aborted := make(chan struct{}, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < s.workers; i++ {
+		s.running[&Job{
+			spec:     JobSpec{Priority: priority},
+			status:   StatusRunning,
+			abortRun: func() { aborted <- struct{}{} },
+		}] = true
+	}
+	return aborted
+}
+
+func TestSchedulerRunsJobs(t *testing.T) {
+	s := New(2)
+
+	var jobs []*Job
+	for capacity := 5.0; capacity <= 20; capacity += 5 {
+		jobs = append(jobs, s.Submit(JobSpec{Model: knapsack(t, capacity)}))
+	}
+
+	for i, j := range jobs {
+		res, err := j.Wait()
+		require.NoError(t, err)
+		assert.Equal(t, StatusDone, j.Status())
+
+		want, err := knapsack(t, 5*float64(i+1)).Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, want.ObjectiveValue(), res.ObjectiveValue(), delta)
+	}
+	assert.Equal(t, 0, s.Running())
+	assert.Equal(t, 0, s.Queued())
+}
+
+func TestSchedulerQueuedJobs(t *testing.T) {
+	s := New(1)
+	occupy(s, 10)
+
+	canceled := s.Submit(JobSpec{Model: knapsack(t, 10)})
+	expiring := s.Submit(JobSpec{Model: knapsack(t, 10), Deadline: time.Now().Add(10 * time.Millisecond)})
+	assert.Equal(t, StatusQueued, canceled.Status())
+	assert.Equal(t, 2, s.Queued())
+
+	canceled.Cancel()
+	_, err := canceled.Wait()
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StatusFailed, canceled.Status())
+
+	_, err = expiring.Wait()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, s.Queued())
+}
+
+func TestSchedulerPreemption(t *testing.T) {
+	s := New(1)
+	aborted := occupy(s, 0)
+
+	s.Submit(JobSpec{Model: knapsack(t, 10), Priority: 0})
+	select {
+	case <-aborted:
+		t.Fatal("job with equal priority preempted")
+	default:
+	}
+
+	s.Submit(JobSpec{Model: knapsack(t, 10), Priority: 1})
+	select {
+	case <-aborted:
+	default:
+		t.Fatal("job with lower priority not preempted")
+	}
+}
+
+func TestJobQueueOrder(t *testing.T) {
+	now := time.Now()
+	jobs := []*Job{
+		{spec: JobSpec{Priority: 0}, seq: 1},
+		{spec: JobSpec{Priority: 1, Deadline: now.Add(time.Hour)}, seq: 2},
+		{spec: JobSpec{Priority: 1}, seq: 3},
+		{spec: JobSpec{Priority: 1, Deadline: now.Add(time.Minute)}, seq: 4},
+		{spec: JobSpec{Priority: 0}, seq: 5},
+	}
+
+	var q jobQueue
+	for _, j := range jobs {
+		heap.Push(&q, j)
+	}
+
+	var order []uint64
+	for q.Len() > 0 {
+		order = append(order, heap.Pop(&q).(*Job).seq)
+	}
+	assert.Equal(t, []uint64{4, 2, 3, 1, 5}, order)
+}
+
+func TestTenantMaxRunning(t *testing.T) {
+	s := New(2)
+	s.SetTenantLimits("a", TenantLimits{MaxRunning: 1})
+	s.mu.Lock()
+	s.tenantNamed("a").running = 1
+	s.mu.Unlock()
+
+	limited := s.Submit(JobSpec{Model: knapsack(t, 10), Tenant: "a"})
+	other := s.Submit(JobSpec{Model: knapsack(t, 10), Tenant: "b"})
+	_, err := other.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, limited.Status())
+
+	s.mu.Lock()
+	s.tenantNamed("a").running = 0
+	s.dispatch()
+	s.mu.Unlock()
+
+	_, err = limited.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, StatusDone, limited.Status())
+}
+
+func TestTenantCPUTime(t *testing.T) {
+	s := New(1)
+	s.SetTenantLimits("a", TenantLimits{CPUTime: time.Hour, Period: 50 * time.Millisecond})
+	s.mu.Lock()
+	s.tenantNamed("a").used = time.Hour
+	s.mu.Unlock()
+
+	j := s.Submit(JobSpec{Model: knapsack(t, 10), Tenant: "a"})
+	assert.Equal(t, StatusQueued, j.Status())
+
+	// the job starts once the next period begins
+	_, err := j.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, StatusDone, j.Status())
+
+	used, running := s.TenantUsage("a")
+	assert.Less(t, used, time.Hour)
+	assert.Equal(t, 0, running)
+}
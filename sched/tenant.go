@@ -0,0 +1,158 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sched
+
+import (
+	"errors"
+	"time"
+
+	"github.com/costela/golpa"
+)
+
+// ErrQuotaExceeded is returned for jobs aborted because their tenant used up
+// its solver time.
+var ErrQuotaExceeded = errors.New("tenant solver time quota exceeded")
+
+// TenantLimits restricts the resources used by the jobs of one tenant.
+type TenantLimits struct {
+	// MaxRunning is the maximum number of the tenant's jobs running at
+	// once, or 0 for no limit.
+	MaxRunning int
+
+	// CPUTime is the solver time the tenant's jobs may use per Period, or 0
+	// for no limit. Once it is used up, the tenant's jobs stay queued until
+	// the next period starts.
+	//
+	// Since lp_solve runs single-threaded, a job never uses more CPU time
+	// than the time elapsed since it started, so running jobs are aborted
+	// once the elapsed time reaches the tenant's remaining budget, like at
+	// their deadline but failing with ErrQuotaExceeded if no solution was
+	// found. Jobs running at the same time are each given the whole
+	// remaining budget, so it can be exceeded by up to MaxRunning times.
+	CPUTime time.Duration
+	Period  time.Duration
+}
+
+// tenant tracks the resources used by a tenant's jobs.
+type tenant struct {
+	sched   *Scheduler
+	limits  TenantLimits
+	running int
+
+	used        time.Duration
+	periodStart time.Time
+	timer       *time.Timer
+}
+
+// SetTenantLimits sets the limits of the given tenant's jobs, which also
+// apply to jobs already queued. Usage in the current period is kept.
+func (s *Scheduler) SetTenantLimits(tenant string, limits TenantLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tenantNamed(tenant)
+	t.limits = limits
+	s.dispatch()
+}
+
+// TenantUsage returns the solver time used by the given tenant's finished
+// jobs in the current period, and the number of its jobs running.
+func (s *Scheduler) TenantUsage(tenant string) (used time.Duration, running int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tenantNamed(tenant)
+	t.rollPeriod(time.Now())
+	return t.used, t.running
+}
+
+// tenant returns the tenant of the given job.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) tenant(j *Job) *tenant {
+	return s.tenantNamed(j.spec.Tenant)
+}
+
+// tenantNamed returns the tenant with the given name, creating it if needed.
+// The caller must hold the scheduler's lock.
+func (s *Scheduler) tenantNamed(name string) *tenant {
+	t := s.tenants[name]
+	if t == nil {
+		t = &tenant{sched: s, periodStart: time.Now()}
+		s.tenants[name] = t
+	}
+	return t
+}
+
+// rollPeriod starts a new period, if the current one is over.
+func (t *tenant) rollPeriod(now time.Time) {
+	if t.limits.Period > 0 && now.Sub(t.periodStart) >= t.limits.Period {
+		t.used = 0
+		t.periodStart = now
+	}
+}
+
+// admits reports whether another of the tenant's jobs may start. If not
+// because the budget is used up, the scheduler is woken up again when the
+// next period starts.
+func (t *tenant) admits(now time.Time) bool {
+	if t.limits.MaxRunning > 0 && t.running >= t.limits.MaxRunning {
+		return false
+	}
+
+	t.rollPeriod(now)
+	if _, ok := t.remaining(); ok && t.used >= t.limits.CPUTime {
+		if t.timer == nil && t.limits.Period > 0 {
+			t.timer = time.AfterFunc(t.periodStart.Add(t.limits.Period).Sub(now), func() {
+				t.sched.mu.Lock()
+				defer t.sched.mu.Unlock()
+				t.timer = nil
+				t.sched.dispatch()
+			})
+		}
+		return false
+	}
+
+	return true
+}
+
+// remaining returns the tenant's remaining solver time, and false if it is
+// unlimited.
+func (t *tenant) remaining() (time.Duration, bool) {
+	if t.limits.CPUTime <= 0 {
+		return 0, false
+	}
+	return t.limits.CPUTime - t.used, true
+}
+
+// charge adds the solver time used by the finished job. Where the solver's
+// CPU time is not measured, the elapsed time is charged instead.
+func (t *tenant) charge(j *Job, res *golpa.SolveResult) {
+	used := time.Since(j.started)
+	if res != nil {
+		if cpu := res.Stats().CPUTime; cpu > 0 {
+			used = cpu
+		}
+	}
+	if j.started.Before(t.periodStart) {
+		// the job started in a previous period
+		if elapsed := time.Since(t.periodStart); used > elapsed {
+			used = elapsed
+		}
+	}
+	t.used += used
+}
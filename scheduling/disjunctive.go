@@ -0,0 +1,127 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package scheduling provides constraint generators for scheduling models
+// built with golpa.
+//
+// Jobs are represented by their start time variables. Two formulations are
+// supported: disjunctive constraints between pairs of jobs, using big-M
+// values derived from the jobs' time windows, and time-indexed formulations
+// with one binary variable per job and start period.
+package scheduling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Job is a job with a fixed duration and a variable start time.
+type Job struct {
+	Start    *golpa.Variable
+	Duration float64
+}
+
+// AddJob adds a job to the model, with a continuous start time variable
+// bounded so that the job starts no earlier than release and ends no later
+// than deadline.
+func AddJob(model *golpa.Model, name string, duration, release, deadline float64) (*Job, error) {
+	if deadline-duration < release {
+		return nil, fmt.Errorf("job %q does not fit into its time window [%g, %g]", name, release, deadline)
+	}
+
+	start, err := model.AddDefinedVariable(name, golpa.ContinuousVariable, 0, release, deadline-duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		Start:    start,
+		Duration: duration,
+	}, nil
+}
+
+// StartValue returns the start time of the job in a solution.
+func (j *Job) StartValue(res *golpa.SolveResult) float64 {
+	return res.Value(j.Start)
+}
+
+// EndValue returns the end time of the job in a solution.
+func (j *Job) EndValue(res *golpa.SolveResult) float64 {
+	return res.Value(j.Start) + j.Duration
+}
+
+// AddPrecedence requires job a to end before job b starts.
+func AddPrecedence(model *golpa.Model, a, b *Job) (*golpa.Constraint, error) {
+	return model.AddConstraint(math.Inf(-1), -a.Duration, []*golpa.Variable{a.Start, b.Start}, []float64{1, -1})
+}
+
+// AddDisjunction requires jobs a and b not to overlap, i.e. either a ends
+// before b starts or b ends before a starts. It returns the binary variable
+// choosing the order, which is 1 if a comes first.
+//
+// The big-M values are derived from the bounds of the start time variables,
+// which must therefore be finite.
+func AddDisjunction(model *golpa.Model, a, b *Job) (*golpa.Variable, error) {
+	aLow, aHigh := a.Start.Bounds()
+	bLow, bHigh := b.Start.Bounds()
+	for _, bound := range []float64{aLow, aHigh, bLow, bHigh} {
+		if math.IsInf(bound, 0) {
+			return nil, fmt.Errorf("disjunction between %q and %q requires finite start time bounds", a.Start.Name(), b.Start.Name())
+		}
+	}
+
+	order, err := model.AddDefinedVariable(fmt.Sprintf("%s_before_%s", a.Start.Name(), b.Start.Name()), golpa.BinaryVariable, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// largest possible violation of each ordering, used to relax it when the
+	// other one is chosen
+	bigA := math.Max(0, aHigh+a.Duration-bLow)
+	bigB := math.Max(0, bHigh+b.Duration-aLow)
+
+	// order = 1: a.Start + a.Duration <= b.Start
+	if _, err := model.AddConstraint(math.Inf(-1), bigA-a.Duration, []*golpa.Variable{a.Start, b.Start, order}, []float64{1, -1, bigA}); err != nil {
+		return nil, err
+	}
+
+	// order = 0: b.Start + b.Duration <= a.Start
+	if _, err := model.AddConstraint(math.Inf(-1), -b.Duration, []*golpa.Variable{b.Start, a.Start, order}, []float64{1, -1, -bigB}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// AddMakespan adds a variable bounding the end time of all given jobs from
+// above. Minimizing it minimizes the makespan of the schedule.
+func AddMakespan(model *golpa.Model, jobs []*Job) (*golpa.Variable, error) {
+	makespan, err := model.AddDefinedVariable("makespan", golpa.ContinuousVariable, 0, 0, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, j := range jobs {
+		if _, err := model.AddConstraint(math.Inf(-1), -j.Duration, []*golpa.Variable{j.Start, makespan}, []float64{1, -1}); err != nil {
+			return nil, err
+		}
+	}
+
+	return makespan, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package scheduling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestDisjunction(t *testing.T) {
+	model, err := golpa.NewModel("jobs", golpa.Minimize)
+	require.NoError(t, err)
+
+	a, err := AddJob(model, "a", 3, 0, 10)
+	require.NoError(t, err)
+	b, err := AddJob(model, "b", 2, 1, 10)
+	require.NoError(t, err)
+
+	order, err := AddDisjunction(model, a, b)
+	require.NoError(t, err)
+
+	makespan, err := AddMakespan(model, []*Job{a, b})
+	require.NoError(t, err)
+	makespan.SetObjectiveCoefficient(1)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	assert.InDelta(t, 5, res.ObjectiveValue(), delta)
+	assert.True(t, a.EndValue(res) <= b.StartValue(res)+delta || b.EndValue(res) <= a.StartValue(res)+delta)
+	assert.InDelta(t, 1, res.Value(order), delta, "b can't start first without idling")
+}
+
+func TestPrecedence(t *testing.T) {
+	model, err := golpa.NewModel("jobs", golpa.Minimize)
+	require.NoError(t, err)
+
+	a, err := AddJob(model, "a", 3, 0, 10)
+	require.NoError(t, err)
+	b, err := AddJob(model, "b", 2, 0, 10)
+	require.NoError(t, err)
+	b.Start.SetObjectiveCoefficient(1)
+
+	_, err = AddPrecedence(model, a, b)
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, b.StartValue(res), delta)
+}
+
+func TestDisjunctionUnbounded(t *testing.T) {
+	model, err := golpa.NewModel("jobs", golpa.Minimize)
+	require.NoError(t, err)
+
+	a, err := AddJob(model, "a", 3, 0, math.Inf(1))
+	require.NoError(t, err)
+	b, err := AddJob(model, "b", 2, 0, 10)
+	require.NoError(t, err)
+
+	_, err = AddDisjunction(model, a, b)
+	assert.Error(t, err)
+}
+
+func TestTimeIndexed(t *testing.T) {
+	model, err := golpa.NewModel("jobs", golpa.Minimize)
+	require.NoError(t, err)
+
+	ti := NewTimeIndexed(model, 10)
+	var jobs []*Job
+	for i, duration := range []int{2, 1, 2} {
+		j, err := ti.AddJob(string(rune('a'+i)), duration, 0, 0)
+		require.NoError(t, err)
+		jobs = append(jobs, j)
+	}
+	require.NoError(t, ti.AddCapacity(1, jobs))
+
+	makespan, err := AddMakespan(model, jobs)
+	require.NoError(t, err)
+	makespan.SetObjectiveCoefficient(1)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 5, res.ObjectiveValue(), delta)
+
+	for i, a := range jobs {
+		start := a.StartValue(res)
+		assert.InDelta(t, 1, res.Value(ti.StartVariable(a, int(math.Round(start)))), delta)
+		for _, b := range jobs[i+1:] {
+			assert.True(t, a.EndValue(res) <= b.StartValue(res)+delta || b.EndValue(res) <= a.StartValue(res)+delta)
+		}
+	}
+
+	_, err = ti.AddJob("long", 11, 0, 0)
+	assert.Error(t, err)
+}
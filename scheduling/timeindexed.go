@@ -0,0 +1,140 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// TimeIndexed builds time-indexed scheduling formulations over a discrete
+// horizon of periods 0 to horizon-1.
+type TimeIndexed struct {
+	model   *golpa.Model
+	horizon int
+	starts  map[*Job][]*golpa.Variable
+}
+
+// NewTimeIndexed returns a time-indexed formulation builder for the given
+// model and horizon.
+func NewTimeIndexed(model *golpa.Model, horizon int) *TimeIndexed {
+	return &TimeIndexed{
+		model:   model,
+		horizon: horizon,
+		starts:  make(map[*Job][]*golpa.Variable),
+	}
+}
+
+// AddJob adds a job with the given duration in periods, which must start no
+// earlier than release and end no later than deadline (or the horizon, if
+// deadline is not positive).
+//
+// One binary variable is added for each possible start period, along with a
+// continuous start time variable equal to the chosen period, so the job can
+// also be used in precedence constraints.
+func (ti *TimeIndexed) AddJob(name string, duration, release, deadline int) (*Job, error) {
+	if deadline <= 0 || deadline > ti.horizon {
+		deadline = ti.horizon
+	}
+	if release < 0 {
+		release = 0
+	}
+	last := deadline - duration
+	if last < release {
+		return nil, fmt.Errorf("job %q does not fit into its time window [%d, %d]", name, release, deadline)
+	}
+
+	start, err := ti.model.AddDefinedVariable(name, golpa.ContinuousVariable, 0, float64(release), float64(last))
+	if err != nil {
+		return nil, err
+	}
+
+	starts := make([]*golpa.Variable, ti.horizon)
+	vars := []*golpa.Variable{start}
+	coefs := []float64{-1}
+	var ones []float64
+	for t := release; t <= last; t++ {
+		if starts[t], err = ti.model.AddDefinedVariable(fmt.Sprintf("%s_%d", name, t), golpa.BinaryVariable, 0, 0, 1); err != nil {
+			return nil, err
+		}
+		vars = append(vars, starts[t])
+		coefs = append(coefs, float64(t))
+		ones = append(ones, 1)
+	}
+
+	if _, err := ti.model.AddConstraint(1, 1, vars[1:], ones); err != nil {
+		return nil, err
+	}
+	if _, err := ti.model.AddConstraint(0, 0, vars, coefs); err != nil {
+		return nil, err
+	}
+
+	j := &Job{
+		Start:    start,
+		Duration: float64(duration),
+	}
+	ti.starts[j] = starts
+
+	return j, nil
+}
+
+// StartVariable returns the binary variable deciding whether the job starts
+// in the given period, or nil if the job can't start then.
+func (ti *TimeIndexed) StartVariable(j *Job, period int) *golpa.Variable {
+	starts := ti.starts[j]
+	if period < 0 || period >= len(starts) {
+		return nil
+	}
+	return starts[period]
+}
+
+// AddCapacity limits the number of the given jobs running in each period to
+// capacity. With a capacity of 1, the jobs are scheduled on a single machine.
+func (ti *TimeIndexed) AddCapacity(capacity float64, jobs []*Job) error {
+	for period := 0; period < ti.horizon; period++ {
+		var vars []*golpa.Variable
+		for _, j := range jobs {
+			starts, ok := ti.starts[j]
+			if !ok {
+				return fmt.Errorf("job %q is not time-indexed", j.Start.Name())
+			}
+			// the job runs in this period if it started in one of the last
+			// Duration periods
+			for t := period - int(j.Duration) + 1; t <= period; t++ {
+				if t >= 0 && starts[t] != nil {
+					vars = append(vars, starts[t])
+				}
+			}
+		}
+		if len(vars) == 0 {
+			continue
+		}
+
+		coefs := make([]float64, len(vars))
+		for i := range coefs {
+			coefs[i] = 1
+		}
+		if _, err := ti.model.AddConstraint(math.Inf(-1), capacity, vars, coefs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
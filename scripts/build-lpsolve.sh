@@ -0,0 +1,39 @@
+#!/bin/sh
+# Builds lp_solve from source as a static library into third_party/lpsolve,
+# for use with the golpa_static build tag:
+#
+#   $ ./scripts/build-lpsolve.sh
+#   $ go build -tags golpa_static ./...
+#
+# An already unpacked lp_solve source tree can be given as the first
+# argument; otherwise the source release LPSOLVE_VERSION is downloaded.
+
+set -eu
+
+LPSOLVE_VERSION=${LPSOLVE_VERSION:-5.5.2.11}
+
+root=$(cd "$(dirname "$0")/.." && pwd)
+dest=$root/third_party/lpsolve
+
+work=$(mktemp -d)
+trap 'rm -rf "$work"' EXIT
+
+if [ $# -gt 0 ]; then
+	src=$(cd "$1" && pwd)
+else
+	curl -fsSL -o "$work/lp_solve.tar.gz" \
+		"https://sourceforge.net/projects/lpsolve/files/lpsolve/$LPSOLVE_VERSION/lp_solve_${LPSOLVE_VERSION}_source.tar.gz/download"
+	tar -xzf "$work/lp_solve.tar.gz" -C "$work"
+	src=$work/lp_solve_5.5
+fi
+
+# lp_solve's own build script produces liblpsolve55.a next to the shared
+# library, including colamd
+(cd "$src/lpsolve55" && sh ccc)
+
+rm -rf "$dest"
+mkdir -p "$dest/include" "$dest/lib"
+cp "$src"/*.h "$dest/include/"
+cp "$src"/lpsolve55/bin/*/liblpsolve55.a "$dest/lib/"
+
+echo "lp_solve $LPSOLVE_VERSION installed in $dest"
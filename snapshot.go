@@ -0,0 +1,127 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import "fmt"
+
+// Snapshot records the state of a model's variables and constraints, to be
+// restored later with Restore, e.g. to undo changes. Unlike a clone, it
+// only holds a copy of the model's data, not of the solver's state.
+type Snapshot struct {
+	model *Model
+	vars  []*Variable
+	cons  []*Constraint
+	data  *modelData
+}
+
+// Snapshot records the model's variables and constraints, with their
+// bounds, types and coefficients.
+func (model *Model) Snapshot() *Snapshot {
+	data := model.readData()
+
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	return &Snapshot{
+		model: model,
+		vars:  append([]*Variable(nil), model.vars...),
+		cons:  append([]*Constraint(nil), model.cons...),
+		data:  data,
+	}
+}
+
+// Restore returns the model to the state recorded in the snapshot: variables
+// and constraints added since are removed, and the bounds, types and
+// coefficients of the remaining ones are reset. Variables and constraints
+// removed since the snapshot was taken cannot be restored, in which case an
+// error is returned and the model is left unchanged.
+func (model *Model) Restore(snap *Snapshot) error {
+	if snap.model != model {
+		return fmt.Errorf("snapshot was not taken of model %q", model.Name())
+	}
+
+	kept := make(map[interface{}]bool, len(snap.vars)+len(snap.cons))
+	for _, v := range snap.vars {
+		if v.index < 0 {
+			return fmt.Errorf("variable was removed after the snapshot was taken")
+		}
+		kept[v] = true
+	}
+	for _, c := range snap.cons {
+		if c.index < 0 {
+			return fmt.Errorf("constraint was removed after the snapshot was taken")
+		}
+		kept[c] = true
+	}
+
+	model.syncSpool()
+
+	// removing from the end keeps the recorded indices valid
+	cons := model.Constraints()
+	for i := len(cons) - 1; i >= 0; i-- {
+		if !kept[cons[i]] {
+			if err := model.RemoveConstraint(cons[i]); err != nil {
+				return err
+			}
+		}
+	}
+	vars := model.Variables()
+	for i := len(vars) - 1; i >= 0; i-- {
+		if !kept[vars[i]] {
+			if err := model.RemoveVariable(vars[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, v := range snap.vars {
+		d := snap.data.vars[i]
+		v.SetType(d.typ)
+		v.SetBounds(d.lower, d.upper)
+		v.SetObjectiveCoefficient(d.obj)
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	for i, c := range snap.cons {
+		d := snap.data.cons[i]
+		row := make([]C.REAL, len(d.cols))
+		colno := make([]C.int, len(d.cols))
+		for j, col := range d.cols {
+			row[j] = C.REAL(d.coefs[j])
+			colno[j] = C.int(col + 1)
+		}
+
+		var (
+			rowPtr   *C.REAL
+			colnoPtr *C.int
+		)
+		if len(row) > 0 {
+			rowPtr, colnoPtr = &row[0], &colno[0]
+		}
+		C.set_rowex(model.prob, C.int(c.index+1), C.int(len(row)), rowPtr, colnoPtr)
+		model.setRowBounds(c.index+1, d.lower, d.upper)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// constraintSpool buffers constraint rows in a temporary file instead of
+// handing them to the underlying library right away. This keeps the memory
+// footprint of model generation low for very large models: each row only
+// lives in Go memory while it is being written or loaded.
+//
+// Rows are stored as a sequence of records with the following layout
+// (little endian):
+//
+//	lower   float64
+//	upper   float64
+//	count   uint32
+//	count × (column uint32, coefficient float64)
+type constraintSpool struct {
+	file    *os.File
+	w       *bufio.Writer
+	pending int
+}
+
+func newConstraintSpool(dir string) (*constraintSpool, error) {
+	f, err := os.CreateTemp(dir, "golpa-spool-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool file: %w", err)
+	}
+
+	return &constraintSpool{
+		file: f,
+		w:    bufio.NewWriter(f),
+	}, nil
+}
+
+// write appends a single row to the spool.
+func (s *constraintSpool) write(lower, upper float64, vars []*Variable, coefs []float64) error {
+	var (
+		buf [8]byte
+		err error
+	)
+	writeUint32 := func(v uint32) {
+		if err == nil {
+			binary.LittleEndian.PutUint32(buf[:4], v)
+			_, err = s.w.Write(buf[:4])
+		}
+	}
+	writeFloat64 := func(f float64) {
+		if err == nil {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+			_, err = s.w.Write(buf[:])
+		}
+	}
+
+	writeFloat64(lower)
+	writeFloat64(upper)
+	writeUint32(uint32(len(vars)))
+	for i, v := range vars {
+		writeUint32(uint32(v.index))
+		writeFloat64(coefs[i])
+	}
+	if err != nil {
+		return fmt.Errorf("writing to spool file: %w", err)
+	}
+
+	s.pending++
+
+	return nil
+}
+
+// load replays all pending rows into the given model and resets the spool.
+// The caller must hold the model's write lock.
+func (s *constraintSpool) load(model *Model) error {
+	if s.pending == 0 {
+		return nil
+	}
+
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("flushing spool file: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding spool file: %w", err)
+	}
+
+	r := bufio.NewReader(s.file)
+
+	var (
+		buf   [8]byte
+		row   []C.REAL
+		colno []C.int
+	)
+
+	readUint32 := func() (uint32, error) {
+		_, err := io.ReadFull(r, buf[:4])
+		return binary.LittleEndian.Uint32(buf[:4]), err
+	}
+	readFloat64 := func() (float64, error) {
+		_, err := io.ReadFull(r, buf[:])
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf[:])), err
+	}
+
+	for i := 0; i < s.pending; i++ {
+		lower, err := readFloat64()
+		if err != nil {
+			return fmt.Errorf("reading spooled row %d: %w", i, err)
+		}
+		upper, err := readFloat64()
+		if err != nil {
+			return fmt.Errorf("reading spooled row %d: %w", i, err)
+		}
+		count, err := readUint32()
+		if err != nil {
+			return fmt.Errorf("reading spooled row %d: %w", i, err)
+		}
+
+		row, colno = row[:0], colno[:0]
+		for j := uint32(0); j < count; j++ {
+			col, err := readUint32()
+			if err != nil {
+				return fmt.Errorf("reading spooled row %d: %w", i, err)
+			}
+			coef, err := readFloat64()
+			if err != nil {
+				return fmt.Errorf("reading spooled row %d: %w", i, err)
+			}
+			colno = append(colno, C.int(col+1))
+			row = append(row, C.REAL(coef))
+		}
+
+		if err := model.addRow(lower, upper, row, colno); err != nil {
+			return err
+		}
+	}
+
+	return s.reset()
+}
+
+func (s *constraintSpool) reset() error {
+	s.pending = 0
+
+	if err := s.file.Truncate(0); err != nil {
+		return fmt.Errorf("truncating spool file: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding spool file: %w", err)
+	}
+	s.w.Reset(s.file)
+
+	return nil
+}
+
+// close releases the spool file. Pending rows are discarded.
+func (s *constraintSpool) close() {
+	s.file.Close()
+	os.Remove(s.file.Name())
+}
+
+// loadSpooledConstraints moves any spooled constraints into the underlying
+// library. Since some rows may have been loaded when it fails, the error is
+// kept and returned by all later calls, including those made by Solve. The
+// caller must hold the model's write lock.
+func (model *Model) loadSpooledConstraints() error {
+	if model.spoolErr != nil {
+		return model.spoolErr
+	}
+	if model.spool == nil {
+		return nil
+	}
+
+	if err := model.spool.load(model); err != nil {
+		model.spoolErr = fmt.Errorf("loading spooled constraints: %w", err)
+		return model.spoolErr
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddStabilityPenalty augments the objective function with penalties for
+// changing variables from their values in a previous solution: for each
+// variable in penalties, the absolute difference between its value and its
+// value in prev is added to the objective, scaled by the given penalty, so
+// that re-optimizing prefers solutions close to the previous one.
+//
+// prev must be the result of the latest solve of this model or of a model
+// with the same variables, such as a clone. One auxiliary variable and two
+// constraints are added for each penalized variable.
+func (model *Model) AddStabilityPenalty(prev *SolveResult, penalties map[*Variable]float64) error {
+	for v, penalty := range penalties {
+		if v.model != model {
+			return fmt.Errorf("variable does not belong to model")
+		}
+		if penalty < 0 {
+			return fmt.Errorf("negative penalty %g for %q", penalty, v.Name())
+		}
+	}
+
+	// read all previous values before the model changes
+	type penalized struct {
+		v       *Variable
+		value   float64
+		penalty float64
+	}
+	// the constraints refer to scaled variables, so the values and
+	// penalties are scaled too
+	model.mu.RLock()
+	scaling := model.scaling
+	model.mu.RUnlock()
+	var terms []penalized
+	for _, v := range model.Variables() {
+		if penalty := penalties[v]; penalty != 0 {
+			terms = append(terms, penalized{v, prev.scaledValue(v), penalty * scaling.col(v)})
+		}
+	}
+
+	sign := 1.0
+	if model.Direction() == Maximize {
+		sign = -1
+	}
+
+	for _, t := range terms {
+		// d >= |v - value|, which is tight at the optimum since d is
+		// penalized in the objective
+		d, err := model.AddDefinedVariable("", ContinuousVariable, sign*t.penalty, 0, math.Inf(1))
+		if err != nil {
+			return err
+		}
+		if _, err := model.AddConstraint(-t.value, math.Inf(1), []*Variable{d, t.v}, []float64{1, -1}); err != nil {
+			return err
+		}
+		if _, err := model.AddConstraint(t.value, math.Inf(1), []*Variable{d, t.v}, []float64{1, 1}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
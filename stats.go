@@ -0,0 +1,67 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"runtime"
+	"time"
+)
+
+// Stats holds the resources used by a solve, e.g. for accounting in
+// services solving models for several users.
+type Stats struct {
+	// WallTime is the total time spent in Solve.
+	WallTime time.Duration
+	// CPUTime is the CPU time spent by the solver. It is only measured on
+	// Linux.
+	CPUTime time.Duration
+	// PeakRSS is the peak resident set size of the whole process in bytes,
+	// as of the end of the solve. It is only measured on Linux.
+	PeakRSS int64
+	// Iterations is the number of simplex iterations.
+	Iterations int64
+	// Nodes is the number of branch-and-bound nodes explored.
+	Nodes int64
+}
+
+// Stats returns the resources used by the solve which produced this result.
+func (res SolveResult) Stats() Stats {
+	stats := res.stats
+	stats.Iterations = res.iterations.Iterations
+	stats.Nodes = res.iterations.Nodes
+	return stats
+}
+
+// measure runs solve on a locked OS thread, so the CPU time spent by the
+// solver can be attributed, and records its resource usage in stats.
+func measure(stats *Stats, solve func() C.int) C.int {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	cpuStart, _ := resourceUsage()
+	ret := solve()
+	cpuEnd, peakRSS := resourceUsage()
+
+	stats.CPUTime = cpuEnd - cpuStart
+	stats.PeakRSS = peakRSS
+	return ret
+}
@@ -0,0 +1,186 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// SecondStageFunc adds the second-stage variables and constraints for a
+// single scenario to the deterministic equivalent model. firstStage holds the
+// scenario's copies of the first-stage variables, in the same order as
+// returned by the first-stage model's Variables.
+// Objective coefficients should be given as if the scenario was certain;
+// they are weighted by the scenario's probability automatically.
+type SecondStageFunc func(model *Model, firstStage []*Variable) error
+
+// Stochastic describes a two-stage stochastic program: first-stage
+// decisions, which have to be taken before knowing which scenario occurs,
+// and per-scenario second-stage decisions.
+type Stochastic struct {
+	firstStage *Model
+	scenarios  []scenario
+}
+
+type scenario struct {
+	name        string
+	probability float64
+	build       SecondStageFunc
+}
+
+// DeterministicEquivalent is the single model equivalent to a two-stage
+// stochastic program, as built by Stochastic.DeterministicEquivalent.
+type DeterministicEquivalent struct {
+	Model *Model
+	// FirstStage holds each scenario's copies of the first-stage variables.
+	// The nonanticipativity constraints force them to be equal.
+	FirstStage [][]*Variable
+
+	firstStageModel *Model
+}
+
+// StochasticResult is the result of solving a two-stage stochastic program.
+// Second-stage variables' values can be queried directly.
+type StochasticResult struct {
+	*SolveResult
+	de *DeterministicEquivalent
+}
+
+// NewStochastic creates a two-stage stochastic program with the given
+// first-stage model, whose variables, constraints, objective function and
+// direction are used as the first stage.
+func NewStochastic(firstStage *Model) *Stochastic {
+	return &Stochastic{firstStage: firstStage}
+}
+
+// AddScenario adds a scenario with the given probability. The probabilities
+// of all scenarios must add up to 1.
+func (s *Stochastic) AddScenario(name string, probability float64, secondStage SecondStageFunc) {
+	s.scenarios = append(s.scenarios, scenario{
+		name:        name,
+		probability: probability,
+		build:       secondStage,
+	})
+}
+
+// DeterministicEquivalent builds a single model equivalent to the stochastic
+// program: it contains a copy of the first stage for each scenario, linked by
+// nonanticipativity constraints, and each scenario's second stage. Variables
+// copied from the first stage are suffixed with "[scenario name]". The
+// objective is the expected value over all scenarios.
+func (s *Stochastic) DeterministicEquivalent() (*DeterministicEquivalent, error) {
+	if len(s.scenarios) == 0 {
+		return nil, fmt.Errorf("stochastic program has no scenarios")
+	}
+
+	var total float64
+	for _, sc := range s.scenarios {
+		if sc.probability < 0 {
+			return nil, fmt.Errorf("scenario %s has negative probability %g", sc.name, sc.probability)
+		}
+		total += sc.probability
+	}
+	if math.Abs(total-1) > 1e-9 {
+		return nil, fmt.Errorf("scenario probabilities add up to %g instead of 1", total)
+	}
+
+	data := s.firstStage.readData()
+
+	dir := Minimize
+	if data.maximize {
+		dir = Maximize
+	}
+
+	model, err := NewModel(data.name, dir, WithLogger(s.firstStage.logger))
+	if err != nil {
+		return nil, err
+	}
+
+	de := &DeterministicEquivalent{
+		Model:           model,
+		FirstStage:      make([][]*Variable, len(s.scenarios)),
+		firstStageModel: s.firstStage,
+	}
+
+	for k, sc := range s.scenarios {
+		vars := make([]*Variable, len(data.vars))
+		for i, v := range data.vars {
+			vars[i], err = model.AddDefinedVariable(fmt.Sprintf("%s[%s]", v.name, sc.name), v.typ, sc.probability*v.obj, v.lower, v.upper)
+			if err != nil {
+				return nil, err
+			}
+		}
+		de.FirstStage[k] = vars
+
+		for _, c := range data.cons {
+			rowVars := make([]*Variable, len(c.cols))
+			for i, col := range c.cols {
+				rowVars[i] = vars[col]
+			}
+			if _, err := model.AddConstraint(c.lower, c.upper, rowVars, c.coefs); err != nil {
+				return nil, err
+			}
+		}
+
+		if k > 0 {
+			for i, v := range vars {
+				na, err := model.AddConstraint(0, 0, []*Variable{v, de.FirstStage[0][i]}, []float64{1, -1})
+				if err != nil {
+					return nil, err
+				}
+				na.SetName(fmt.Sprintf("nonanticipativity_%s[%s]", data.vars[i].name, sc.name))
+			}
+		}
+
+		firstNew := model.VariableCount()
+		if err := sc.build(model, vars); err != nil {
+			return nil, fmt.Errorf("building scenario %s: %w", sc.name, err)
+		}
+
+		for _, v := range model.Variables()[firstNew:] {
+			v.SetObjectiveCoefficient(sc.probability * v.Coefficient())
+		}
+	}
+
+	return de, nil
+}
+
+// Solve builds the deterministic equivalent of the stochastic program and
+// solves it.
+func (s *Stochastic) Solve() (*StochasticResult, error) {
+	de, err := s.DeterministicEquivalent()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := de.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StochasticResult{
+		SolveResult: res,
+		de:          de,
+	}, nil
+}
+
+// FirstStageValue returns the value of a variable of the first-stage model.
+func (res *StochasticResult) FirstStageValue(v *Variable) float64 {
+	return res.Value(res.de.FirstStage[0][v.index])
+}
@@ -0,0 +1,216 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package subproc solves golpa models in a child process, so a crash or
+// runaway memory use in lp_solve cannot take down the calling process, and
+// resource limits can be applied to the solver with the usual OS tools.
+//
+// The model is passed to the child in golpa's binary format through a
+// temporary file, and the result comes back as written by
+// golpa.SolveResult.WriteTo. By default, the child is the running
+// executable itself, which must call Main at the start of its main
+// function:
+//
+//	func main() {
+//		subproc.Main()
+//		// ⋮
+//	}
+package subproc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/costela/golpa"
+)
+
+// modelEnv is the environment variable passing the model's path to the
+// child process.
+const modelEnv = "GOLPA_SUBPROC_MODEL"
+
+// maxStderr is the amount of the child's standard error included in errors.
+const maxStderr = 4096
+
+// header precedes the result in the child's output.
+type header struct {
+	// Error is the golpa.SolveError returned by the solve, or 0.
+	Error int `json:"error,omitempty"`
+	// Message describes other errors.
+	Message string `json:"message,omitempty"`
+}
+
+// Solver runs solves in child processes.
+type Solver struct {
+	// Command returns the command running the child process, which must
+	// call Main with modelEnv set to modelPath. If nil, the running
+	// executable is started again. Wrapping the command, e.g. with
+	// prlimit or systemd-run, allows limiting the child's resources.
+	Command func(ctx context.Context, modelPath string) (*exec.Cmd, error)
+}
+
+// Solve solves the model in a child process and returns its result, which
+// provides the status, objective value and variable values (see
+// golpa.ReadResult). Only the model's own objective function is solved,
+// with default options. If ctx is done, the child process is killed.
+//
+// Errors of the solve itself, like golpa.ErrModelInfeasible, are returned
+// as in golpa.Model.Solve; if the child process fails, the error includes
+// its standard error output.
+func (s Solver) Solve(ctx context.Context, model *golpa.Model) (*golpa.SolveResult, error) {
+	f, err := os.CreateTemp("", "golpa-*.bin")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	err = model.WriteBinary(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	command := s.Command
+	if command == nil {
+		command = self
+	}
+	cmd, err := command(ctx, f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	stderr := &limitedBuffer{max: maxStderr}
+	cmd.Stdout, cmd.Stderr = &stdout, stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("solver process: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	dec := json.NewDecoder(&stdout)
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("solver process: reading output: %w", err)
+	}
+	if h.Message != "" {
+		return nil, fmt.Errorf("solver process: %s", h.Message)
+	}
+
+	var solveErr error
+	if h.Error != 0 {
+		solveErr = golpa.SolveError(h.Error)
+	}
+	res, err := golpa.ReadResult(io.MultiReader(dec.Buffered(), &stdout), model)
+	if errors.Is(err, io.EOF) {
+		// the solve failed without a result
+		return nil, solveErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("solver process: reading result: %w", err)
+	}
+
+	return res, solveErr
+}
+
+// Solve solves the model in a child process of the running executable. See
+// Solver.Solve.
+func Solve(ctx context.Context, model *golpa.Model) (*golpa.SolveResult, error) {
+	return Solver{}.Solve(ctx, model)
+}
+
+// self starts the running executable again as child process.
+func self(ctx context.Context, modelPath string) (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Env = append(os.Environ(), modelEnv+"="+modelPath)
+	return cmd, nil
+}
+
+// Main runs the child side of a solve and exits if the process was started
+// by Solver.Solve, and returns immediately otherwise.
+func Main() {
+	path := os.Getenv(modelEnv)
+	if path == "" {
+		return
+	}
+
+	if err := serve(path, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// serve solves the model at path and writes the header and result to w.
+func serve(path string, w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	model, err := golpa.OpenBinary(path)
+	if err != nil {
+		return enc.Encode(header{Message: err.Error()})
+	}
+
+	res, err := model.Solve()
+	var h header
+	var solveErr golpa.SolveError
+	switch {
+	case errors.As(err, &solveErr):
+		h.Error = int(solveErr)
+	case err != nil:
+		h.Message = err.Error()
+	}
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+
+	if res == nil {
+		return nil
+	}
+	_, err = res.WriteTo(w)
+	return err
+}
+
+// limitedBuffer keeps the first max bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package subproc
+
+import (
+	"context"
+	"math"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+func TestSolve(t *testing.T) {
+	model, err := golpa.NewModel("subproc", golpa.Maximize)
+	require.NoError(t, err)
+
+	x, err := model.AddDefinedVariable("x", golpa.IntegerVariable, 3, 0, 10)
+	require.NoError(t, err)
+	y, err := model.AddDefinedVariable("y", golpa.ContinuousVariable, 2, 0, 10)
+	require.NoError(t, err)
+	_, err = model.AddConstraint(math.Inf(-1), 7.5, []*golpa.Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+
+	res, err := Solve(context.Background(), model)
+	require.NoError(t, err)
+	assert.Equal(t, golpa.SolutionOptimal, res.Status())
+	assert.InDelta(t, 7, res.Value(x), delta)
+	assert.InDelta(t, 0.5, res.Value(y), delta)
+	assert.InDelta(t, 22, res.ObjectiveValue(), delta)
+
+	_, err = model.AddConstraint(8, math.Inf(1), []*golpa.Variable{x, y}, []float64{1, 1})
+	require.NoError(t, err)
+	res, err = Solve(context.Background(), model)
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+	require.NotNil(t, res)
+	assert.Equal(t, golpa.SolutionInfeasible, res.Status())
+}
+
+func TestSolveCrash(t *testing.T) {
+	model, err := golpa.NewModel("crash", golpa.Minimize)
+	require.NoError(t, err)
+
+	s := Solver{Command: func(ctx context.Context, modelPath string) (*exec.Cmd, error) {
+		return exec.CommandContext(ctx, "sh", "-c", "echo boom >&2; exit 3"), nil
+	}}
+	_, err = s.Solve(context.Background(), model)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SymmetryGroup is a set of interchangeable variables found by
+// BreakSymmetry, along with the ordering constraints added for them.
+type SymmetryGroup struct {
+	Variables   []*Variable
+	Constraints []*Constraint
+}
+
+// BreakSymmetry finds groups of interchangeable variables, i.e. variables
+// with identical columns: the same type, bounds and objective coefficients,
+// including those of named objectives, and the same coefficients in every
+// constraint. Swapping the values of such variables yields another solution
+// with the same objective value, which makes branch-and-bound explore many
+// equivalent branches, e.g. in models with identical machines or vehicles.
+//
+// For each group x1, ..., xk, in the order the variables were added, the
+// constraints x1 >= x2 >= ... >= xk are added to the model, which keep one
+// of every set of equivalent solutions. The constraints can be removed again
+// with RemoveConstraint.
+func (model *Model) BreakSymmetry() ([]SymmetryGroup, error) {
+	data := model.readData()
+	vars := model.Variables()
+
+	model.mu.RLock()
+	names := make([]string, 0, len(model.objectives))
+	for name := range model.objectives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	objectives := make([][]float64, len(names))
+	for i, name := range names {
+		objectives[i] = model.objectives[name].row(len(vars))
+	}
+	model.mu.RUnlock()
+
+	type entry struct {
+		row  int
+		coef float64
+	}
+	columns := make([][]entry, len(data.vars))
+	for i, c := range data.cons {
+		for j, col := range c.cols {
+			columns[col] = append(columns[col], entry{i, c.coefs[j]})
+		}
+	}
+
+	var keys []string
+	groups := make(map[string][]*Variable)
+	for col, v := range data.vars {
+		var b strings.Builder
+		b.WriteString(strconv.Itoa(int(v.typ)))
+		for _, f := range []float64{v.lower, v.upper, v.obj} {
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		for _, obj := range objectives {
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(obj[col], 'g', -1, 64))
+		}
+
+		// a row may contain several terms of the same variable
+		sort.Slice(columns[col], func(i, j int) bool {
+			return columns[col][i].row < columns[col][j].row
+		})
+		for i := 0; i < len(columns[col]); i++ {
+			e := columns[col][i]
+			for i+1 < len(columns[col]) && columns[col][i+1].row == e.row {
+				i++
+				e.coef += columns[col][i].coef
+			}
+			if e.coef == 0 {
+				continue
+			}
+			b.WriteString(" " + strconv.Itoa(e.row) + ":" + strconv.FormatFloat(e.coef, 'g', -1, 64))
+		}
+
+		key := b.String()
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], vars[col])
+	}
+
+	var symmetric []SymmetryGroup
+	for _, key := range keys {
+		group := SymmetryGroup{Variables: groups[key]}
+		if len(group.Variables) < 2 {
+			continue
+		}
+		for i := 1; i < len(group.Variables); i++ {
+			c, err := model.AddConstraint(0, math.Inf(1), group.Variables[i-1:i+1], []float64{1, -1})
+			if err != nil {
+				return symmetric, err
+			}
+			group.Constraints = append(group.Constraints, c)
+		}
+		symmetric = append(symmetric, group)
+	}
+
+	return symmetric, nil
+}
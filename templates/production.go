@@ -0,0 +1,281 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package templates provides configurable builders for common planning
+// models, returning golpa models along with typed accessors for their
+// decision variables and solutions. Besides being usable as they are, they
+// show how larger models are put together with golpa.
+package templates
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/costela/golpa"
+)
+
+// Product is a product of a production plan.
+type Product struct {
+	Name string
+	// Demand is the amount to deliver in each period.
+	Demand []float64
+	// InitialInventory is the amount in stock before the first period.
+	InitialInventory float64
+
+	// ProductionCost is the cost per unit produced.
+	ProductionCost float64
+	// HoldingCost is the cost per unit kept in stock at the end of a
+	// period.
+	HoldingCost float64
+	// BacklogCost is the cost per unit of demand delivered late, per period
+	// of delay. Demand can only be delivered late if it is positive.
+	BacklogCost float64
+
+	// Usage is the amount of each resource used per unit produced,
+	// indexed like Production.Resources.
+	Usage []float64
+	// Integer restricts production to whole units.
+	Integer bool
+}
+
+// Resource is a resource shared by the products, such as a machine's time.
+type Resource struct {
+	Name string
+	// Capacity is the amount available in each period.
+	Capacity []float64
+}
+
+// Production configures a multi-period production and inventory plan.
+type Production struct {
+	Periods   int
+	Products  []Product
+	Resources []Resource
+}
+
+// ProductionPlan is a production planning model: decide how much of each
+// product to produce in each period, meeting the demands within the
+// resources' capacities at minimum production, holding and backlog cost.
+type ProductionPlan struct {
+	// Model is the underlying model. Further constraints may be added to it
+	// before solving.
+	Model *golpa.Model
+
+	produce   [][]*golpa.Variable
+	inventory [][]*golpa.Variable
+	backlog   [][]*golpa.Variable
+	balance   [][]*golpa.Constraint
+}
+
+// BuildProductionPlan builds the model of a production plan. For each
+// product p and period t, the inventory balance
+//
+//	inventory[p][t-1] - backlog[p][t-1] + produce[p][t] = Demand[t] + inventory[p][t] - backlog[p][t]
+//
+// links production, inventory and backlog, and for each resource r the
+// production of all products in period t uses at most Capacity[t].
+// Demand still backlogged at the end of the last period is never
+// delivered.
+func BuildProductionPlan(cfg Production) (*ProductionPlan, error) {
+	if cfg.Periods < 1 {
+		return nil, fmt.Errorf("need at least one period, got %d", cfg.Periods)
+	}
+	for _, r := range cfg.Resources {
+		if len(r.Capacity) != cfg.Periods {
+			return nil, fmt.Errorf("resource %q has capacities for %d periods, want %d", r.Name, len(r.Capacity), cfg.Periods)
+		}
+	}
+
+	model, err := golpa.NewModel("production", golpa.Minimize)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ProductionPlan{
+		Model:     model,
+		produce:   make([][]*golpa.Variable, len(cfg.Products)),
+		inventory: make([][]*golpa.Variable, len(cfg.Products)),
+		backlog:   make([][]*golpa.Variable, len(cfg.Products)),
+		balance:   make([][]*golpa.Constraint, len(cfg.Products)),
+	}
+
+	usage := make([][]*golpa.Variable, cfg.Periods)
+	usageCoefs := make([][][]float64, cfg.Periods)
+	for t := range usage {
+		usageCoefs[t] = make([][]float64, len(cfg.Resources))
+	}
+
+	for p, product := range cfg.Products {
+		if err := checkProduct(product, cfg); err != nil {
+			return nil, err
+		}
+
+		typ := golpa.ContinuousVariable
+		if product.Integer {
+			typ = golpa.IntegerVariable
+		}
+		backlogUpper := 0.0
+		if product.BacklogCost > 0 {
+			backlogUpper = math.Inf(1)
+		}
+
+		plan.produce[p] = make([]*golpa.Variable, cfg.Periods)
+		plan.inventory[p] = make([]*golpa.Variable, cfg.Periods)
+		plan.backlog[p] = make([]*golpa.Variable, cfg.Periods)
+		plan.balance[p] = make([]*golpa.Constraint, cfg.Periods)
+		for t := 0; t < cfg.Periods; t++ {
+			produce, err := model.AddDefinedVariable(fmt.Sprintf("produce_%s_%d", product.Name, t), typ, product.ProductionCost, 0, math.Inf(1))
+			if err != nil {
+				return nil, err
+			}
+			inventory, err := model.AddDefinedVariable(fmt.Sprintf("inventory_%s_%d", product.Name, t), golpa.ContinuousVariable, product.HoldingCost, 0, math.Inf(1))
+			if err != nil {
+				return nil, err
+			}
+			backlog, err := model.AddDefinedVariable(fmt.Sprintf("backlog_%s_%d", product.Name, t), golpa.ContinuousVariable, product.BacklogCost, 0, backlogUpper)
+			if err != nil {
+				return nil, err
+			}
+			plan.produce[p][t] = produce
+			plan.inventory[p][t] = inventory
+			plan.backlog[p][t] = backlog
+
+			e := golpa.Term(1, produce).Plus(golpa.Term(-1, inventory)).Plus(golpa.Term(1, backlog))
+			if t == 0 {
+				e = e.PlusConstant(product.InitialInventory)
+			} else {
+				e = e.Plus(golpa.Term(1, plan.inventory[p][t-1])).Plus(golpa.Term(-1, plan.backlog[p][t-1]))
+			}
+			c, err := model.AddExprConstraint(product.Demand[t], product.Demand[t], e)
+			if err != nil {
+				return nil, err
+			}
+			c.SetName(fmt.Sprintf("balance_%s_%d", product.Name, t))
+			plan.balance[p][t] = c
+
+			usage[t] = append(usage[t], produce)
+			for r := range cfg.Resources {
+				amount := 0.0
+				if product.Usage != nil {
+					amount = product.Usage[r]
+				}
+				usageCoefs[t][r] = append(usageCoefs[t][r], amount)
+			}
+		}
+	}
+
+	for r, resource := range cfg.Resources {
+		for t := 0; t < cfg.Periods; t++ {
+			c, err := model.AddConstraint(math.Inf(-1), resource.Capacity[t], usage[t], usageCoefs[t][r])
+			if err != nil {
+				return nil, err
+			}
+			c.SetName(fmt.Sprintf("capacity_%s_%d", resource.Name, t))
+		}
+	}
+
+	return plan, nil
+}
+
+// checkProduct returns an error if the product's configuration doesn't fit
+// the plan's.
+func checkProduct(product Product, cfg Production) error {
+	if len(product.Demand) != cfg.Periods {
+		return fmt.Errorf("product %q has demands for %d periods, want %d", product.Name, len(product.Demand), cfg.Periods)
+	}
+	if product.Usage != nil && len(product.Usage) != len(cfg.Resources) {
+		return fmt.Errorf("product %q has usages for %d resources, want %d", product.Name, len(product.Usage), len(cfg.Resources))
+	}
+	if product.ProductionCost < 0 || product.HoldingCost < 0 || product.BacklogCost < 0 {
+		return fmt.Errorf("product %q has negative costs", product.Name)
+	}
+	return nil
+}
+
+// Produce returns the variable holding the amount of product p produced in
+// period t.
+func (plan *ProductionPlan) Produce(p, t int) *golpa.Variable {
+	return plan.produce[p][t]
+}
+
+// Inventory returns the variable holding the amount of product p in stock at
+// the end of period t.
+func (plan *ProductionPlan) Inventory(p, t int) *golpa.Variable {
+	return plan.inventory[p][t]
+}
+
+// Backlog returns the variable holding the demand for product p not yet
+// delivered at the end of period t.
+func (plan *ProductionPlan) Backlog(p, t int) *golpa.Variable {
+	return plan.backlog[p][t]
+}
+
+// Balance returns the inventory balance constraint of product p in period
+// t. Its shadow price is the marginal cost of one more unit of demand.
+func (plan *ProductionPlan) Balance(p, t int) *golpa.Constraint {
+	return plan.balance[p][t]
+}
+
+// Solve solves the production planning problem.
+func (plan *ProductionPlan) Solve() (*ProductionResult, error) {
+	res, err := plan.Model.Solve()
+	if err != nil {
+		return nil, err
+	}
+
+	values := func(vars [][]*golpa.Variable) [][]float64 {
+		out := make([][]float64, len(vars))
+		for p := range vars {
+			out[p] = make([]float64, len(vars[p]))
+			for t, v := range vars[p] {
+				out[p][t] = res.Value(v)
+			}
+		}
+		return out
+	}
+
+	return &ProductionResult{
+		SolveResult: res,
+		production:  values(plan.produce),
+		inventory:   values(plan.inventory),
+		backlog:     values(plan.backlog),
+	}, nil
+}
+
+// ProductionResult is the solution of a production planning problem.
+type ProductionResult struct {
+	*golpa.SolveResult
+
+	production, inventory, backlog [][]float64
+}
+
+// Production returns the amount of each product produced in each period,
+// indexed by product and period.
+func (res *ProductionResult) Production() [][]float64 {
+	return res.production
+}
+
+// Inventory returns the amount of each product in stock at the end of each
+// period, indexed by product and period.
+func (res *ProductionResult) Inventory() [][]float64 {
+	return res.inventory
+}
+
+// Backlog returns the demand for each product not yet delivered at the end
+// of each period, indexed by product and period.
+func (res *ProductionResult) Backlog() [][]float64 {
+	return res.backlog
+}
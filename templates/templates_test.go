@@ -0,0 +1,89 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package templates
+
+import (
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.0000001
+
+func TestProductionPlan(t *testing.T) {
+	cfg := Production{
+		Periods: 3,
+		Products: []Product{{
+			Name:           "widget",
+			Demand:         []float64{10, 40, 10},
+			ProductionCost: 1,
+			HoldingCost:    0.5,
+			BacklogCost:    5,
+			Usage:          []float64{2},
+			Integer:        true,
+		}},
+		Resources: []Resource{{Name: "machine", Capacity: []float64{40, 40, 40}}},
+	}
+
+	plan, err := BuildProductionPlan(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 9, plan.Model.VariableCount())
+	assert.Equal(t, 6, plan.Model.ConstraintCount())
+
+	// 20 units per period: 10 are stocked in the first period and 10
+	// delivered late in the second
+	res, err := plan.Solve()
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{20, 20, 20}, res.Production()[0], delta)
+	assert.InDeltaSlice(t, []float64{10, 0, 0}, res.Inventory()[0], delta)
+	assert.InDeltaSlice(t, []float64{0, 10, 0}, res.Backlog()[0], delta)
+	assert.InDelta(t, 60+0.5*10+5*10, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 20, res.Value(plan.Produce(0, 1)), delta)
+	assert.Equal(t, golpa.IntegerVariable, plan.Produce(0, 0).Type())
+
+	// without backlog, the second period's demand can't be met
+	cfg.Products[0].BacklogCost = 0
+	plan, err = BuildProductionPlan(cfg)
+	require.NoError(t, err)
+	_, err = plan.Solve()
+	assert.ErrorIs(t, err, golpa.ErrModelInfeasible)
+
+	// unless there's enough in stock
+	cfg.Products[0].InitialInventory = 10
+	plan, err = BuildProductionPlan(cfg)
+	require.NoError(t, err)
+	res, err = plan.Solve()
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{20, 20, 10}, res.Production()[0], delta)
+	assert.InDeltaSlice(t, []float64{20, 0, 0}, res.Inventory()[0], delta)
+	assert.InDelta(t, 50+0.5*20, res.ObjectiveValue(), delta)
+}
+
+func TestProductionPlanErrors(t *testing.T) {
+	for name, cfg := range map[string]Production{
+		"no periods":       {},
+		"demand periods":   {Periods: 2, Products: []Product{{Demand: []float64{1}}}},
+		"capacity periods": {Periods: 2, Resources: []Resource{{Capacity: []float64{1}}}},
+		"usages":           {Periods: 1, Products: []Product{{Demand: []float64{1}, Usage: []float64{1}}}},
+		"negative cost":    {Periods: 1, Products: []Product{{Demand: []float64{1}, HoldingCost: -1}}},
+	} {
+		_, err := BuildProductionPlan(cfg)
+		assert.Error(t, err, name)
+	}
+}
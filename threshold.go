@@ -0,0 +1,141 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// SolveObjectiveThreshold solves the feasibility question of whether a
+// solution with an objective value of at most target exists, or at least
+// target when maximizing. Like SolveFeasible, it stops at the first such
+// solution, which is usually much faster than optimizing. If there is none,
+// ErrModelInfeasible is returned.
+//
+// The model itself is not modified: the result refers to a copy of it, but
+// can be queried with the model's variables.
+func (model *Model) SolveObjectiveThreshold(target float64, opts ...SolveOption) (*SolveResult, error) {
+	t, err := model.newThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.solve(target, opts)
+}
+
+// BisectObjective finds the optimal objective value by bisection between
+// low and high, using SolveObjectiveThreshold, until the interval known to
+// contain it is at most tolerance wide, or cannot be split any further. low
+// and high must be finite and tolerance positive. It returns the best
+// solution found, or ErrModelInfeasible if there is no solution within the
+// interval.
+//
+// For models where finding a solution is much faster than proving its
+// optimality, this answers questions like "what is the lowest cost within
+// 100?" faster than Solve.
+func (model *Model) BisectObjective(low, high, tolerance float64, opts ...SolveOption) (*SolveResult, error) {
+	if math.IsInf(low, 0) || math.IsNaN(low) || math.IsInf(high, 0) || math.IsNaN(high) || low > high {
+		return nil, fmt.Errorf("invalid bisection interval [%g, %g]", low, high)
+	}
+	if !(tolerance > 0) {
+		return nil, fmt.Errorf("bisection tolerance must be positive, got %g", tolerance)
+	}
+
+	t, err := model.newThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// bisect on the side of the objective which is being improved
+	worst := high
+	if t.maximize {
+		worst = low
+	}
+	best, err := t.solve(worst, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if t.maximize {
+			low = math.Max(low, best.ObjectiveValue())
+		} else {
+			high = math.Min(high, best.ObjectiveValue())
+		}
+		if high-low <= tolerance {
+			return best, nil
+		}
+
+		mid := (low + high) / 2
+		if mid == low || mid == high {
+			// adjacent floats: the interval cannot shrink any further
+			return best, nil
+		}
+		res, err := t.solve(mid, opts)
+		switch {
+		case errors.Is(err, ErrModelInfeasible) && t.maximize:
+			high = mid
+		case errors.Is(err, ErrModelInfeasible):
+			low = mid
+		case err != nil:
+			return nil, err
+		default:
+			best = res
+		}
+	}
+}
+
+// threshold is a copy of a model with a constraint on its objective value.
+type threshold struct {
+	model    *Model
+	bound    *Constraint
+	maximize bool
+}
+
+// newThreshold returns a copy of the model for threshold solves in the
+// direction the given options solve it in.
+func (model *Model) newThreshold(opts []SolveOption) (*threshold, error) {
+	var o solveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dir := model.Direction()
+	if o.direction != nil {
+		dir = *o.direction
+	}
+
+	t := &threshold{
+		model:    model.Clone(),
+		maximize: dir == Maximize,
+	}
+	var err error
+	t.bound, err = t.model.ConstrainObjective(math.Inf(-1), math.Inf(1))
+	return t, err
+}
+
+// solve looks for a solution with an objective value of at most target, or
+// at least target when maximizing.
+func (t *threshold) solve(target float64, opts []SolveOption) (*SolveResult, error) {
+	if t.maximize {
+		t.bound.SetBounds(target, math.Inf(1))
+	} else {
+		t.bound.SetBounds(math.Inf(-1), target)
+	}
+	return t.model.SolveFeasible(opts...)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+//
+// extern void msgCallback(lprec *lp, void *userhandle, int msg);
+import "C"
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// Timings holds the time spent in each phase of a solve.
+type Timings struct {
+	// Build is the time spent preparing the model for the solver, e.g.
+	// loading spooled constraints.
+	Build time.Duration
+	// Presolve is the time spent in lp_solve's presolve, if enabled.
+	Presolve time.Duration
+	// Simplex is the time spent solving the LP relaxation.
+	Simplex time.Duration
+	// BranchAndBound is the time spent searching for integer solutions
+	// after the LP relaxation was solved.
+	BranchAndBound time.Duration
+	// Extract is the time spent copying the solution from the solver. It is
+	// only non-zero for results of SolveReusing, since results of Solve read
+	// values lazily.
+	Extract time.Duration
+}
+
+// Timings returns the time spent in each phase of the solve which produced
+// this result.
+func (res SolveResult) Timings() Timings {
+	return res.timings
+}
+
+// phaseRecorder records when the solver reports reaching solve phases.
+type phaseRecorder struct {
+	presolved, relaxed time.Time
+}
+
+//export msgCallback
+func msgCallback(prob *C.lprec, recPtr unsafe.Pointer, msg C.int) {
+	rec, ok := loadRef(recPtr).(*phaseRecorder)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	switch msg {
+	case C.MSG_PRESOLVE:
+		if rec.presolved.IsZero() {
+			rec.presolved = now
+		}
+	case C.MSG_LPOPTIMAL:
+		if rec.relaxed.IsZero() {
+			rec.relaxed = now
+		}
+	}
+}
+
+// solvePhases runs the solver, recording the time spent in each phase since
+// start. The solving goroutine is labeled with the model's name and size for
+// CPU profiles.
+// The caller must hold the model's write lock.
+func (model *Model) solvePhases(start time.Time, timings *Timings) (ret C.int) {
+	rec := &phaseRecorder{}
+	ref := saveRef(rec)
+	C.put_msgfunc(model.prob, (*C.lphandleint_func)(C.msgCallback), ref, C.MSG_PRESOLVE|C.MSG_LPOPTIMAL)
+	defer func() {
+		C.put_msgfunc(model.prob, nil, nil, 0)
+		releaseRef(ref)
+	}()
+
+	labels := pprof.Labels(
+		"golpa.model", C.GoString(C.get_lp_name(model.prob)),
+		"golpa.rows", strconv.Itoa(len(model.cons)),
+		"golpa.columns", strconv.Itoa(len(model.vars)),
+	)
+
+	solveStart := time.Now()
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		ret = C.solve(model.prob)
+	})
+	end := time.Now()
+
+	timings.Build = solveStart.Sub(start)
+	simplexStart := solveStart
+	if !rec.presolved.IsZero() {
+		timings.Presolve = rec.presolved.Sub(solveStart)
+		simplexStart = rec.presolved
+	}
+	if rec.relaxed.IsZero() {
+		timings.Simplex = end.Sub(simplexStart)
+	} else {
+		timings.Simplex = rec.relaxed.Sub(simplexStart)
+		timings.BranchAndBound = end.Sub(rec.relaxed)
+	}
+
+	return ret
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// UnboundedRay returns a direction in which the objective of the model's LP
+// relaxation improves indefinitely without violating any constraint or
+// bound. Only variables with a non-zero component are part of the returned
+// map. The components are scaled so the largest one has an absolute value of
+// 1.
+//
+// The variables in the ray are the ones "running away"; adding a constraint
+// or bound limiting any of them in the ray's direction removes this
+// particular source of unboundedness.
+//
+// It is only available for results returned along with ErrModelUnbounded.
+func (res SolveResult) UnboundedRay() (map[*Variable]float64, error) {
+	if res.status != SolutionUnbounded {
+		return nil, fmt.Errorf("unbounded ray is only available for unbounded results")
+	}
+
+	data := res.model.readData()
+
+	dir := Minimize
+	if data.maximize {
+		dir = Maximize
+	}
+
+	// Look for a direction within the recession cone of the feasible region
+	// which improves the objective, limited to a box so the search itself is
+	// bounded.
+	rayModel, err := NewModel(data.name, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]*Variable, len(data.vars))
+	for i, v := range data.vars {
+		lower, upper := -1.0, 1.0
+		if !math.IsInf(v.lower, 0) {
+			lower = 0
+		}
+		if !math.IsInf(v.upper, 0) {
+			upper = 0
+		}
+
+		vars[i], err = rayModel.AddDefinedVariable(v.name, ContinuousVariable, v.obj, lower, upper)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range data.cons {
+		lower, upper := math.Inf(-1), math.Inf(1)
+		if !math.IsInf(c.lower, 0) {
+			lower = 0
+		}
+		if !math.IsInf(c.upper, 0) {
+			upper = 0
+		}
+
+		rowVars := make([]*Variable, len(c.cols))
+		for j, col := range c.cols {
+			rowVars[j] = vars[col]
+		}
+
+		if _, err := rayModel.AddConstraint(lower, upper, rowVars, c.coefs); err != nil {
+			return nil, err
+		}
+	}
+
+	rayRes, err := rayModel.Solve()
+	if err != nil {
+		return nil, fmt.Errorf("solving ray model: %w", err)
+	}
+
+	if math.Abs(rayRes.ObjectiveValue()) <= certificateTolerance {
+		return nil, fmt.Errorf("LP relaxation is not unbounded")
+	}
+
+	var largest float64
+	for _, v := range vars {
+		largest = math.Max(largest, math.Abs(rayRes.Value(v)))
+	}
+
+	ray := make(map[*Variable]float64)
+	for i, v := range vars {
+		if d := rayRes.Value(v) / largest; math.Abs(d) > certificateTolerance {
+			ray[res.model.vars[i]] = d
+		}
+	}
+
+	return ray, nil
+}
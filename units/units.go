@@ -0,0 +1,348 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package units adds units of measurement to golpa models.
+//
+// Variables are declared with a unit and coefficients are given as
+// quantities, so each term of an expression has a unit as well. Adding
+// terms, or bounding an expression, with quantities of different dimensions
+// (e.g. mass and length) is reported as an error when the constraint is
+// added, while quantities of the same dimension (e.g. kilograms and tonnes)
+// are converted automatically.
+//
+// Internally, expressions are kept in the base unit of their dimension,
+// while variables are kept in their own unit, so solution values need no
+// conversion.
+package units
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/costela/golpa"
+)
+
+// Dimension is the kind of quantity a unit measures, as the exponents of
+// base dimensions, e.g. {"mass": 1} or {"money": 1, "mass": -1}. Base
+// dimensions with exponent zero are omitted, so dimensionless quantities
+// have an empty dimension.
+type Dimension map[string]int
+
+// Equal reports whether both dimensions are the same.
+func (d Dimension) Equal(other Dimension) bool {
+	if len(d) != len(other) {
+		return false
+	}
+	for base, exp := range d {
+		if other[base] != exp {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the dimension as a product of powers of base dimensions,
+// e.g. "mass^-1 money".
+func (d Dimension) String() string {
+	if len(d) == 0 {
+		return "dimensionless"
+	}
+	parts := make([]string, 0, len(d))
+	for base, exp := range d {
+		if exp == 1 {
+			parts = append(parts, base)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s^%d", base, exp))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// combine returns the dimension d * other^sign.
+func (d Dimension) combine(other Dimension, sign int) Dimension {
+	out := make(Dimension, len(d)+len(other))
+	for base, exp := range d {
+		out[base] = exp
+	}
+	for base, exp := range other {
+		out[base] += sign * exp
+		if out[base] == 0 {
+			delete(out, base)
+		}
+	}
+	return out
+}
+
+// Unit is a unit of measurement.
+type Unit interface {
+	// Dimension returns the kind of quantity measured by the unit.
+	Dimension() Dimension
+	// Factor returns the size of the unit in the base unit of its
+	// dimension, e.g. 1000 for tonnes if the base unit of mass is the
+	// kilogram.
+	Factor() float64
+	String() string
+}
+
+// unit is the Unit implementation of this package.
+type unit struct {
+	name   string
+	dim    Dimension
+	factor float64
+}
+
+func (u unit) Dimension() Dimension { return u.dim }
+func (u unit) Factor() float64      { return u.factor }
+func (u unit) String() string       { return u.name }
+
+// Base returns a new base unit of the given base dimension.
+func Base(name, dimension string) Unit {
+	return unit{name: name, dim: Dimension{dimension: 1}, factor: 1}
+}
+
+// Multiple returns a new unit of the same dimension as of, whose size is
+// factor units of of, e.g. Multiple("t", Kilogram, 1000).
+func Multiple(name string, of Unit, factor float64) Unit {
+	return unit{name: name, dim: of.Dimension(), factor: factor * of.Factor()}
+}
+
+// Times returns the product of both units, e.g. kWh from kW and h.
+func Times(a, b Unit) Unit {
+	return unit{
+		name:   a.String() + "*" + b.String(),
+		dim:    a.Dimension().combine(b.Dimension(), 1),
+		factor: a.Factor() * b.Factor(),
+	}
+}
+
+// Per returns the quotient of both units, e.g. EUR/kg from EUR and kg.
+func Per(a, b Unit) Unit {
+	return unit{
+		name:   a.String() + "/" + b.String(),
+		dim:    a.Dimension().combine(b.Dimension(), -1),
+		factor: a.Factor() / b.Factor(),
+	}
+}
+
+// Common units.
+var (
+	One = Unit(unit{name: "1", dim: Dimension{}, factor: 1})
+
+	Kilogram = Base("kg", "mass")
+	Gram     = Multiple("g", Kilogram, 0.001)
+	Tonne    = Multiple("t", Kilogram, 1000)
+
+	Meter     = Base("m", "length")
+	Kilometer = Multiple("km", Meter, 1000)
+
+	Second = Base("s", "time")
+	Minute = Multiple("min", Second, 60)
+	Hour   = Multiple("h", Second, 3600)
+
+	CubicMeter = Multiple("m3", Times(Meter, Times(Meter, Meter)), 1)
+	Liter      = Multiple("l", CubicMeter, 0.001)
+)
+
+// Quantity is an amount of some unit.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// Q returns the quantity value * unit.
+func Q(value float64, unit Unit) Quantity {
+	return Quantity{Value: value, Unit: unit}
+}
+
+// In returns the quantity converted to the given unit, which must be of the
+// same dimension.
+func (q Quantity) In(unit Unit) (float64, error) {
+	if !q.Unit.Dimension().Equal(unit.Dimension()) {
+		return 0, ErrDimensionMismatch{Want: unit.Dimension(), Got: q.Unit.Dimension()}
+	}
+	return q.Value * q.Unit.Factor() / unit.Factor(), nil
+}
+
+// String returns the quantity with its unit, e.g. "2.5 t".
+func (q Quantity) String() string {
+	return fmt.Sprintf("%g %s", q.Value, q.Unit)
+}
+
+// ErrDimensionMismatch is returned when quantities of different dimensions
+// are added or compared.
+type ErrDimensionMismatch struct {
+	Want, Got Dimension
+}
+
+func (e ErrDimensionMismatch) Error() string {
+	return fmt.Sprintf("dimension mismatch: expected %s, got %s", e.Want, e.Got)
+}
+
+// Variable is a model variable measured in some unit. Its values in
+// results are in that unit.
+type Variable struct {
+	*golpa.Variable
+	unit Unit
+}
+
+// Unit returns the unit of the variable.
+func (v *Variable) Unit() Unit {
+	return v.unit
+}
+
+// Value returns the variable's value in the given result.
+func (v *Variable) Value(res *golpa.SolveResult) Quantity {
+	return Q(res.Value(v.Variable), v.unit)
+}
+
+// Expr is a linear expression whose terms have units. The zero value is the
+// empty expression, which is compatible with any dimension.
+//
+// Expressions are immutable: all methods return new expressions.
+type Expr struct {
+	expr golpa.Expr // in the dimension's base unit
+	dim  Dimension  // nil for the empty expression
+	err  error
+}
+
+// Term returns the expression coef * v, whose dimension is the product of
+// both units' dimensions.
+func Term(coef Quantity, v *Variable) Expr {
+	return Expr{
+		expr: golpa.Term(coef.Value*coef.Unit.Factor()*v.unit.Factor(), v.Variable),
+		dim:  coef.Unit.Dimension().combine(v.unit.Dimension(), 1),
+	}
+}
+
+// Sum returns the sum of the variables, which must all have the same
+// dimension.
+func Sum(vars ...*Variable) Expr {
+	var e Expr
+	for _, v := range vars {
+		e = e.Plus(Term(Q(1, One), v))
+	}
+	return e
+}
+
+// Plus returns the sum of both expressions. If their dimensions differ, the
+// error is returned when the expression is used.
+func (e Expr) Plus(other Expr) Expr {
+	out := Expr{expr: e.expr.Plus(other.expr), dim: e.dim, err: e.err}
+	if out.err == nil {
+		out.err = other.err
+	}
+	switch {
+	case out.dim == nil:
+		out.dim = other.dim
+	case other.dim != nil && !out.dim.Equal(other.dim) && out.err == nil:
+		out.err = ErrDimensionMismatch{Want: out.dim, Got: other.dim}
+	}
+	return out
+}
+
+// PlusConstant returns the expression with the given quantity added.
+func (e Expr) PlusConstant(q Quantity) Expr {
+	return e.Plus(Expr{
+		expr: golpa.Expr{}.PlusConstant(q.Value * q.Unit.Factor()),
+		dim:  q.Unit.Dimension(),
+	})
+}
+
+// Scale returns the expression multiplied by a dimensionless factor.
+func (e Expr) Scale(factor float64) Expr {
+	e.expr = e.expr.Scale(factor)
+	return e
+}
+
+// Dimension returns the dimension of the expression, which is nil for the
+// empty expression.
+func (e Expr) Dimension() Dimension {
+	return e.dim
+}
+
+// Err returns the first dimension mismatch found while building the
+// expression.
+func (e Expr) Err() error {
+	return e.err
+}
+
+// Model adds unit-aware variables and constraints to a golpa model.
+type Model struct {
+	*golpa.Model
+}
+
+// New returns a unit-aware view of the model.
+func New(model *golpa.Model) *Model {
+	return &Model{Model: model}
+}
+
+// AddVariable adds a variable measured in the given unit, with bounds in
+// that unit and no objective function coefficient.
+func (m *Model) AddVariable(name string, typ golpa.VariableType, unit Unit, lower, upper float64) (*Variable, error) {
+	v, err := m.AddDefinedVariable(name, typ, 0, lower, upper)
+	if err != nil {
+		return nil, err
+	}
+	return &Variable{Variable: v, unit: unit}, nil
+}
+
+// Missing bounds for AddConstraint.
+var (
+	NoLower = Quantity{Value: math.Inf(-1)}
+	NoUpper = Quantity{Value: math.Inf(1)}
+)
+
+// AddConstraint adds the constraint lower <= e <= upper. The bounds must
+// have the expression's dimension, unless they are infinite, in which case
+// their unit may be nil, like for NoLower and NoUpper.
+func (m *Model) AddConstraint(lower Quantity, e Expr, upper Quantity) (*golpa.Constraint, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	bound := func(q Quantity) (float64, error) {
+		if math.IsInf(q.Value, 0) && q.Unit == nil {
+			return q.Value, nil
+		}
+		if e.dim != nil && !e.dim.Equal(q.Unit.Dimension()) {
+			return 0, ErrDimensionMismatch{Want: e.dim, Got: q.Unit.Dimension()}
+		}
+		return q.Value * q.Unit.Factor(), nil
+	}
+	lo, err := bound(lower)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := bound(upper)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.AddExprConstraint(lo, hi, e.expr)
+}
+
+// SetObjective sets the model's objective function, in the base unit of the
+// expression's dimension.
+func (m *Model) SetObjective(e Expr) error {
+	if e.err != nil {
+		return e.err
+	}
+	return m.SetObjectiveExpr(e.expr)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package units
+
+import (
+	"math"
+	"testing"
+
+	"github.com/costela/golpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 0.000001
+
+func TestConversion(t *testing.T) {
+	value, err := Q(2.5, Tonne).In(Kilogram)
+	require.NoError(t, err)
+	assert.InDelta(t, 2500, value, delta)
+
+	value, err = Q(3, Liter).In(CubicMeter)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.003, value, delta)
+
+	_, err = Q(1, Meter).In(Kilogram)
+	assert.ErrorAs(t, err, &ErrDimensionMismatch{})
+
+	eurPerTonne := Per(Base("EUR", "money"), Tonne)
+	assert.True(t, Times(eurPerTonne, Kilogram).Dimension().Equal(Dimension{"money": 1}))
+	assert.Equal(t, "mass^-1 money", eurPerTonne.Dimension().String())
+}
+
+func TestModel(t *testing.T) {
+	model, err := golpa.NewModel("transport", golpa.Maximize)
+	require.NoError(t, err)
+	m := New(model)
+
+	// sand is loaded in kg, gravel in t, onto a truck carrying 2 t
+	sand, err := m.AddVariable("sand", golpa.ContinuousVariable, Kilogram, 0, math.Inf(1))
+	require.NoError(t, err)
+	gravel, err := m.AddVariable("gravel", golpa.ContinuousVariable, Tonne, 0, 0.5)
+	require.NoError(t, err)
+
+	_, err = m.AddConstraint(NoLower, Sum(sand, gravel), Q(2, Tonne))
+	require.NoError(t, err)
+
+	eur := Base("EUR", "money")
+	profit := Term(Q(10, Per(eur, Tonne)), sand).Plus(Term(Q(0.02, Per(eur, Kilogram)), gravel))
+	require.NoError(t, m.SetObjective(profit))
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	got, err := gravel.Value(res).In(Kilogram)
+	require.NoError(t, err)
+	assert.InDelta(t, 500, got, delta)
+	assert.InDelta(t, 1500, sand.Value(res).Value, delta)
+	assert.InDelta(t, 25, res.ObjectiveValue(), delta)
+}
+
+func TestDimensionMismatch(t *testing.T) {
+	model, err := golpa.NewModel("test", golpa.Minimize)
+	require.NoError(t, err)
+	m := New(model)
+
+	weight, err := m.AddVariable("weight", golpa.ContinuousVariable, Kilogram, 0, math.Inf(1))
+	require.NoError(t, err)
+	distance, err := m.AddVariable("distance", golpa.ContinuousVariable, Kilometer, 0, math.Inf(1))
+	require.NoError(t, err)
+
+	_, err = m.AddConstraint(NoLower, Sum(weight, distance), Q(10, Kilogram))
+	assert.ErrorAs(t, err, &ErrDimensionMismatch{})
+
+	_, err = m.AddConstraint(NoLower, Sum(weight), Q(10, Meter))
+	assert.ErrorAs(t, err, &ErrDimensionMismatch{})
+
+	assert.Error(t, m.SetObjective(Sum(weight).PlusConstant(Q(1, Second))))
+	assert.Equal(t, 0, model.ConstraintCount())
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"syscall"
+	"time"
+)
+
+// resourceUsage returns the CPU time used by the calling thread and the
+// peak resident set size of the process in bytes.
+func resourceUsage() (cpu time.Duration, peakRSS int64) {
+	var thread, self syscall.Rusage
+	if syscall.Getrusage(syscall.RUSAGE_THREAD, &thread) == nil {
+		cpu = time.Duration(thread.Utime.Nano() + thread.Stime.Nano())
+	}
+	if syscall.Getrusage(syscall.RUSAGE_SELF, &self) == nil {
+		peakRSS = self.Maxrss * 1024
+	}
+	return cpu, peakRSS
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import "math"
+
+// validateTerms checks that the given terms can safely be passed to
+// lp_solve, which does not check the column indices it is given and
+// silently corrupts the model on non-finite coefficients.
+// The caller must hold at least the model's read lock.
+func (model *Model) validateTerms(vars []*Variable, coefs []float64) error {
+	if len(vars) != len(coefs) {
+		return ErrDimensionMismatch{Got: len(coefs), Want: len(vars)}
+	}
+
+	for i, v := range vars {
+		if v == nil || v.model != model || v.index < 0 || v.index >= len(model.vars) {
+			return ErrInvalidVariable{Position: i, Model: model.name()}
+		}
+		if math.IsNaN(coefs[i]) || math.IsInf(coefs[i], 0) {
+			return ErrInvalidCoefficient{Variable: model.colName(v.index + 1), Index: v.index, Value: coefs[i]}
+		}
+	}
+
+	return nil
+}
+
+// validateBounds checks that the given bounds form a valid range. Since
+// lp_solve treats all infinite bounds as unbounded, a range between two
+// equal infinities would silently become the whole line.
+func validateBounds(lower, upper float64) error {
+	if math.IsNaN(lower) || math.IsNaN(upper) || lower > upper || math.IsInf(lower, 1) || math.IsInf(upper, -1) {
+		return ErrInvalidBounds{Lower: lower, Upper: upper}
+	}
+
+	return nil
+}
@@ -17,19 +17,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package golpa
 
-// #cgo CFLAGS: -I/usr/include/lpsolve/
-// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
 // #include <lp_lib.h>
 // #include <stdlib.h>
 import "C"
 
 import (
+	"fmt"
 	"math"
 )
 
+// Variable is a variable of a model. Once removed with Model.RemoveVariable,
+// its methods return zero values and changing it has no effect.
 type Variable struct {
 	model *Model
-	index int
+	index int // -1 once removed
 }
 
 type VariableType int
@@ -47,17 +48,30 @@ func (v *Variable) Name() string {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
-	return C.GoString(C.get_col_name(v.model.prob, C.int(v.index+1)))
+	if v.index < 0 {
+		return ""
+	}
+	return v.model.colName(v.index + 1)
 }
 
 // SetType sets the type of a variable to either:
 //    - ContinuousVariable
 //    - Integervariable
 //    - BinaryVariable
+//
+// The type can be changed at any time, including after adding constraints
+// and after solving; the next solve uses the new type. Setting the type to
+// BinaryVariable also sets the variable's bounds to [0, 1], while the other
+// types keep the current bounds. Since lp_solve does not distinguish them,
+// integer variables with bounds [0, 1] are reported as BinaryVariable.
 func (v *Variable) SetType(vartype VariableType) {
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	if v.index < 0 {
+		return
+	}
+
 	switch vartype {
 	case ContinuousVariable:
 		C.set_int(v.model.prob, C.int(v.index+1), C.FALSE)
@@ -75,35 +89,43 @@ func (v *Variable) Type() VariableType {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
-	if C.is_binary(v.model.prob, C.int(v.index+1)) == C.TRUE {
-		return BinaryVariable
-	} else if C.is_int(v.model.prob, C.int(v.index+1)) == C.TRUE {
-		return IntegerVariable
-	} else {
+	if v.index < 0 {
 		return ContinuousVariable
 	}
+	return v.model.colType(v.index + 1)
 }
 
 // SetBounds sets the boundaries for the given variable.
-// To set a bound to infinity, pass math.Inf(1) or math.Inf(-1). The
-// signal of the infinity is ignored, as the lower and upper bounds are
-// always assumed to be the negative and positive infinities,
-// respectively.
+// To remove a bound, pass math.Inf(-1) or math.Inf(1), respectively.
+// Invalid bounds (see ErrInvalidBounds) are logged and otherwise ignored.
 func (v *Variable) SetBounds(lower, upper float64) {
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	if v.index < 0 {
+		return
+	}
+	if err := validateBounds(lower, upper); err != nil {
+		v.model.logger.Print(fmt.Sprintf("ignoring bounds of %q: %v", v.model.colName(v.index+1), err))
+		return
+	}
+	v.model.setColBounds(v.index+1, lower, upper)
+}
+
+// setColBounds sets the bounds of the given column. The caller must hold the
+// model's write lock.
+func (model *Model) setColBounds(col int, lower, upper float64) {
 	switch {
 	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
+		C.set_unbounded(model.prob, C.int(col))
 	case math.IsInf(lower, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
-		C.set_upbo(v.model.prob, C.int(v.index+1), C.double(upper))
+		C.set_unbounded(model.prob, C.int(col))
+		C.set_upbo(model.prob, C.int(col), C.double(upper))
 	case math.IsInf(upper, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
-		C.set_lowbo(v.model.prob, C.int(v.index+1), C.double(lower))
+		C.set_unbounded(model.prob, C.int(col))
+		C.set_lowbo(model.prob, C.int(col), C.double(lower))
 	default:
-		C.set_bounds(v.model.prob, C.int(v.index+1), C.double(lower), C.double(upper))
+		C.set_bounds(model.prob, C.int(col), C.double(lower), C.double(upper))
 	}
 }
 
@@ -112,26 +134,26 @@ func (v *Variable) Bounds() (lower, upper float64) {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
-	lower = float64(C.get_lowbo(v.model.prob, C.int(v.index+1)))
-	upper = float64(C.get_upbo(v.model.prob, C.int(v.index+1)))
-
-	inf := float64(C.get_infinite(v.model.prob))
-
-	if lower == -inf {
-		lower = math.Inf(-1)
-	}
-	if upper == inf {
-		upper = math.Inf(1)
+	if v.index < 0 {
+		return 0, 0
 	}
-	return
+	return v.model.colBounds(v.index + 1)
 }
 
 // SetObjectiveCoefficient sets the coefficient for this variable in
-// the objective function.
+// the objective function. Coefficients which are not finite are logged and
+// otherwise ignored.
 func (v *Variable) SetObjectiveCoefficient(coef float64) {
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	if v.index < 0 {
+		return
+	}
+	if math.IsNaN(coef) || math.IsInf(coef, 0) {
+		v.model.logger.Print(fmt.Sprintf("ignoring objective coefficient of %q: not finite: %g", v.model.colName(v.index+1), coef))
+		return
+	}
 	C.set_mat(v.model.prob, C.int(0), C.int(v.index+1), C.REAL(coef))
 }
 
@@ -141,5 +163,43 @@ func (v *Variable) Coefficient() float64 {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
+	if v.index < 0 {
+		return 0
+	}
 	return float64(C.get_mat(v.model.prob, C.int(0), C.int(v.index+1)))
 }
+
+// colName returns the name of the given column. The caller must hold at least
+// the model's read lock.
+func (model *Model) colName(col int) string {
+	return C.GoString(C.get_col_name(model.prob, C.int(col)))
+}
+
+// colType returns the type of the given column. The caller must hold at least
+// the model's read lock.
+func (model *Model) colType(col int) VariableType {
+	if C.is_binary(model.prob, C.int(col)) == C.TRUE {
+		return BinaryVariable
+	} else if C.is_int(model.prob, C.int(col)) == C.TRUE {
+		return IntegerVariable
+	} else {
+		return ContinuousVariable
+	}
+}
+
+// colBounds returns the bounds of the given column. The caller must hold at
+// least the model's read lock.
+func (model *Model) colBounds(col int) (lower, upper float64) {
+	lower = float64(C.get_lowbo(model.prob, C.int(col)))
+	upper = float64(C.get_upbo(model.prob, C.int(col)))
+
+	inf := float64(C.get_infinite(model.prob))
+
+	if lower == -inf {
+		lower = math.Inf(-1)
+	}
+	if upper == inf {
+		upper = math.Inf(1)
+	}
+	return
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import "math"
+
+// MaxViolation returns the largest absolute amount by which the solution in
+// this result violates a constraint or variable bound, or 0 if it satisfies
+// all of them exactly. Solvers accept small violations within their
+// feasibility tolerances; this allows rejecting solutions whose violations
+// are too large for the caller's purposes.
+func (res SolveResult) MaxViolation() float64 {
+	data := res.model.readData()
+
+	values := make([]float64, len(data.vars))
+	for i, v := range res.model.Variables() {
+		values[i] = res.scaledValue(v)
+	}
+
+	worst := 0.0
+	violation := func(value, lower, upper float64) {
+		worst = math.Max(worst, math.Max(lower-value, value-upper))
+	}
+
+	for i, v := range data.vars {
+		violation(values[i], v.lower, v.upper)
+	}
+	for _, c := range data.cons {
+		activity := 0.0
+		for j, col := range c.cols {
+			activity += c.coefs[j] * values[col]
+		}
+		violation(activity, c.lower, c.upper)
+	}
+
+	return worst
+}
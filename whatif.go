@@ -0,0 +1,71 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MarginalValue reports how much the objective value would improve if the
+// given constraint were removed: the model is solved as is and again with
+// the constraint relaxed, starting from the first solve's basis. The
+// result is positive if the constraint restricts the objective value and
+// +Inf if the model is unbounded without it.
+//
+// The constraint's bounds are restored afterwards, but results of earlier
+// solves of the model are invalidated.
+func (model *Model) MarginalValue(c *Constraint, opts ...SolveOption) (float64, error) {
+	if c.model != model || c.index < 0 {
+		return 0, fmt.Errorf("constraint does not belong to model %q", model.Name())
+	}
+
+	res, err := model.Solve(opts...)
+	if err != nil {
+		return 0, err
+	}
+	base := res.ObjectiveValue()
+
+	lower, upper := c.Bounds()
+	c.SetBounds(math.Inf(-1), math.Inf(1))
+	defer c.SetBounds(lower, upper)
+
+	res, err = model.Solve(opts...)
+	if errors.Is(err, ErrModelUnbounded) {
+		return math.Inf(1), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	relaxed := res.ObjectiveValue()
+
+	maximize := model.Direction() == Maximize
+	var o solveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.direction != nil {
+		maximize = *o.direction == Maximize
+	}
+
+	if maximize {
+		return relaxed - base, nil
+	}
+	return base - relaxed, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import "unsafe"
+
+// Workspace holds memory reused across calls to SolveReusing, to avoid
+// allocations when solving many models of the same size. A workspace must
+// not be used by more than one solve at a time.
+type Workspace struct {
+	res       SolveResult
+	values    []float64
+	objective float64
+}
+
+// SolveReusing is like Solve, but reuses the given workspace's memory for
+// the result, which is invalidated by the next solve using the same
+// workspace.
+//
+// Unlike results returned by Solve, the variable values and the objective
+// value in the result are read from the solver all at once, making repeated
+// calls to Value cheaper. They are not affected by subsequent solves of the
+// model.
+func (model *Model) SolveReusing(ws *Workspace, opts ...SolveOption) (*SolveResult, error) {
+	return model.Solve(append(opts, func(o *solveOptions) { o.workspace = ws })...)
+}
+
+// result returns the workspace's result, reset for a new solve.
+func (ws *Workspace) result() *SolveResult {
+	ws.res = SolveResult{values: ws.values[:0]}
+	return &ws.res
+}
+
+// readValues copies all variable values and the objective value from the
+// solver into the workspace's result.
+// The caller must hold at least the model's read lock.
+func (ws *Workspace) readValues(model *Model) {
+	n := len(model.vars)
+	if cap(ws.values) < n {
+		ws.values = make([]float64, n)
+	}
+	ws.values = ws.values[:n]
+	if n > 0 {
+		C.get_variables(model.prob, (*C.REAL)(unsafe.Pointer(&ws.values[0])))
+	}
+	ws.res.values = ws.values
+	ws.objective = float64(C.get_objective(model.prob))
+	ws.res.objective = &ws.objective
+}